@@ -0,0 +1,46 @@
+// Package angle implements the handful of operations needed to work with
+// planar headings, i.e. elements of SO(2) represented in radians.
+//
+// Headings are normalized to [0, 2π), while differences between headings are
+// signed and normalized to [-π, π).
+package angle
+
+import "math"
+
+const twoPi = 2 * math.Pi
+
+// Normalize wraps a to the range [0, 2π).
+func Normalize(a float64) float64 {
+	a = math.Mod(a, twoPi)
+	if a < 0 {
+		a += twoPi
+	}
+	// Adding 2π to a tiny negative number rounds to 2π.
+	if a >= twoPi {
+		a = 0
+	}
+	return a
+}
+
+// NormalizeSigned wraps a to the range [-π, π).
+func NormalizeSigned(a float64) float64 {
+	return Normalize(a+math.Pi) - math.Pi
+}
+
+// Diff returns the shortest signed rotation from one heading to another, in
+// the range [-π, π). Positive values are counterclockwise.
+func Diff(from, to float64) float64 {
+	return NormalizeSigned(to - from)
+}
+
+// Distance is the SO(2) distance between two headings, i.e. the absolute
+// value of the shortest rotation between them, in the range [0, π].
+func Distance(a, b float64) float64 {
+	return math.Abs(Diff(a, b))
+}
+
+// Lerp interpolates from one heading to another along the shortest arc. A t
+// of 0 returns from and a t of 1 returns to, both normalized to [0, 2π).
+func Lerp(from, to, t float64) float64 {
+	return Normalize(from + t*Diff(from, to))
+}
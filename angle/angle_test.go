@@ -0,0 +1,158 @@
+package angle
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+	"testing/quick"
+)
+
+const tolerance = 1e-9
+
+// angles generates float64 arguments for quick.Check in a range where a
+// float64 still has plenty of precision after wrapping, including negative
+// angles and angles several turns away from zero.
+var angles = &quick.Config{
+	MaxCount: 10000,
+	Values: func(args []reflect.Value, rng *rand.Rand) {
+		for i := range args {
+			args[i] = reflect.ValueOf((rng.Float64()*2 - 1) * 20 * math.Pi)
+		}
+	},
+}
+
+// sameHeading returns true if a and b point in the same direction.
+func sameHeading(a, b float64) bool {
+	return math.Abs(math.Sin(a)-math.Sin(b)) < tolerance && math.Abs(math.Cos(a)-math.Cos(b)) < tolerance
+}
+
+func TestNormalize(t *testing.T) {
+	var tests = []struct {
+		in, exp float64
+	}{
+		{0, 0},
+		{math.Pi, math.Pi},
+		{2 * math.Pi, 0},
+		{-math.Pi / 2, 3 * math.Pi / 2},
+		{-2 * math.Pi, 0},
+		{5 * math.Pi, math.Pi},
+		{-1e-17, 0},
+	}
+	for _, tc := range tests {
+		if got := Normalize(tc.in); math.Abs(got-tc.exp) > tolerance {
+			t.Errorf("Normalize(%f) = %f, expected %f", tc.in, got, tc.exp)
+		}
+	}
+}
+
+func TestNormalizeProperties(t *testing.T) {
+	inRange := func(a float64) bool {
+		n := Normalize(a)
+		return 0 <= n && n < 2*math.Pi && sameHeading(a, n)
+	}
+	if err := quick.Check(inRange, angles); err != nil {
+		t.Error(err)
+	}
+
+	signedInRange := func(a float64) bool {
+		n := NormalizeSigned(a)
+		return -math.Pi <= n && n < math.Pi && sameHeading(a, n)
+	}
+	if err := quick.Check(signedInRange, angles); err != nil {
+		t.Error(err)
+	}
+
+	idempotent := func(a float64) bool {
+		return Normalize(Normalize(a)) == Normalize(a)
+	}
+	if err := quick.Check(idempotent, angles); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	var tests = []struct {
+		name     string
+		from, to float64
+		exp      float64
+	}{
+		{"90 degree", 0, math.Pi / 2, math.Pi / 2},
+		{"-90 degree", 0, -math.Pi / 2, -math.Pi / 2},
+		{"-90 degree, 45 to neg45", math.Pi / 4, -math.Pi / 4, -math.Pi / 2},
+		{"90 degree, neg45 to 45", -math.Pi / 4, math.Pi / 4, math.Pi / 2},
+		{"across zero", 2*math.Pi - 0.1, 0.1, 0.2},
+		{"across zero backwards", 0.1, -0.1, -0.2},
+		{"half turn", 0, math.Pi, -math.Pi},
+		{"fractions of a degree", 0, 0.001, 0.001},
+		{"several turns", -7 * math.Pi, 7*math.Pi + 0.5, 0.5},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := Diff(tc.from, tc.to); math.Abs(got-tc.exp) > tolerance {
+				t.Errorf("Diff(%f, %f) = %f, expected %f", tc.from, tc.to, got, tc.exp)
+			}
+		})
+	}
+}
+
+func TestDiffProperties(t *testing.T) {
+	shortest := func(from, to float64) bool {
+		d := Diff(from, to)
+		return -math.Pi <= d && d < math.Pi && sameHeading(from+d, to)
+	}
+	if err := quick.Check(shortest, angles); err != nil {
+		t.Error(err)
+	}
+
+	antisymmetric := func(a, b float64) bool {
+		d := Diff(a, b)
+		// A half turn is -π in both directions.
+		return math.Abs(d+math.Pi) < tolerance || math.Abs(d+Diff(b, a)) < tolerance
+	}
+	if err := quick.Check(antisymmetric, angles); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestDistanceProperties(t *testing.T) {
+	metric := func(a, b, c float64) bool {
+		dab, dba := Distance(a, b), Distance(b, a)
+		return 0 <= dab && dab <= math.Pi &&
+			math.Abs(dab-dba) < tolerance &&
+			Distance(a, a) == 0 &&
+			Distance(a, c) <= dab+Distance(b, c)+tolerance
+	}
+	if err := quick.Check(metric, angles); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestLerp(t *testing.T) {
+	if got := Lerp(2*math.Pi-0.2, 0.2, 0.5); math.Abs(got) > tolerance {
+		t.Errorf("halfway across zero should be 0, got %f", got)
+	}
+	if got := Lerp(0, 3*math.Pi/2, 0.5); math.Abs(got-7*math.Pi/4) > tolerance {
+		t.Errorf("should interpolate along the shortest arc, got %f", got)
+	}
+}
+
+func TestLerpProperties(t *testing.T) {
+	endpoints := func(from, to float64) bool {
+		return sameHeading(Lerp(from, to, 0), from) && sameHeading(Lerp(from, to, 1), to)
+	}
+	if err := quick.Check(endpoints, angles); err != nil {
+		t.Error(err)
+	}
+
+	alongShortestArc := func(from, to, s float64) bool {
+		frac := math.Abs(math.Mod(s, 1))
+		l := Lerp(from, to, frac)
+		return 0 <= l && l < 2*math.Pi &&
+			math.Abs(Distance(from, l)-frac*Distance(from, to)) < tolerance &&
+			math.Abs(Distance(l, to)-(1-frac)*Distance(from, to)) < tolerance
+	}
+	if err := quick.Check(alongShortestArc, angles); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,172 @@
+// Package bench collects the results of repeated planner runs and summarizes
+// them per problem and planner, to compare planners and settings.
+package bench
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/hdhauk/enae788v/planner"
+)
+
+// Run is the outcome of a single planner run.
+type Run struct {
+	Problem      int    // index of the problem in its config
+	Name         string // name of the problem
+	Planner      string // planner and settings
+	Seed         int64
+	Solved       bool
+	Reason       string        // why no solution was found
+	FirstSolved  time.Duration // time to the first solution
+	Elapsed      time.Duration // time until the planner returned
+	Cost         float64       // cost of the path
+	Length       float64       // distance in the plane along the path
+	Nodes        int           // vertices in the tree
+	Iterations   int
+	StateChecks  int
+	MotionChecks int
+}
+
+// NewRun records the solution of a planner and the error it returned.
+func NewRun(problem int, name, plannerName string, seed int64, sol *planner.Solution, err error) Run {
+	r := Run{Problem: problem, Name: name, Planner: plannerName, Seed: seed, Solved: err == nil}
+	if err != nil {
+		r.Reason = err.Error()
+	}
+	if sol == nil {
+		return r
+	}
+	r.Elapsed = sol.Stats.Elapsed
+	r.Nodes = len(sol.Tree) + 1
+	r.Iterations = sol.Stats.Iterations
+	r.StateChecks = sol.Stats.StateChecks
+	r.MotionChecks = sol.Stats.MotionChecks
+	if !r.Solved {
+		return r
+	}
+	r.Cost = sol.Cost
+	r.Length = PathLength(sol.Path)
+
+	// Planners that keep improving record when the first solution was found.
+	r.FirstSolved = r.Elapsed
+	for _, p := range sol.Progress {
+		if !math.IsInf(p.Cost, 1) {
+			r.FirstSolved = p.Elapsed
+			break
+		}
+	}
+	return r
+}
+
+// PathLength returns the distance in the plane along a path, through the
+// intermediate states of its motions.
+func PathLength(path []*planner.Motion) float64 {
+	states := planner.PathStates(path)
+	var length float64
+	for i := 1; i < len(states); i++ {
+		a, b := planner.Pose(states[i-1]), planner.Pose(states[i])
+		length += math.Hypot(b.X-a.X, b.Y-a.Y)
+	}
+	return length
+}
+
+// Stat summarizes a sample. The fields are NaN for an empty sample.
+type Stat struct {
+	N                      int
+	Mean, Median, P10, P90 float64
+}
+
+// NewStat summarizes a sample. Percentiles interpolate linearly between the
+// closest ranks.
+func NewStat(sample []float64) Stat {
+	s := Stat{N: len(sample), Mean: math.NaN(), Median: math.NaN(), P10: math.NaN(), P90: math.NaN()}
+	if len(sample) == 0 {
+		return s
+	}
+	sorted := append([]float64(nil), sample...)
+	sort.Float64s(sorted)
+	var sum float64
+	for _, x := range sorted {
+		sum += x
+	}
+	s.Mean = sum / float64(len(sorted))
+	s.Median = Percentile(sorted, 50)
+	s.P10 = Percentile(sorted, 10)
+	s.P90 = Percentile(sorted, 90)
+	return s
+}
+
+// Percentile returns the p-th percentile of a sorted, non-empty sample.
+func Percentile(sorted []float64, p float64) float64 {
+	rank := p / 100 * float64(len(sorted)-1)
+	lo := int(math.Floor(rank))
+	if lo >= len(sorted)-1 {
+		return sorted[len(sorted)-1]
+	}
+	return sorted[lo] + (rank-float64(lo))*(sorted[lo+1]-sorted[lo])
+}
+
+// Summary summarizes the runs of a planner on a problem. The time to the
+// first solution, cost and length are over the solved runs, and the node and
+// collision check counts over every run.
+type Summary struct {
+	Problem     int
+	Name        string
+	Planner     string
+	Runs        int
+	Solved      int
+	FirstSolved Stat // seconds
+	Cost        Stat
+	Length      Stat
+	Nodes       Stat
+	Checks      Stat // state and motion checks
+}
+
+// SuccessRate returns the fraction of solved runs.
+func (s Summary) SuccessRate() float64 {
+	if s.Runs == 0 {
+		return 0
+	}
+	return float64(s.Solved) / float64(s.Runs)
+}
+
+// Summarize groups runs by problem and planner, in the order each pair first
+// appears.
+func Summarize(runs []Run) []Summary {
+	type key struct {
+		problem int
+		planner string
+	}
+	var keys []key
+	groups := map[key][]Run{}
+	for _, r := range runs {
+		k := key{r.Problem, r.Planner}
+		if _, ok := groups[k]; !ok {
+			keys = append(keys, k)
+		}
+		groups[k] = append(groups[k], r)
+	}
+
+	summaries := make([]Summary, len(keys))
+	for i, k := range keys {
+		group := groups[k]
+		var firstSolved, cost, length, nodes, checks []float64
+		s := Summary{Problem: k.problem, Name: group[0].Name, Planner: k.planner, Runs: len(group)}
+		for _, r := range group {
+			nodes = append(nodes, float64(r.Nodes))
+			checks = append(checks, float64(r.StateChecks+r.MotionChecks))
+			if !r.Solved {
+				continue
+			}
+			s.Solved++
+			firstSolved = append(firstSolved, r.FirstSolved.Seconds())
+			cost = append(cost, r.Cost)
+			length = append(length, r.Length)
+		}
+		s.FirstSolved, s.Cost, s.Length = NewStat(firstSolved), NewStat(cost), NewStat(length)
+		s.Nodes, s.Checks = NewStat(nodes), NewStat(checks)
+		summaries[i] = s
+	}
+	return summaries
+}
@@ -0,0 +1,118 @@
+package bench
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hdhauk/enae788v/planner"
+)
+
+// assert fails the test if the condition is false.
+func assert(tb testing.TB, condition bool, msg string, v ...interface{}) {
+	if !condition {
+		_, file, line, _ := runtime.Caller(1)
+		fmt.Printf("\033[31m%s:%d: "+msg+"\033[39m\n\n", append([]interface{}{filepath.Base(file), line}, v...)...)
+		tb.FailNow()
+	}
+}
+
+// ok fails the test if an err is not nil.
+func ok(tb testing.TB, err error) {
+	if err != nil {
+		_, file, line, _ := runtime.Caller(1)
+		fmt.Printf("\033[31m%s:%d: unexpected error: %s\033[39m\n\n", filepath.Base(file), line, err.Error())
+		tb.FailNow()
+	}
+}
+
+// equals fails the test if exp is not equal to act.
+func equals(tb testing.TB, exp, act interface{}) {
+	if !reflect.DeepEqual(exp, act) {
+		_, file, line, _ := runtime.Caller(1)
+		fmt.Printf("\033[31m%s:%d:\n\n\texp: %#v\n\n\tgot: %#v\033[39m\n\n", filepath.Base(file), line, exp, act)
+		tb.FailNow()
+	}
+}
+
+func TestNewStat(t *testing.T) {
+	s := NewStat([]float64{10, 1, 3, 4, 2})
+	equals(t, 5, s.N)
+	for _, c := range []struct{ exp, act float64 }{{4, s.Mean}, {3, s.Median}, {1.4, s.P10}, {7.6, s.P90}} {
+		assert(t, math.Abs(c.exp-c.act) < 1e-9, "expected %v, got %v", c.exp, c.act)
+	}
+	equals(t, Stat{N: 1, Mean: 7, Median: 7, P10: 7, P90: 7}, NewStat([]float64{7}))
+	empty := NewStat(nil)
+	assert(t, empty.N == 0 && math.IsNaN(empty.Mean) && math.IsNaN(empty.P90), "expected NaN for an empty sample, got %+v", empty)
+}
+
+func TestNewRun(t *testing.T) {
+	a, b, c := planner.Point{X: 0, Y: 0}, planner.Point{X: 3, Y: 4}, planner.Point{X: 3, Y: 5}
+	first := &planner.Motion{From: a, To: b, Cost: 5}
+	second := &planner.Motion{From: b, To: c, Cost: 1}
+	sol := &planner.Solution{
+		Path:     []*planner.Motion{first, second},
+		Tree:     []*planner.Motion{first, second, {From: a, To: c}},
+		Cost:     6,
+		Progress: []planner.Progress{{Iteration: 1, Elapsed: time.Second, Cost: math.Inf(1)}, {Iteration: 2, Elapsed: 2 * time.Second, Cost: 6}},
+		Stats:    planner.Stats{Iterations: 2, Elapsed: 3 * time.Second, StateChecks: 1, MotionChecks: 2},
+	}
+	r := NewRun(1, "test", "sst", 7, sol, nil)
+	equals(t, Run{
+		Problem: 1, Name: "test", Planner: "sst", Seed: 7, Solved: true,
+		FirstSolved: 2 * time.Second, Elapsed: 3 * time.Second, Cost: 6, Length: 6,
+		Nodes: 4, Iterations: 2, StateChecks: 1, MotionChecks: 2,
+	}, r)
+
+	r = NewRun(1, "test", "rrt", 7, &planner.Solution{Stats: planner.Stats{Iterations: 9}}, errors.New("iteration limit reached"))
+	assert(t, !r.Solved, "expected no solution")
+	equals(t, "iteration limit reached", r.Reason)
+	equals(t, 1, r.Nodes)
+	equals(t, 9, r.Iterations)
+}
+
+func TestSummarize(t *testing.T) {
+	runs := []Run{
+		{Problem: 0, Planner: "rrt", Solved: true, Cost: 2, Nodes: 10, StateChecks: 1, MotionChecks: 9},
+		{Problem: 0, Planner: "sst", Solved: false, Nodes: 30},
+		{Problem: 0, Planner: "rrt", Solved: false, Nodes: 20},
+		{Problem: 1, Planner: "rrt", Solved: true, Cost: 4, Nodes: 5},
+	}
+	s := Summarize(runs)
+	equals(t, 3, len(s))
+	equals(t, "rrt", s[0].Planner)
+	equals(t, 2, s[0].Runs)
+	equals(t, 0.5, s[0].SuccessRate())
+	equals(t, 1, s[0].Cost.N)
+	equals(t, 2.0, s[0].Cost.Mean)
+	equals(t, 15.0, s[0].Nodes.Mean)
+	equals(t, 5.0, s[0].Checks.Mean)
+	equals(t, "sst", s[1].Planner)
+	equals(t, 0.0, s[1].SuccessRate())
+	equals(t, 1, s[2].Problem)
+}
+
+func TestWrite(t *testing.T) {
+	s := Summarize([]Run{{Problem: 0, Name: "a", Planner: "rrt", Solved: true, Cost: 2}, {Problem: 0, Name: "a", Planner: "sst"}})
+
+	var buf bytes.Buffer
+	ok(t, WriteCSV(&buf, s))
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	equals(t, 3, len(lines))
+	equals(t, 6+4*len(stats), len(strings.Split(lines[0], ",")))
+	assert(t, strings.HasPrefix(lines[1], "0,a,rrt,1,1,1,"), "unexpected row %q", lines[1])
+	assert(t, strings.HasPrefix(lines[2], "0,a,sst,1,0,0,,,,,"), "unsolved metrics should be empty: %q", lines[2])
+
+	buf.Reset()
+	ok(t, WriteMarkdown(&buf, s))
+	lines = strings.Split(strings.TrimSpace(buf.String()), "\n")
+	equals(t, 4, len(lines))
+	assert(t, strings.HasPrefix(lines[3], "| 0 a | sst | 0/1 | - |"), "unexpected row %q", lines[3])
+}
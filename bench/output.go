@@ -0,0 +1,105 @@
+package bench
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// stats are the summarized metrics in the order they are written.
+var stats = []struct {
+	name string
+	stat func(Summary) Stat
+}{
+	{"first_solution_seconds", func(s Summary) Stat { return s.FirstSolved }},
+	{"cost", func(s Summary) Stat { return s.Cost }},
+	{"length", func(s Summary) Stat { return s.Length }},
+	{"nodes", func(s Summary) Stat { return s.Nodes }},
+	{"checks", func(s Summary) Stat { return s.Checks }},
+}
+
+// WriteCSV writes a row per summary, with the mean, median, 10th and 90th
+// percentile of every metric. Metrics without any solved run are empty.
+func WriteCSV(w io.Writer, summaries []Summary) error {
+	cw := csv.NewWriter(w)
+	header := []string{"problem", "name", "planner", "runs", "solved", "success_rate"}
+	for _, st := range stats {
+		for _, field := range []string{"mean", "median", "p10", "p90"} {
+			header = append(header, st.name+"_"+field)
+		}
+	}
+	cw.Write(header)
+	for _, s := range summaries {
+		row := []string{strconv.Itoa(s.Problem), s.Name, s.Planner, strconv.Itoa(s.Runs), strconv.Itoa(s.Solved), formatFloat(s.SuccessRate())}
+		for _, st := range stats {
+			v := st.stat(s)
+			row = append(row, formatFloat(v.Mean), formatFloat(v.Median), formatFloat(v.P10), formatFloat(v.P90))
+		}
+		cw.Write(row)
+	}
+	cw.Flush()
+	return errors.Wrap(cw.Error(), "could not write summary")
+}
+
+// WriteMarkdown writes a table with a row per summary, with the median and
+// the 10th to 90th percentile range of every metric.
+func WriteMarkdown(w io.Writer, summaries []Summary) error {
+	bw := bufio.NewWriter(w)
+	header := []string{"problem", "planner", "solved"}
+	for _, st := range stats {
+		header = append(header, st.name+" (median, p10-p90)")
+	}
+	fmt.Fprintf(bw, "| %s |\n", strings.Join(header, " | "))
+	fmt.Fprintf(bw, "|%s\n", strings.Repeat(" --- |", len(header)))
+	for _, s := range summaries {
+		name := strconv.Itoa(s.Problem)
+		if s.Name != "" {
+			name += " " + s.Name
+		}
+		row := []string{name, s.Planner, fmt.Sprintf("%d/%d", s.Solved, s.Runs)}
+		for _, st := range stats {
+			v := st.stat(s)
+			if v.N == 0 {
+				row = append(row, "-")
+				continue
+			}
+			row = append(row, fmt.Sprintf("%.4g (%.4g-%.4g)", v.Median, v.P10, v.P90))
+		}
+		fmt.Fprintf(bw, "| %s |\n", strings.Join(row, " | "))
+	}
+	return errors.Wrap(bw.Flush(), "could not write summary")
+}
+
+// WriteRunsCSV writes a row per run.
+func WriteRunsCSV(w io.Writer, runs []Run) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"problem", "name", "planner", "seed", "solved", "reason", "first_solution_seconds", "elapsed_seconds", "cost", "length", "nodes", "iterations", "state_checks", "motion_checks"})
+	for _, r := range runs {
+		cost, length, firstSolved := "", "", ""
+		if r.Solved {
+			cost, length, firstSolved = formatFloat(r.Cost), formatFloat(r.Length), formatFloat(r.FirstSolved.Seconds())
+		}
+		cw.Write([]string{
+			strconv.Itoa(r.Problem), r.Name, r.Planner, strconv.FormatInt(r.Seed, 10),
+			strconv.FormatBool(r.Solved), r.Reason, firstSolved, formatFloat(r.Elapsed.Seconds()),
+			cost, length, strconv.Itoa(r.Nodes), strconv.Itoa(r.Iterations),
+			strconv.Itoa(r.StateChecks), strconv.Itoa(r.MotionChecks),
+		})
+	}
+	cw.Flush()
+	return errors.Wrap(cw.Error(), "could not write runs")
+}
+
+// formatFloat formats x for a csv cell, which is empty for NaN.
+func formatFloat(x float64) string {
+	if math.IsNaN(x) {
+		return ""
+	}
+	return strconv.FormatFloat(x, 'g', 6, 64)
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+
+	"github.com/hdhauk/enae788v/render"
+)
+
+// animateMain animates the robot moving along the path of a result written
+// with -format json, as a GIF or a directory of SVG frames.
+func animateMain(args []string) int {
+	fs := newFlagSet("animate", "[result.json]")
+	configPath := fs.String("c", "hw3/problems.json", "config file with the obstacles and robot of the result")
+	outPath := fs.String("o", "", "output path of the gif, or directory of the svg frames (default stdout for a gif)")
+	format := fs.String("format", "gif", "animation format: gif, or svg for a frame<n>.svg file per frame")
+	opts := render.DefaultAnimationOptions
+	registerRenderOptions(fs, &opts.Options)
+	fs.IntVar(&opts.Frames, "frames", opts.Frames, "frames of the robot moving along the path")
+	fs.IntVar(&opts.TreeFrames, "tree-frames", opts.TreeFrames, "frames of the tree growing before the robot moves, 0 to draw the whole tree throughout")
+	fs.DurationVar(&opts.Delay, "delay", opts.Delay, "time between frames of a gif")
+	if code, run := parse(fs, args, 0, 1); !run {
+		return code
+	}
+	switch {
+	case *format != "gif" && *format != "svg":
+		return fail("animate", exitUsage, errors.Errorf("unknown format %q", *format))
+	case *format == "svg" && *outPath == "":
+		return fail("animate", exitUsage, errors.New("svg frames need an output directory"))
+	case opts.Frames < 1 || opts.TreeFrames < 0:
+		return fail("animate", exitUsage, errors.New("-frames must be positive and -tree-frames not negative"))
+	}
+
+	scene, code := loadScene("animate", *configPath, fs.Arg(0))
+	if code != exitOK {
+		return code
+	}
+	if scene.Solution == nil || len(scene.Solution.Path) == 0 {
+		return fail("animate", exitNoSolution, errors.New("the result has no path to animate"))
+	}
+
+	if *format == "svg" {
+		if err := os.MkdirAll(*outPath, 0755); err != nil {
+			return fail("animate", exitInput, errors.Wrap(err, "could not create frame directory"))
+		}
+		err := render.SVGFrames(scene, opts, func(i int) (io.WriteCloser, error) {
+			f, err := os.Create(filepath.Join(*outPath, fmt.Sprintf("frame%04d.svg", i)))
+			return f, errors.Wrap(err, "could not create frame")
+		})
+		if err != nil {
+			return fail("animate", exitInput, err)
+		}
+		return exitOK
+	}
+
+	f, err := create(*outPath)
+	if err != nil {
+		return fail("animate", exitInput, errors.Wrap(err, "could not create animation"))
+	}
+	if err := render.GIF(f, scene, opts); err != nil {
+		f.Close()
+		return fail("animate", exitInput, err)
+	}
+	if err := f.Close(); err != nil {
+		return fail("animate", exitInput, errors.Wrap(err, "could not close animation"))
+	}
+	return exitOK
+}
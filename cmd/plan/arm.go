@@ -0,0 +1,126 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/hdhauk/enae788v/planner"
+	"github.com/hdhauk/enae788v/render"
+)
+
+// armMain plans for a planar arm in joint space with RRT, and writes the
+// configurations along the path as csv, or the whole run as JSON. The arm can
+// be drawn sweeping along the path, or animated moving along it.
+func armMain(args []string) int {
+	fs := newFlagSet("arm", "")
+	configPath := fs.String("c", "arm/problems.json", "arm config file")
+	pIndex := fs.Int("p", 0, "which problem in config file to solve (0-indexed)")
+	seed := fs.Int64("seed", 0, "seed for the random sampling (default current time)")
+	outPath := fs.String("o", "", "output path for the path csv of joint angles and end effector positions, or the json result (default stdout)")
+	format := fs.String("format", "csv", "output format: csv of the path, or json of the result with the tree")
+	precision := fs.Int("precision", 4, "number of decimals in the path csv")
+	resolution := fs.Float64("resolution", 0.5, "largest distance a point of the arm moves between collision checks along a motion")
+	figurePath := fs.String("figure", "", "output path for a figure of the arm along the path: svg, png or an animated gif (default none)")
+	anim := render.DefaultAnimationOptions
+	registerRenderOptions(fs, &anim.Options)
+	fs.IntVar(&anim.Frames, "frames", anim.Frames, "frames of the arm moving along the path of a gif")
+	var lim limits
+	lim.register(fs)
+	if code, run := parse(fs, args, 0, 0); !run {
+		return code
+	}
+	if *resolution <= 0 || anim.Frames < 1 {
+		return fail("arm", exitUsage, errors.New("-resolution and -frames must be positive"))
+	}
+	if *format != "csv" && *format != "json" {
+		return fail("arm", exitUsage, errors.Errorf("unknown format %q", *format))
+	}
+
+	config, obstacles, err := planner.LoadArmConfig(*configPath)
+	infeasible, isInfeasible := err.(planner.FeasibilityError)
+	if err != nil && !isInfeasible {
+		return fail("arm", exitInput, err)
+	}
+	if *pIndex < 0 || *pIndex >= len(config.Problems) {
+		return fail("arm", exitUsage, errors.Errorf("invalid problem number %d, the config has %d problems", *pIndex, len(config.Problems)))
+	}
+	if err := infeasible.Problem(*pIndex); err != nil {
+		return fail("arm", exitInput, err)
+	}
+	if *seed == 0 {
+		*seed = time.Now().UnixNano()
+	}
+
+	p := config.Problems[*pIndex]
+	checker := planner.ArmChecker{Arm: config.Arm, Obstacles: obstacles, Space: config.ConfigSpace, Resolution: *resolution}
+	rrt := planner.NewArmRRT(p, config.Arm, checker, *seed)
+	rrt.MaxIterations = lim.maxIter
+	ctx, cancel := lim.context()
+	defer cancel()
+	solution, err := rrt.Plan(ctx)
+	err = errors.Wrap(err, "rrt failed")
+
+	// The partial tree is written as JSON and drawn even if no solution was
+	// found.
+	if *format == "json" {
+		result := planner.Result{ArmProblem: &p, Planner: "rrt", Seed: *seed, Solution: solution, Err: err}
+		if err := writeResult(*outPath, *format, result, 0); err != nil {
+			return fail("arm", exitInput, err)
+		}
+	}
+	if *figurePath != "" {
+		scene := render.ArmScene{Space: config.ConfigSpace, Obstacles: obstacles, Arm: config.Arm, Problem: p, Solution: solution}
+		if err := renderArm(*figurePath, scene, anim); err != nil {
+			return fail("arm", exitInput, err)
+		}
+	}
+	if err != nil {
+		return fail("arm", exitNoSolution, err)
+	}
+	if *format == "csv" {
+		if err := writeArmPath(*outPath, config.Arm, solution.Path, *precision); err != nil {
+			return fail("arm", exitInput, err)
+		}
+	}
+	fmt.Fprintf(os.Stderr, "found path of %d motions turning the joints %.3f rad after %d iterations\n", len(solution.Path), solution.Cost, solution.Stats.Iterations)
+	return exitOK
+}
+
+// writeArmPath writes the path csv of an arm to path.
+func writeArmPath(path string, arm planner.Arm, motions []*planner.Motion, precision int) error {
+	f, err := create(path)
+	if err != nil {
+		return errors.Wrap(err, "could not create path file")
+	}
+	if err := planner.WriteArmPath(f, arm, motions, precision); err != nil {
+		f.Close()
+		return err
+	}
+	return errors.Wrap(f.Close(), "could not close path file")
+}
+
+// renderArm draws an arm scene to path, in the format of its extension: an
+// animated gif, a png, or an svg otherwise.
+func renderArm(path string, scene render.ArmScene, opts render.AnimationOptions) error {
+	f, err := create(path)
+	if err != nil {
+		return errors.Wrap(err, "could not create figure")
+	}
+	switch filepath.Ext(path) {
+	case ".gif":
+		err = render.ArmGIF(f, scene, opts)
+	case ".png":
+		err = render.ArmPNG(f, scene, opts.Options)
+	default:
+		err = render.ArmSVG(f, scene, opts.Options)
+	}
+	if err != nil {
+		f.Close()
+		return err
+	}
+	return errors.Wrap(f.Close(), "could not close figure")
+}
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/hdhauk/enae788v/graph"
+	"github.com/hdhauk/enae788v/planner"
+)
+
+// batch holds the flags of the batch command.
+type batch struct {
+	outDir    string
+	format    string
+	seed      int64
+	footprint footprintOptions
+	dijkstra  bool
+	opts      kinodynamicOptions
+}
+
+// batchMain solves every problem in a problem file and writes the results of
+// problem i to problem<i>.txt, or problem<i>.json with -format json, in the
+// output directory. Kinodynamic problems also get their trajectory csv in
+// problem<i>_state.csv, and graph problems their search tree in
+// problem<i>_tree.txt with the text format. The -timeout and -max-iter limits
+// apply to every problem on its own.
+func batchMain(args []string) int {
+	var b batch
+	fs := newFlagSet("batch", "")
+	configPath := fs.String("c", "hw2/problems.json", "config or problem file")
+	mode := fs.String("mode", "auto", "problem type: graph, rrt, kinodynamic or auto, which picks graph for .txt files and kinodynamic for configs with acceleration limits")
+	fs.StringVar(&b.outDir, "out", ".", "directory to write the results to")
+	fs.StringVar(&b.format, "format", "text", formatUsage)
+	fs.Int64Var(&b.seed, "seed", 0, "seed for the random sampling (default current time)")
+	b.footprint = defaultFootprint
+	b.footprint.register(fs)
+	fs.BoolVar(&b.dijkstra, "dijkstra", false, "use a zero heuristic for graph problems, which makes the search equal to Dijkstra")
+	b.opts.register(fs)
+	if code, run := parse(fs, args, 0, 0); !run {
+		return code
+	}
+
+	if *mode == "auto" && strings.HasSuffix(*configPath, ".txt") {
+		*mode = "graph"
+	}
+	if b.seed == 0 {
+		b.seed = time.Now().UnixNano()
+	}
+	if err := b.opts.check(); err != nil {
+		return fail("batch", exitUsage, err)
+	}
+	if err := checkFormat(b.format); err != nil {
+		return fail("batch", exitUsage, err)
+	}
+	if err := os.MkdirAll(b.outDir, 0755); err != nil {
+		return fail("batch", exitInput, errors.Wrap(err, "could not create output directory"))
+	}
+	if *mode == "graph" {
+		return b.graph(*configPath)
+	}
+
+	config, obstacles, robot, err := planner.LoadConfig(*configPath)
+	infeasible, isInfeasible := err.(planner.FeasibilityError)
+	if err != nil && !isInfeasible {
+		return fail("batch", exitInput, err)
+	}
+	if *mode == "auto" {
+		*mode = "rrt"
+		if kinodynamicSpace(config.ConfigSpace) {
+			*mode = "kinodynamic"
+		}
+	}
+	if *mode != "rrt" && *mode != "kinodynamic" {
+		return fail("batch", exitUsage, errors.Errorf("unknown mode %q", *mode))
+	}
+
+	if *mode == "kinodynamic" {
+		if err := b.opts.loadPrimitives(config.ConfigSpace); err != nil {
+			return fail("batch", exitInput, err)
+		}
+	}
+
+	code := exitOK
+	for i, p := range config.Problems {
+		err := infeasible.Problem(i)
+		if err != nil {
+			err = batchError{err}
+		} else if *mode == "rrt" {
+			err = b.rrt(i, p, config.ConfigSpace, obstacles, robot)
+		} else {
+			err = b.kinodynamic(i, p, config.ConfigSpace, obstacles, robot)
+		}
+		if code = batchResult(i, err, code); code == exitInput {
+			return code
+		}
+	}
+	return code
+}
+
+// batchError is a problem that could not be solved, as opposed to results
+// that could not be written.
+type batchError struct {
+	error
+}
+
+// batchResult reports the outcome of problem i and returns the exit code of
+// the batch so far.
+func batchResult(i int, err error, code int) int {
+	switch err.(type) {
+	case nil:
+		fmt.Fprintf(os.Stderr, "problem %d: solved\n", i)
+		return code
+	case batchError:
+		fmt.Fprintf(os.Stderr, "problem %d: %v\n", i, err)
+		return exitNoSolution
+	default:
+		return fail("batch", exitInput, err)
+	}
+}
+
+// path returns the path of an output file of problem i.
+func (b *batch) path(i int, suffix string) string {
+	return filepath.Join(b.outDir, fmt.Sprintf("problem%d%s", i, suffix))
+}
+
+func (b *batch) graph(problemsPath string) int {
+	problems, err := graph.ReadProblems(problemsPath)
+	if err != nil {
+		return fail("batch", exitInput, err)
+	}
+	algorithm := "astar"
+	if b.dijkstra {
+		algorithm = "dijkstra"
+	}
+	code := exitOK
+	for i, p := range problems {
+		search, err := solveGraph(p, b.dijkstra)
+		var werr error
+		if b.format == "json" {
+			werr = writeGraphJSON(b.path(i, ".json"), graph.Result{Problem: p, Algorithm: algorithm, Search: search, Err: err})
+		} else {
+			werr = writeGraphFile(b.path(i, "_tree.txt"), search.SearchTree, graph.WriteSearchTree)
+			if werr == nil && err == nil {
+				werr = writeGraphFile(b.path(i, ".txt"), search.Path, graph.WritePath)
+			}
+		}
+		if werr != nil {
+			return fail("batch", exitInput, werr)
+		}
+		if err != nil {
+			err = batchError{err}
+		}
+		if code = batchResult(i, err, code); code == exitInput {
+			return code
+		}
+	}
+	return code
+}
+
+func (b *batch) rrt(i int, p planner.Problem, cSpace planner.ConfigSpace, obstacles []planner.Circle, robot planner.Robot) error {
+	ctx, cancel := b.opts.context()
+	defer cancel()
+	seed := b.seed + int64(i)
+	solution, err := solveRRT(ctx, p, cSpace, obstacles, robot, b.footprint, seed, b.opts.maxIter, nil)
+	result := planner.Result{Problem: p, Planner: "rrt", Seed: seed, Solution: solution, Err: err}
+	if werr := writeResult(b.path(i, formats[b.format]), b.format, result, 0); werr != nil {
+		return werr
+	}
+	if err != nil {
+		return batchError{err}
+	}
+	return nil
+}
+
+func (b *batch) kinodynamic(i int, p planner.Problem, cSpace planner.ConfigSpace, obstacles []planner.Circle, robot planner.Robot) error {
+	checker := kinodynamicChecker(obstacles, cSpace, robot)
+	ctx, cancel := b.opts.context()
+	defer cancel()
+	seed := b.seed + int64(i)
+	solution, err := b.opts.newPlanner(p, cSpace, obstacles, checker, seed).Plan(ctx)
+	if err == nil {
+		b.opts.refine(solution, p, cSpace, obstacles, robot, checker)
+	} else {
+		err = errors.Wrapf(err, "%s failed", b.opts.planner)
+	}
+	result := planner.Result{Problem: p, Planner: b.opts.planner, Seed: seed, Solution: solution, Err: err}
+	if werr := writeResult(b.path(i, formats[b.format]), b.format, result, b.opts.reportEvery); werr != nil {
+		return werr
+	}
+	if err != nil {
+		return batchError{err}
+	}
+	path := planner.Trajectory(solution.Path)
+	return writeTrajectory(b.path(i, "_state.csv"), p, path, b.opts.precision)
+}
@@ -0,0 +1,258 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/hdhauk/enae788v/bench"
+	"github.com/hdhauk/enae788v/planner"
+)
+
+// variant is a planner with its settings, as given to -planner.
+type variant struct {
+	spec      string
+	opts      kinodynamicOptions
+	star      starOptions
+	footprint footprintOptions
+	epsilon   *float64 // overrides the epsilon of the problem if set
+	goalBias  *float64 // overrides the goal bias of the problem if set
+}
+
+// parseVariant parses a planner name followed by settings, separated by
+// colons, such as sst:cost=effort:budget=2s. The settings not given are taken
+// from base.
+func parseVariant(spec string, base variant) (variant, error) {
+	v := base
+	v.spec = spec
+	fields := strings.Split(spec, ":")
+	v.opts.planner = fields[0]
+	for _, field := range fields[1:] {
+		key, value, found := strings.Cut(field, "=")
+		if !found {
+			return v, errors.Errorf("setting %q of planner %q is not key=value", field, spec)
+		}
+		var err error
+		switch key {
+		case "cost":
+			v.opts.cost = value
+		case "budget":
+			v.opts.sst.Budget, err = time.ParseDuration(value)
+			v.star.budget = v.opts.sst.Budget
+		case "samples":
+			v.star.samples, err = strconv.Atoi(value)
+		case "headings":
+			v.opts.hybrid.Headings, err = strconv.Atoi(value)
+		case "primitives":
+			v.opts.lattice = value
+		case "cobstacles":
+			v.footprint.slices, err = strconv.Atoi(value)
+		case "hull":
+			v.footprint.hull, err = strconv.ParseBool(value)
+		case "delta-bn", "delta-s", "max-prop", "cell", "resolution", "turn-weight", "max-turn", "epsilon", "goal-bias":
+			var x float64
+			x, err = strconv.ParseFloat(value, 64)
+			switch key {
+			case "delta-bn":
+				v.opts.sst.DeltaBN = x
+			case "delta-s":
+				v.opts.sst.DeltaS = x
+			case "max-prop":
+				v.opts.sst.MaxPropTime = x
+			case "cell":
+				v.opts.hybrid.Resolution = x
+			case "resolution":
+				v.footprint.resolution = x
+			case "turn-weight":
+				v.footprint.turnWeight = x
+			case "max-turn":
+				v.footprint.maxTurn = x
+			case "epsilon":
+				v.epsilon = &x
+			case "goal-bias":
+				v.goalBias = &x
+			}
+		default:
+			return v, errors.Errorf("unknown setting %q of planner %q", key, spec)
+		}
+		if err != nil {
+			return v, errors.Wrapf(err, "invalid setting %q of planner %q", field, spec)
+		}
+	}
+	if starPlanner(v.opts.planner) {
+		return v, nil
+	}
+	return v, errors.Wrapf(v.opts.check(), "planner %q", spec)
+}
+
+// solve runs the variant once on a problem. Kinodynamic configs are solved
+// with the planners of plan kinodynamic, and others with the planners of plan
+// rrt.
+func (v variant) solve(p planner.Problem, cSpace planner.ConfigSpace, obstacles []planner.Circle, robot planner.Robot, seed int64) (*planner.Solution, error) {
+	if v.epsilon != nil {
+		p.Epsilon = *v.epsilon
+	}
+	if v.goalBias != nil {
+		p.GoalBias = *v.goalBias
+	}
+	ctx, cancel := v.opts.context()
+	defer cancel()
+	if starPlanner(v.opts.planner) {
+		return solveStar(ctx, v.opts.planner, p, cSpace, obstacles, robot, v.footprint, seed, v.opts.maxIter, v.star)
+	}
+	if !kinodynamicSpace(cSpace) {
+		return solveRRT(ctx, p, cSpace, obstacles, robot, v.footprint, seed, v.opts.maxIter, nil)
+	}
+	checker := kinodynamicChecker(obstacles, cSpace, robot)
+	return v.opts.newPlanner(p, cSpace, obstacles, checker, seed).Plan(ctx)
+}
+
+// stringList is a flag that can be given several times.
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, " ")
+}
+
+func (l *stringList) Set(s string) error {
+	*l = append(*l, s)
+	return nil
+}
+
+// benchMain runs every problem of a config with every planner variant for a
+// number of seeds, and writes summary statistics per problem and variant.
+// Every variant runs with the same seeds.
+func benchMain(args []string) int {
+	fs := newFlagSet("bench", "")
+	configPath := fs.String("c", "hw2/problems.json", "config file")
+	runs := fs.Int("runs", 10, "runs of every planner on every problem, with seeds from -seed upwards")
+	seed := fs.Int64("seed", 1, "seed of the first run")
+	var specs stringList
+	fs.Var(&specs, "planner", "planner to run, with optional settings as in sst:cost=effort:budget=2s; can be given several times (default rrt)\n"+
+		"planners: rrt, sst, hybrid and lattice (configs with dynamics), rrtstar, informed, bitstar, fmtstar (configs without dynamics) and tangent (configs without a robot)\n"+
+		"settings: epsilon, goal-bias, resolution, cobstacles, hull, turn-weight, max-turn (configs without dynamics), budget (sst, rrtstar, informed, bitstar), samples (bitstar, fmtstar), cell, headings (hybrid), primitives (lattice), cost, delta-bn, delta-s, max-prop (sst)")
+	outPath := fs.String("o", "", "output path for the summary (default stdout)")
+	format := fs.String("format", "", "summary format: csv or markdown (default from the extension of -o, or csv)")
+	runsPath := fs.String("runs-out", "", "output path for a csv with every run")
+	base := variant{opts: kinodynamicOptions{planner: "rrt", cost: "duration", sst: defaultSST, hybrid: planner.DefaultHybridAStarParams()}, star: defaultStar, footprint: defaultFootprint}
+	base.footprint.register(fs)
+	base.opts.limits.register(fs)
+	if code, run := parse(fs, args, 0, 0); !run {
+		return code
+	}
+
+	if *format == "" {
+		*format = "csv"
+		if ext := filepath.Ext(*outPath); ext == ".md" || ext == ".markdown" {
+			*format = "markdown"
+		}
+	}
+	if *format != "csv" && *format != "markdown" {
+		return fail("bench", exitUsage, errors.Errorf("unknown format %q", *format))
+	}
+	if *runs < 1 {
+		return fail("bench", exitUsage, errors.New("-runs must be positive"))
+	}
+	if len(specs) == 0 {
+		specs = stringList{"rrt"}
+	}
+	var variants []variant
+	for _, spec := range specs {
+		v, err := parseVariant(spec, base)
+		if err != nil {
+			return fail("bench", exitUsage, err)
+		}
+		variants = append(variants, v)
+	}
+
+	config, obstacles, robot, err := planner.LoadConfig(*configPath)
+	infeasible, isInfeasible := err.(planner.FeasibilityError)
+	if err != nil && !isInfeasible {
+		return fail("bench", exitInput, err)
+	}
+	for i, v := range variants {
+		if (v.opts.planner == "sst" || v.opts.planner == "hybrid" || v.opts.planner == "lattice") && !kinodynamicSpace(config.ConfigSpace) {
+			return fail("bench", exitUsage, errors.Errorf("planner %q needs a config with dynamics", v.spec))
+		}
+		if starPlanner(v.opts.planner) && kinodynamicSpace(config.ConfigSpace) {
+			return fail("bench", exitUsage, errors.Errorf("planner %q needs a config without dynamics", v.spec))
+		}
+		if v.opts.planner == "tangent" && robot != nil {
+			return fail("bench", exitUsage, errors.Errorf("planner %q needs a config without a robot", v.spec))
+		}
+		if err := variants[i].opts.loadPrimitives(config.ConfigSpace); err != nil {
+			return fail("bench", exitInput, errors.Wrapf(err, "planner %q", v.spec))
+		}
+	}
+
+	var results []bench.Run
+	for i, p := range config.Problems {
+		for _, v := range variants {
+			solved := 0
+			for r := 0; r < *runs; r++ {
+				s := *seed + int64(r)
+				// Infeasible problems count as failed runs without planning.
+				var sol *planner.Solution
+				err := infeasible.Problem(i)
+				if err == nil {
+					sol, err = v.solve(p, config.ConfigSpace, obstacles, robot, s)
+				}
+				run := bench.NewRun(i, p.Name, v.spec, s, sol, err)
+				if run.Solved {
+					solved++
+				}
+				results = append(results, run)
+			}
+			fmt.Fprintf(os.Stderr, "problem %d %s: %d/%d solved\n", i, v.spec, solved, *runs)
+		}
+	}
+
+	if *runsPath != "" {
+		if err := writeFile(*runsPath, func(f *os.File) error { return bench.WriteRunsCSV(f, results) }); err != nil {
+			return fail("bench", exitInput, err)
+		}
+	}
+	f, err := create(*outPath)
+	if err != nil {
+		return fail("bench", exitInput, errors.Wrap(err, "could not create summary"))
+	}
+	summaries := bench.Summarize(results)
+	if *format == "markdown" {
+		err = bench.WriteMarkdown(f, summaries)
+	} else {
+		err = bench.WriteCSV(f, summaries)
+	}
+	if err == nil {
+		err = errors.Wrap(f.Close(), "could not close summary")
+	} else {
+		f.Close()
+	}
+	if err != nil {
+		return fail("bench", exitInput, err)
+	}
+	return exitOK
+}
+
+// writeFile creates a file at path and writes it with write.
+func writeFile(path string, write func(f *os.File) error) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return errors.Wrap(err, "could not create file")
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return errors.Wrap(f.Close(), "could not close file")
+}
+
+// kinodynamicSpace reports whether a config space has the acceleration and
+// steering limits of a robot with dynamics, as in hw4.
+func kinodynamicSpace(cSpace planner.ConfigSpace) bool {
+	return cSpace.AMax != 0 || cSpace.GammaMax != 0
+}
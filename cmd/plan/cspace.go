@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+
+	"github.com/hdhauk/enae788v/planner"
+	"github.com/hdhauk/enae788v/render"
+)
+
+// cspaceMain builds the C-obstacles of the robot of a config and draws a θ
+// slice of its configuration space, or every slice into a directory, to
+// debug the C-obstacles used by rrt -cobstacles.
+func cspaceMain(args []string) int {
+	fs := newFlagSet("cspace", "")
+	configPath := fs.String("c", "hw3/problems.json", "config file")
+	pIndex := fs.Int("p", 0, "problem in the config file to draw (0-indexed)")
+	outPath := fs.String("o", "", "output path for the figure, or directory of the slice<n> figures with -all (default stdout)")
+	format := fs.String("format", "", "figure format: svg or png (default from the extension of -o, or svg)")
+	slices := fs.Int("slices", 64, "headings to build the C-obstacles for")
+	slice := fs.Int("slice", -1, "slice to draw (default the slice of the start heading of the problem)")
+	all := fs.Bool("all", false, "draw every slice")
+	hull := fs.Bool("hull", false, "build the C-obstacles of the convex hull of the footprint instead of its points")
+	polygonsPath := fs.String("polygons", "", polygonsUsage)
+	opts := render.DefaultOptions
+	fs.IntVar(&opts.Width, "width", opts.Width, "width of the figure in pixels")
+	if code, run := parse(fs, args, 0, 0); !run {
+		return code
+	}
+	if !*all {
+		*format = figureFormat(*format, *outPath)
+	} else if *format == "" {
+		*format = "svg"
+	}
+	switch {
+	case *format != "svg" && *format != "png":
+		return fail("cspace", exitUsage, errors.Errorf("unknown format %q", *format))
+	case *slices < 1:
+		return fail("cspace", exitUsage, errors.New("-slices must be positive"))
+	case *slice >= *slices:
+		return fail("cspace", exitUsage, errors.Errorf("-slice must be less than -slices, %d", *slices))
+	case *all && *outPath == "":
+		return fail("cspace", exitUsage, errors.New("-all needs an output directory"))
+	}
+
+	config, obstacles, robot, code := loadProblem("cspace", *configPath, *pIndex)
+	if code != exitOK {
+		return code
+	}
+	polygons, err := readPolygons(*polygonsPath)
+	if err != nil {
+		return fail("cspace", exitInput, err)
+	}
+	// A point robot is a footprint of its center.
+	if robot == nil {
+		robot = planner.Robot{{}}
+	}
+	footprint := footprintOptions{slices: *slices, hull: *hull}.footprint(robot)
+	p := config.Problems[*pIndex]
+	cObstacles := planner.NewCObstacles(config.ConfigSpace, obstacles, polygons, footprint, *slices)
+	scene := render.Scene{Space: config.ConfigSpace, Obstacles: obstacles, Polygons: polygons, Problem: p}
+
+	if !*all {
+		scene.Slice = cObstacles.Slice(p.Start.Theta)
+		if *slice >= 0 {
+			scene.Slice = &cObstacles.Slices[*slice]
+		}
+		if err := renderFile(*outPath, *format, scene, opts); err != nil {
+			return fail("cspace", exitInput, err)
+		}
+		fmt.Fprintf(os.Stderr, "slice at θ=%.3f with %d C-obstacles, inflated by %.3f\n", scene.Slice.Theta, len(scene.Slice.Obstacles), cObstacles.Inflation)
+		return exitOK
+	}
+
+	if err := os.MkdirAll(*outPath, 0755); err != nil {
+		return fail("cspace", exitInput, errors.Wrap(err, "could not create slice directory"))
+	}
+	for i := range cObstacles.Slices {
+		scene.Slice = &cObstacles.Slices[i]
+		path := filepath.Join(*outPath, fmt.Sprintf("slice%03d.%s", i, *format))
+		if err := renderFile(path, *format, scene, opts); err != nil {
+			return fail("cspace", exitInput, err)
+		}
+	}
+	fmt.Fprintf(os.Stderr, "%d slices, inflated by %.3f\n", len(cObstacles.Slices), cObstacles.Inflation)
+	return exitOK
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/pkg/errors"
+
+	"github.com/hdhauk/enae788v/graph"
+)
+
+func graphMain(args []string) int {
+	fs := newFlagSet("graph", "")
+	problemsPath := fs.String("c", "hw1/problems/problems.txt", "problem file")
+	pIndex := fs.Int("p", 0, "which problem in the problem file to solve (0-indexed)")
+	outPath := fs.String("o", "", "output path for the shortest path (default stdout)")
+	format := fs.String("format", "text", "output format: text, a csv row for every vertex along the path, or json")
+	treePath := fs.String("tree", "", "output path for the search tree csv (default none)")
+	dijkstra := fs.Bool("dijkstra", false, "use a zero heuristic, which makes the search equal to Dijkstra")
+	if code, run := parse(fs, args, 0, 0); !run {
+		return code
+	}
+	if err := checkFormat(*format); err != nil {
+		return fail("graph", exitUsage, err)
+	}
+
+	problems, err := graph.ReadProblems(*problemsPath)
+	if err != nil {
+		return fail("graph", exitInput, err)
+	}
+	if *pIndex < 0 || *pIndex >= len(problems) {
+		return fail("graph", exitUsage, errors.Errorf("invalid problem number %d, the file has %d problems", *pIndex, len(problems)))
+	}
+
+	p := problems[*pIndex]
+	search, err := solveGraph(p, *dijkstra)
+	if err := writeGraphFile(*treePath, search.SearchTree, graph.WriteSearchTree); err != nil {
+		return fail("graph", exitInput, err)
+	}
+	if *format == "json" {
+		algorithm := "astar"
+		if *dijkstra {
+			algorithm = "dijkstra"
+		}
+		if err := writeGraphJSON(*outPath, graph.Result{Problem: p, Algorithm: algorithm, Search: search, Err: err}); err != nil {
+			return fail("graph", exitInput, err)
+		}
+	}
+	if err != nil {
+		return fail("graph", exitNoSolution, err)
+	}
+	fmt.Fprintf(os.Stderr, "found shortest path with distance %.3f\n", search.PathCost)
+
+	if *format == "text" {
+		if *outPath == "" {
+			*outPath = "-"
+		}
+		if err := writeGraphFile(*outPath, search.Path, graph.WritePath); err != nil {
+			return fail("graph", exitInput, err)
+		}
+	}
+	return exitOK
+}
+
+// solveGraph runs A* on a problem, or Dijkstra if dijkstra is set.
+func solveGraph(p *graph.Problem, dijkstra bool) (*graph.SearchResult, error) {
+	h := graph.CartesianDistance
+	if dijkstra {
+		h = graph.Zero
+	}
+	return graph.AStar(p.Vertices, p.StartID, p.GoalID, h)
+}
+
+// writeGraphFile writes vertices to path with write. Nothing is written if
+// path is empty.
+func writeGraphFile(path string, vertices []*graph.Vertex, write func(w io.Writer, vertices []*graph.Vertex) error) error {
+	if path == "" {
+		return nil
+	}
+	f, err := create(path)
+	if err != nil {
+		return errors.Wrap(err, "could not create file")
+	}
+	if err := write(f, vertices); err != nil {
+		f.Close()
+		return err
+	}
+	return errors.Wrap(f.Close(), "could not close file")
+}
+
+// writeGraphJSON writes a graph search as json to path, or to stdout if path
+// is empty.
+func writeGraphJSON(path string, r graph.Result) error {
+	f, err := create(path)
+	if err != nil {
+		return errors.Wrap(err, "could not create file")
+	}
+	if err := graph.WriteJSON(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	return errors.Wrap(f.Close(), "could not close file")
+}
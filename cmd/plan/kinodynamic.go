@@ -0,0 +1,247 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/hdhauk/enae788v/planner"
+)
+
+// kinodynamicOptions are the flags shared by the kinodynamic and batch
+// commands.
+type kinodynamicOptions struct {
+	limits
+	planner     string
+	cost        string
+	sst         planner.SSTParams
+	hybrid      planner.HybridAStarParams
+	lattice     string              // path of the primitives of lattice
+	primitives  *planner.Primitives // set by loadPrimitives
+	optimize    bool
+	precision   int
+	reportEvery int
+}
+
+// defaultSST are the sst parameters unless set by flags.
+var defaultSST = planner.SSTParams{Budget: 10 * time.Second, DeltaBN: 2, DeltaS: 1, MaxPropTime: 2}
+
+func (o *kinodynamicOptions) register(fs *flag.FlagSet) {
+	o.limits.register(fs)
+	o.sst = defaultSST
+	o.hybrid = planner.DefaultHybridAStarParams()
+	fs.StringVar(&o.planner, "planner", "rrt", "planner to use: rrt (first feasible trajectory), sst (keeps improving until -budget is spent), hybrid (deterministic hybrid a* on a grid) or lattice (deterministic a* on a state lattice)")
+	fs.StringVar(&o.cost, "cost", "duration", "cost minimized by sst: duration or effort")
+	fs.DurationVar(&o.sst.Budget, "budget", o.sst.Budget, "time budget for sst")
+	fs.Float64Var(&o.sst.DeltaBN, "delta-bn", o.sst.DeltaBN, "best-near selection radius for sst")
+	fs.Float64Var(&o.sst.DeltaS, "delta-s", o.sst.DeltaS, "witness radius for sst")
+	fs.Float64Var(&o.sst.MaxPropTime, "max-prop", o.sst.MaxPropTime, "maximum duration in seconds of a random propagation in sst")
+	fs.Float64Var(&o.hybrid.Resolution, "cell", o.hybrid.Resolution, "side of the grid cells of hybrid")
+	fs.IntVar(&o.hybrid.Headings, "headings", o.hybrid.Headings, "number of heading cells of hybrid")
+	fs.StringVar(&o.lattice, "primitives", "", "motion primitives of lattice, written by plan primitives (default generated from the limits of the config)")
+	fs.BoolVar(&o.optimize, "optimize", false, "refine the trajectory with the iLQR trajectory optimizer, minimizing control effort and duration")
+	fs.IntVar(&o.precision, "precision", 4, "number of decimals in the trajectory csv")
+	fs.IntVar(&o.reportEvery, "report-every", 1000, "print the best sst cost every n iterations, in addition to every improvement")
+}
+
+// check returns an error if the planner or cost is unknown.
+func (o *kinodynamicOptions) check() error {
+	if o.planner != "rrt" && o.planner != "sst" && o.planner != "hybrid" && o.planner != "lattice" {
+		return errors.Errorf("unknown planner %q", o.planner)
+	}
+	if o.hybrid.Resolution <= 0 || o.hybrid.Headings < 1 {
+		return errors.New("hybrid needs a positive cell size and number of headings")
+	}
+	if o.cost != "duration" && o.cost != "effort" {
+		return errors.Errorf("unknown cost %q", o.cost)
+	}
+	return nil
+}
+
+// loadPrimitives reads the primitives of lattice from the -primitives file, or
+// generates them from the limits of cSpace if there is none. Other planners
+// need no primitives.
+func (o *kinodynamicOptions) loadPrimitives(cSpace planner.ConfigSpace) error {
+	if o.planner != "lattice" {
+		return nil
+	}
+	if o.lattice == "" {
+		o.primitives = planner.GeneratePrimitives(cSpace, planner.DefaultPrimitiveParams())
+		return nil
+	}
+	f, err := os.Open(o.lattice)
+	if err != nil {
+		return errors.Wrap(err, "could not open primitives")
+	}
+	defer f.Close()
+	o.primitives, err = planner.ReadPrimitives(f)
+	return err
+}
+
+// newPlanner returns the planner selected by the options, which must have
+// passed check and loaded their primitives.
+func (o *kinodynamicOptions) newPlanner(p planner.Problem, cSpace planner.ConfigSpace, obstacles []planner.Circle, checker planner.CollisionChecker, seed int64) planner.Planner {
+	switch o.planner {
+	case "rrt":
+		rrt := planner.NewKinodynamicRRT(p, cSpace, checker, seed)
+		rrt.MaxIterations = o.maxIter
+		return rrt
+	case "hybrid":
+		params := o.hybrid
+		params.MaxIterations = o.maxIter
+		return planner.NewHybridAStar(p, cSpace, obstacles, checker, params)
+	case "lattice":
+		lattice := planner.NewStateLattice(p, cSpace, obstacles, checker, o.primitives)
+		lattice.MaxIterations = o.maxIter
+		return lattice
+	}
+	params := o.sst
+	params.MaxIterations = o.maxIter
+	params.Cost = planner.DurationCost
+	if o.cost == "effort" {
+		params.Cost = planner.EffortCost
+	}
+	return planner.NewSST(p, cSpace, checker, seed, params)
+}
+
+// refine replaces the path of a solution by the refinement of its trajectory
+// with the trajectory optimizer if -optimize is set. The refined path is a
+// single motion from the start, which is added to the tree as well. The
+// change is reported to stderr. If the optimizer fails, the planned path is
+// kept and the failure is reported to stderr as a warning.
+func (o *kinodynamicOptions) refine(solution *planner.Solution, p planner.Problem, cSpace planner.ConfigSpace, obstacles []planner.Circle, robot planner.Robot, checker planner.CollisionChecker) {
+	if !o.optimize {
+		return
+	}
+	trajectory := planner.Trajectory(solution.Path)
+	optimizer := planner.NewTrajectoryOptimizer(p, cSpace, obstacles, robot, checker, planner.DefaultOptimizerParams())
+	refined, err := optimizer.Optimize(context.Background(), trajectory)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: could not optimize the trajectory, keeping the planned one: %v\n", err)
+		return
+	}
+
+	m := &planner.Motion{From: &planner.PathPoint{Point: p.Start}, To: refined[len(refined)-1]}
+	for _, s := range refined {
+		m.Path = append(m.Path, s)
+	}
+	m.Cost = planner.DurationCost(m)
+	if o.cost == "effort" {
+		m.Cost = planner.EffortCost(m)
+	}
+	fmt.Fprintf(os.Stderr, "optimized: duration %.1fs to %.1fs, objective %.2f to %.2f\n",
+		trajectory[len(trajectory)-1].T, refined[len(refined)-1].T, optimizer.Objective(trajectory), optimizer.Objective(refined))
+	solution.Path, solution.Cost = []*planner.Motion{m}, m.Cost
+	solution.Tree = append(solution.Tree, m)
+}
+
+func kinodynamicMain(args []string) int {
+	fs := newFlagSet("kinodynamic", "")
+	configPath := fs.String("c", "hw4/problems.json", "config file")
+	pIndex := fs.Int("p", 0, "which problem in config file to solve (0-indexed)")
+	seed := fs.Int64("seed", 11, "seed for the random sampling")
+	outPath := fs.String("o", "", "output path for the solution (default stdout)")
+	format := fs.String("format", "text", formatUsage)
+	trajectoryPath := fs.String("trajectory", "", "output path for the trajectory csv (default \"problem<p>_state.csv\")")
+	var opts kinodynamicOptions
+	opts.register(fs)
+	var view viewer
+	view.register(fs)
+	tracking := planner.DefaultTrackingConfig()
+	track := fs.Bool("track", false, "simulate following the planned trajectory in closed loop and report tracking error")
+	fs.Float64Var(&tracking.PositionNoise, "noise-pos", tracking.PositionNoise, "std. deviation of position noise per step used with -track")
+	fs.Float64Var(&tracking.HeadingNoise, "noise-theta", tracking.HeadingNoise, "std. deviation of heading noise per step used with -track")
+	fs.Float64Var(&tracking.VelocityNoise, "noise-vel", tracking.VelocityNoise, "std. deviation of v and w noise per step used with -track")
+	fs.Int64Var(&tracking.Seed, "noise-seed", tracking.Seed, "seed for the process noise used with -track")
+	fs.Var(planner.Weights(tracking.Q[:]), "track-q", "LQR weights on the x, y, θ, v and w errors used with -track")
+	fs.Var(planner.Weights(tracking.R[:]), "track-r", "LQR weights on the a and γ corrections used with -track")
+	if code, run := parse(fs, args, 0, 0); !run {
+		return code
+	}
+	if err := opts.check(); err != nil {
+		return fail("kinodynamic", exitUsage, err)
+	}
+	if view.enabled() && opts.planner != "rrt" {
+		return fail("kinodynamic", exitUsage, errors.New("-serve only supports -planner rrt"))
+	}
+	if err := checkFormat(*format); err != nil {
+		return fail("kinodynamic", exitUsage, err)
+	}
+
+	config, obstacles, robot, code := loadProblem("kinodynamic", *configPath, *pIndex)
+	if code != exitOK {
+		return code
+	}
+
+	if err := opts.loadPrimitives(config.ConfigSpace); err != nil {
+		return fail("kinodynamic", exitInput, err)
+	}
+	p := config.Problems[*pIndex]
+	checker := kinodynamicChecker(obstacles, config.ConfigSpace, robot)
+	pl := opts.newPlanner(p, config.ConfigSpace, obstacles, checker, *seed)
+	if view.enabled() {
+		if err := view.start("kinodynamic", p, config.ConfigSpace, obstacles, robot); err != nil {
+			return fail("kinodynamic", exitInput, err)
+		}
+		pl.(*planner.RRT).OnAdd = view.add
+	}
+	ctx, cancel := opts.context()
+	defer cancel()
+	solution, err := pl.Plan(ctx)
+	if err == nil {
+		opts.refine(solution, p, config.ConfigSpace, obstacles, robot, checker)
+	}
+
+	// The partial tree is written even if no solution was found.
+	result := planner.Result{Problem: p, Planner: opts.planner, Seed: *seed, Solution: solution, Err: err}
+	if err := writeResult(*outPath, *format, result, opts.reportEvery); err != nil {
+		return fail("kinodynamic", exitInput, err)
+	}
+	if view.enabled() {
+		defer view.finish("kinodynamic", solution, err)
+	}
+	if err != nil {
+		return fail("kinodynamic", exitNoSolution, errors.Wrapf(err, "%s failed", opts.planner))
+	}
+
+	path := planner.Trajectory(solution.Path)
+	if *track {
+		report := planner.SimulateTracking(p.Start, path, p, config.ConfigSpace, obstacles, robot, tracking)
+		fmt.Fprintln(os.Stderr, report)
+	}
+
+	if *trajectoryPath == "" {
+		*trajectoryPath = fmt.Sprintf("problem%d_state.csv", *pIndex)
+	}
+	if err := writeTrajectory(*trajectoryPath, p, path, opts.precision); err != nil {
+		return fail("kinodynamic", exitInput, err)
+	}
+	return exitOK
+}
+
+// kinodynamicChecker returns the collision checker for the robot footprint,
+// which also enforces the velocity limits of the config space.
+func kinodynamicChecker(obstacles []planner.Circle, cSpace planner.ConfigSpace, robot planner.Robot) planner.CollisionChecker {
+	return planner.KinodynamicChecker{FootprintChecker: planner.FootprintChecker{
+		Obstacles: obstacles,
+		Space:     cSpace,
+		Robot:     robot,
+	}}
+}
+
+// writeTrajectory writes the trajectory csv of a solution to path.
+func writeTrajectory(path string, p planner.Problem, trajectory []*planner.PathPoint, precision int) error {
+	f, err := create(path)
+	if err != nil {
+		return errors.Wrap(err, "could not create trajectory file")
+	}
+	if err := planner.WriteTrajectory(f, p.Start, trajectory, precision); err != nil {
+		f.Close()
+		return errors.Wrap(err, "could not write trajectory")
+	}
+	return errors.Wrap(f.Close(), "could not close trajectory file")
+}
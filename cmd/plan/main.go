@@ -0,0 +1,211 @@
+// Command plan solves the motion planning problems of every homework with a
+// single binary:
+//
+//	plan graph        A* or Dijkstra on the graphs of hw1
+//	plan rrt          RRT for the point robot of hw2 and the robot footprint of hw3
+//	plan kinodynamic  RRT, SST, Hybrid A* or a state lattice for the robot with dynamics of hw4
+//	plan primitives   generate the motion primitives of the state lattice
+//	plan batch        solve every problem in a problem file
+//	plan bench        compare planners over many seeds
+//	plan validate     check a hw4 trajectory csv against its problem
+//	plan render       draw a json result to svg or png
+//	plan cspace       draw the C-obstacles of a robot footprint at a heading
+//	plan animate      animate the robot along the path of a json result
+//	plan arm          RRT in joint space for a planar robot arm
+//	plan rrt3d        RRT or RRT* for a robot in 3D space among spheres and boxes
+//
+// Run plan <command> -h for the flags of a command.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/hdhauk/enae788v/planner"
+)
+
+// Exit codes.
+const (
+	exitOK         = 0
+	exitNoSolution = 1 // no path was found, or the trajectory is invalid
+	exitUsage      = 2 // invalid command, flags or arguments
+	exitInput      = 3 // the problem files could not be read or are infeasible, or outputs could not be written
+)
+
+// command is a subcommand of plan. run returns the exit code.
+type command struct {
+	summary string
+	run     func(args []string) int
+}
+
+var commands = map[string]command{
+	"graph":       {"A* or Dijkstra shortest path on a graph (hw1)", graphMain},
+	"rrt":         {"RRT for a point robot or a robot footprint (hw2, hw3)", rrtMain},
+	"kinodynamic": {"RRT, SST, Hybrid A* or a state lattice for a robot with dynamics (hw4)", kinodynamicMain},
+	"primitives":  {"generate the motion primitives of the state lattice (hw4)", primitivesMain},
+	"batch":       {"solve every problem in a problem file", batchMain},
+	"bench":       {"compare planners over many seeds", benchMain},
+	"validate":    {"check a trajectory csv against its problem (hw4)", validateMain},
+	"render":      {"draw a json result to svg or png", renderMain},
+	"animate":     {"animate the robot along the path of a json result", animateMain},
+	"cspace":      {"draw the C-obstacles of a robot footprint at a heading (hw3)", cspaceMain},
+	"arm":         {"RRT in joint space for a planar robot arm", armMain},
+	"rrt3d":       {"RRT or RRT* for a robot in 3D space among spheres and boxes", rrt3dMain},
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage(os.Stderr)
+		os.Exit(exitUsage)
+	}
+	name := os.Args[1]
+	if name == "-h" || name == "-help" || name == "--help" || name == "help" {
+		usage(os.Stdout)
+		os.Exit(exitOK)
+	}
+	cmd, ok := commands[name]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "plan: unknown command %q\n\n", name)
+		usage(os.Stderr)
+		os.Exit(exitUsage)
+	}
+	os.Exit(cmd.run(os.Args[2:]))
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintf(w, "Usage:\n  plan <command> [flags]\n\nCommands:\n")
+	var names []string
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(w, "  %-12s %s\n", name, commands[name].summary)
+	}
+	fmt.Fprintf(w, "\nRun plan <command> -h for the flags of a command.\n")
+	fmt.Fprintf(w, "\nExit codes:\n  %d  success\n  %d  no solution found, or invalid trajectory\n  %d  invalid command, flags or arguments\n  %d  could not read inputs, infeasible problem, or could not write outputs\n",
+		exitOK, exitNoSolution, exitUsage, exitInput)
+}
+
+// newFlagSet returns a flag set for a subcommand with generated usage. args
+// describes the positional arguments.
+func newFlagSet(name, args string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage:\n  plan %s [flags] %s\n\nFlags:\n", name, args)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parse parses the flags of a subcommand that takes between minArgs and
+// maxArgs arguments, and returns the exit code to use if the command should
+// not run.
+func parse(fs *flag.FlagSet, args []string, minArgs, maxArgs int) (int, bool) {
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return exitOK, false
+		}
+		return exitUsage, false
+	}
+	if fs.NArg() < minArgs || fs.NArg() > maxArgs {
+		fmt.Fprintf(fs.Output(), "plan %s: unexpected number of arguments: %d\n", fs.Name(), fs.NArg())
+		fs.Usage()
+		return exitUsage, false
+	}
+	return exitOK, true
+}
+
+// limits are the flags that make a planner give up.
+type limits struct {
+	timeout time.Duration
+	maxIter int
+}
+
+func (l *limits) register(fs *flag.FlagSet) {
+	fs.DurationVar(&l.timeout, "timeout", 0, "give up if no solution is found within this duration (default no timeout)")
+	fs.IntVar(&l.maxIter, "max-iter", 0, "give up if no solution is found within this many iterations (default no limit)")
+}
+
+// context returns a context that is done when the timeout has passed.
+func (l limits) context() (context.Context, context.CancelFunc) {
+	if l.timeout > 0 {
+		return context.WithTimeout(context.Background(), l.timeout)
+	}
+	return context.WithCancel(context.Background())
+}
+
+// formats maps the values of the -format flag to the extension of their files.
+var formats = map[string]string{"text": ".txt", "json": ".json"}
+
+const formatUsage = "output format: text, read by plot.py, or json"
+
+// polygonsUsage describes the -polygons flag of the commands that build
+// C-obstacles.
+const polygonsUsage = "csv file of convex polygonal obstacles besides the circles of the config, a polygon per row of the x and y of its vertices"
+
+func checkFormat(format string) error {
+	if _, ok := formats[format]; !ok {
+		return errors.Errorf("unknown format %q", format)
+	}
+	return nil
+}
+
+// loadProblem loads a config, and checks that problem i exists and is
+// feasible. The exit code is non-zero if it does not.
+func loadProblem(cmd, configPath string, i int) (*planner.Config, []planner.Circle, planner.Robot, int) {
+	config, obstacles, robot, err := planner.LoadConfig(configPath)
+	infeasible, isInfeasible := err.(planner.FeasibilityError)
+	if err != nil && !isInfeasible {
+		return nil, nil, nil, fail(cmd, exitInput, err)
+	}
+	if i < 0 || i >= len(config.Problems) {
+		return nil, nil, nil, fail(cmd, exitUsage, errors.Errorf("invalid problem number %d, the config has %d problems", i, len(config.Problems)))
+	}
+	if err := infeasible.Problem(i); err != nil {
+		return nil, nil, nil, fail(cmd, exitInput, err)
+	}
+	return config, obstacles, robot, exitOK
+}
+
+// readPolygons reads the polygonal obstacles of the csv file path, or none if
+// path is empty.
+func readPolygons(path string) ([]planner.Polygon, error) {
+	if path == "" {
+		return nil, nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not open polygon file")
+	}
+	defer f.Close()
+	polygons, err := planner.ReadPolygons(f)
+	return polygons, errors.Wrap(err, "could not read polygons from file")
+}
+
+// fail prints an error for a subcommand and returns the exit code.
+func fail(cmd string, code int, err error) int {
+	fmt.Fprintf(os.Stderr, "plan %s: %v\n", cmd, err)
+	return code
+}
+
+// create opens the output file path, or returns stdout if path is empty or "-".
+func create(path string) (io.WriteCloser, error) {
+	if path == "" || path == "-" {
+		return nopCloser{os.Stdout}, nil
+	}
+	return os.Create(path)
+}
+
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error { return nil }
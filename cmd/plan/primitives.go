@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/pkg/errors"
+
+	"github.com/hdhauk/enae788v/planner"
+)
+
+// primitivesMain generates the motion primitives of the lattice planner from
+// the velocity and control limits of a config, and writes them as JSON.
+func primitivesMain(args []string) int {
+	fs := newFlagSet("primitives", "")
+	configPath := fs.String("c", "hw4/problems.json", "config file with dynamics")
+	outPath := fs.String("o", "", "output path for the primitives (default stdout)")
+	params := planner.DefaultPrimitiveParams()
+	fs.Float64Var(&params.Resolution, "cell", params.Resolution, "distance between lattice points in x and y")
+	fs.Float64Var(&params.SpeedStep, "speed-step", params.SpeedStep, "distance between lattice speeds")
+	fs.Float64Var(&params.MaxDuration, "max-duration", params.MaxDuration, "duration in seconds of the longest primitive")
+	fs.Float64Var(&params.Tolerance, "tol", params.Tolerance, "largest distance from the end of a primitive to its lattice point")
+	if code, run := parse(fs, args, 0, 0); !run {
+		return code
+	}
+	if params.Resolution <= 0 || params.SpeedStep <= 0 || params.MaxDuration <= 0 || params.Tolerance < 0 {
+		return fail("primitives", exitUsage, errors.New("-cell, -speed-step and -max-duration must be positive, and -tol not negative"))
+	}
+
+	// The problems do not matter, so infeasible ones are no reason to stop.
+	config, _, _, err := planner.LoadConfig(*configPath)
+	if _, isInfeasible := err.(planner.FeasibilityError); err != nil && !isInfeasible {
+		return fail("primitives", exitInput, err)
+	}
+	if !kinodynamicSpace(config.ConfigSpace) {
+		return fail("primitives", exitUsage, errors.New("the config has no dynamics"))
+	}
+
+	lib := planner.GeneratePrimitives(config.ConfigSpace, params)
+	f, err := create(*outPath)
+	if err != nil {
+		return fail("primitives", exitInput, errors.Wrap(err, "could not create primitives file"))
+	}
+	if err := planner.WritePrimitives(f, lib); err != nil {
+		f.Close()
+		return fail("primitives", exitInput, err)
+	}
+	if err := f.Close(); err != nil {
+		return fail("primitives", exitInput, errors.Wrap(err, "could not close primitives file"))
+	}
+	fmt.Fprintf(os.Stderr, "%d primitives for %d headings\n", len(lib.Primitives), len(lib.Headings))
+	return exitOK
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+
+	"github.com/hdhauk/enae788v/planner"
+	"github.com/hdhauk/enae788v/render"
+)
+
+// renderMain draws a result written with -format json, together with the
+// obstacles and robot of its config.
+func renderMain(args []string) int {
+	fs := newFlagSet("render", "[result.json]")
+	configPath := fs.String("c", "hw2/problems.json", "config file with the obstacles and robot of the result")
+	outPath := fs.String("o", "", "output path for the figure (default stdout)")
+	format := fs.String("format", "", "figure format: svg or png (default from the extension of -o, or svg)")
+	opts := render.DefaultOptions
+	registerRenderOptions(fs, &opts)
+	if code, run := parse(fs, args, 0, 1); !run {
+		return code
+	}
+	*format = figureFormat(*format, *outPath)
+	if *format != "svg" && *format != "png" {
+		return fail("render", exitUsage, errors.Errorf("unknown format %q", *format))
+	}
+
+	scene, code := loadScene("render", *configPath, fs.Arg(0))
+	if code != exitOK {
+		return code
+	}
+	if err := renderFile(*outPath, *format, scene, opts); err != nil {
+		return fail("render", exitInput, err)
+	}
+	return exitOK
+}
+
+// loadScene reads a result written with -format json from resultPath, or
+// stdin if empty, and the obstacles and robot of the config it was solved
+// with.
+func loadScene(cmd, configPath, resultPath string) (render.Scene, int) {
+	config, obstacles, robot, err := planner.LoadConfig(configPath)
+	if _, isInfeasible := err.(planner.FeasibilityError); err != nil && !isInfeasible {
+		return render.Scene{}, fail(cmd, exitInput, err)
+	}
+
+	var in io.Reader = os.Stdin
+	if resultPath != "" {
+		f, err := os.Open(resultPath)
+		if err != nil {
+			return render.Scene{}, fail(cmd, exitInput, errors.Wrap(err, "could not open result"))
+		}
+		defer f.Close()
+		in = f
+	}
+	result, err := planner.ReadJSON(in)
+	if err != nil {
+		return render.Scene{}, fail(cmd, exitInput, errors.Wrap(err, "could not read result"))
+	}
+
+	return render.Scene{
+		Space:     config.ConfigSpace,
+		Obstacles: obstacles,
+		Problem:   result.Problem,
+		Robot:     robot,
+		Solution:  result.Solution,
+	}, exitOK
+}
+
+func registerRenderOptions(fs *flag.FlagSet, opts *render.Options) {
+	fs.IntVar(&opts.Width, "width", opts.Width, "width of the figure in pixels")
+	fs.BoolVar(&opts.HideTree, "hide-tree", opts.HideTree, "only draw the path, not the tree")
+	fs.IntVar(&opts.FootprintStep, "footprint-step", opts.FootprintStep, "draw the robot footprint at every n-th state along the path (default at the end of every motion)")
+}
+
+// figureFormat returns format, or if it is empty the format of the extension
+// of path, svg unless it is .png.
+func figureFormat(format, path string) string {
+	if format != "" {
+		return format
+	}
+	if filepath.Ext(path) == ".png" {
+		return "png"
+	}
+	return "svg"
+}
+
+// renderFile draws a scene in format, svg or png, to path, or to stdout if
+// path is empty.
+func renderFile(path, format string, scene render.Scene, opts render.Options) error {
+	f, err := create(path)
+	if err != nil {
+		return errors.Wrap(err, "could not create figure")
+	}
+	if format == "png" {
+		err = render.PNG(f, scene, opts)
+	} else {
+		err = render.SVG(f, scene, opts)
+	}
+	if err != nil {
+		f.Close()
+		return err
+	}
+	return errors.Wrap(f.Close(), "could not close figure")
+}
@@ -0,0 +1,240 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"math"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/hdhauk/enae788v/planner"
+)
+
+func rrtMain(args []string) int {
+	fs := newFlagSet("rrt", "")
+	configPath := fs.String("c", "hw2/problems.json", "config file")
+	pIndex := fs.Int("p", 0, "which problem in config file to solve (0-indexed)")
+	seed := fs.Int64("seed", 0, "seed for the random sampling (default current time)")
+	outPath := fs.String("o", "", "output path for the solution (default stdout)")
+	format := fs.String("format", "text", formatUsage)
+	footprint := defaultFootprint
+	footprint.register(fs)
+	polygonsPath := fs.String("polygons", "", polygonsUsage+", which needs -cobstacles and a config with a robot")
+	plannerName := fs.String("planner", "rrt", "planner to use: rrt (first path found), rrtstar or informed (informed rrt*), bitstar (keep shortening the path until -budget is spent), fmtstar (shortest path through -samples states) or tangent (exact shortest path of a point robot)")
+	star := defaultStar
+	fs.DurationVar(&star.budget, "budget", star.budget, "time budget for rrtstar, informed and bitstar")
+	fs.IntVar(&star.samples, "samples", star.samples, "states sampled by fmtstar, or per batch by bitstar (default 2000 for fmtstar and 100 for bitstar)")
+	reportEvery := fs.Int("report-every", 1000, "print the best cost of rrtstar, informed and bitstar every n iterations, in addition to every improvement")
+	var lim limits
+	lim.register(fs)
+	var view viewer
+	view.register(fs)
+	if code, run := parse(fs, args, 0, 0); !run {
+		return code
+	}
+	if *plannerName != "rrt" && !starPlanner(*plannerName) {
+		return fail("rrt", exitUsage, errors.Errorf("unknown planner %q", *plannerName))
+	}
+	if view.enabled() && *plannerName != "rrt" {
+		return fail("rrt", exitUsage, errors.New("-serve only supports -planner rrt"))
+	}
+	if err := checkFormat(*format); err != nil {
+		return fail("rrt", exitUsage, err)
+	}
+
+	config, obstacles, robot, code := loadProblem("rrt", *configPath, *pIndex)
+	if code != exitOK {
+		return code
+	}
+	if *polygonsPath != "" && (robot == nil || footprint.slices == 0) {
+		return fail("rrt", exitUsage, errors.New("-polygons needs -cobstacles and a config with a robot"))
+	}
+	polygons, err := readPolygons(*polygonsPath)
+	if err != nil {
+		return fail("rrt", exitInput, err)
+	}
+	footprint.polygons = polygons
+	if *plannerName == "tangent" && robot != nil {
+		return fail("rrt", exitUsage, errors.New("-planner tangent needs a config without a robot"))
+	}
+	if *seed == 0 {
+		*seed = time.Now().UnixNano()
+	}
+
+	p := config.Problems[*pIndex]
+	var onAdd func(*planner.Vertex)
+	if view.enabled() {
+		if err := view.start("rrt", p, config.ConfigSpace, obstacles, robot); err != nil {
+			return fail("rrt", exitInput, err)
+		}
+		onAdd = view.add
+	}
+	ctx, cancel := lim.context()
+	defer cancel()
+	var solution *planner.Solution
+	if *plannerName == "rrt" {
+		solution, err = solveRRT(ctx, p, config.ConfigSpace, obstacles, robot, footprint, *seed, lim.maxIter, onAdd)
+	} else {
+		solution, err = solveStar(ctx, *plannerName, p, config.ConfigSpace, obstacles, robot, footprint, *seed, lim.maxIter, star)
+	}
+
+	// The partial tree is written even if no solution was found.
+	result := planner.Result{Problem: p, Planner: *plannerName, Seed: *seed, Solution: solution, Err: err}
+	if err := writeResult(*outPath, *format, result, *reportEvery); err != nil {
+		return fail("rrt", exitInput, err)
+	}
+	if view.enabled() {
+		defer view.finish("rrt", solution, err)
+	}
+	if err != nil {
+		return fail("rrt", exitNoSolution, err)
+	}
+	return exitOK
+}
+
+// solveRRT solves a geometric problem with RRT. The robot is a point if the
+// config has no robot file, as in hw2, and a footprint that plans in SE(2)
+// otherwise, as in hw3.
+// RRT gives up after maxIter iterations if it is non-zero, and calls onAdd
+// with every vertex added to the tree if it is non-nil.
+func solveRRT(ctx context.Context, p planner.Problem, cSpace planner.ConfigSpace, obstacles []planner.Circle, robot planner.Robot, footprint footprintOptions, seed int64, maxIter int, onAdd func(*planner.Vertex)) (*planner.Solution, error) {
+	rrt := planner.NewSE2RRT(p, cSpace, geometricChecker(obstacles, cSpace, robot, footprint), seed, footprint.se2(robot))
+	rrt.MaxIterations = maxIter
+	rrt.OnAdd = onAdd
+	solution, err := rrt.Plan(ctx)
+	return solution, errors.Wrap(err, "rrt failed")
+}
+
+// starOptions are the settings of the planners of plan rrt that shorten the
+// path beyond the first one found.
+type starOptions struct {
+	budget  time.Duration
+	samples int // states sampled by fmtstar, or per batch by bitstar, if non-zero
+}
+
+// defaultStar are the starOptions unless set by flags.
+var defaultStar = starOptions{budget: 5 * time.Second}
+
+// starPlanner reports whether name is one of the planners solved by
+// solveStar.
+func starPlanner(name string) bool {
+	return name == "rrtstar" || name == "informed" || name == "bitstar" || name == "fmtstar" || name == "tangent"
+}
+
+// solveStar solves a geometric problem with RRT*, informed RRT*, BIT*, FMT* or
+// the tangent graph, as named by a starPlanner. The tangent graph only solves
+// problems for point robots. The sampling planners give up after maxIter
+// iterations if it is non-zero.
+func solveStar(ctx context.Context, name string, p planner.Problem, cSpace planner.ConfigSpace, obstacles []planner.Circle, robot planner.Robot, footprint footprintOptions, seed int64, maxIter int, opts starOptions) (*planner.Solution, error) {
+	checker := geometricChecker(obstacles, cSpace, robot, footprint)
+	se2 := footprint.se2(robot)
+	var pl planner.Planner
+	switch name {
+	case "tangent":
+		if robot != nil {
+			return nil, errors.New("tangent only plans for point robots")
+		}
+		pl = planner.NewTangentGraph(p, cSpace, obstacles)
+	case "bitstar":
+		params := planner.BITStarParams{Budget: opts.budget, BatchSize: 100, MaxIterations: maxIter, SE2: se2}
+		if opts.samples > 0 {
+			params.BatchSize = opts.samples
+		}
+		pl = planner.NewBITStar(p, cSpace, checker, seed, params)
+	case "fmtstar":
+		params := planner.FMTStarParams{Samples: 2000, MaxIterations: maxIter, SE2: se2}
+		if opts.samples > 0 {
+			params.Samples = opts.samples
+		}
+		pl = planner.NewFMTStar(p, cSpace, checker, seed, params)
+	default:
+		params := planner.RRTStarParams{Budget: opts.budget, MaxIterations: maxIter, Informed: name == "informed", SE2: se2}
+		pl = planner.NewRRTStar(p, cSpace, checker, seed, params)
+	}
+	solution, err := pl.Plan(ctx)
+	return solution, errors.Wrapf(err, "%s failed", name)
+}
+
+// footprintOptions configure the collision checking and SE(2) steering of a
+// robot footprint.
+type footprintOptions struct {
+	resolution float64           // distance between collision checks along a motion
+	slices     int               // θ slices of the C-obstacles to check against, or 0 to check every point of the footprint
+	hull       bool              // build the C-obstacles of the convex hull of the footprint instead of its points
+	turnWeight float64           // distance equivalent of turning by a radian, or 0 for the reach of the footprint
+	maxTurn    float64           // largest turn of a step toward a sample, or 0 for no limit
+	polygons   []planner.Polygon // obstacles besides the circles of the config, only checked with C-obstacles
+}
+
+// defaultFootprint are the footprintOptions unless set by flags.
+var defaultFootprint = footprintOptions{resolution: 0.5, maxTurn: math.Pi / 4}
+
+func (f *footprintOptions) register(fs *flag.FlagSet) {
+	fs.Float64Var(&f.resolution, "resolution", f.resolution, "distance between collision checks along a motion, used by the geometric planners if the config has a robot")
+	fs.IntVar(&f.slices, "cobstacles", f.slices, "check the robot against C-obstacles built for this many headings instead of checking every point of the footprint")
+	fs.BoolVar(&f.hull, "hull", f.hull, "build the C-obstacles of -cobstacles for the convex hull of the footprint")
+	fs.Float64Var(&f.turnWeight, "turn-weight", f.turnWeight, "distance equivalent of turning by one radian if the config has a robot (default the largest distance from the center to a point of the footprint)")
+	fs.Float64Var(&f.maxTurn, "max-turn", f.maxTurn, "largest turn in radians of a step toward a sample if the config has a robot, 0 for no limit")
+}
+
+// se2 returns the state space of the robot, which ignores the heading of a
+// point robot.
+func (f footprintOptions) se2(robot planner.Robot) planner.SE2 {
+	se2 := robot.SE2()
+	if robot == nil {
+		return se2
+	}
+	if f.turnWeight > 0 {
+		se2.TurnWeight = f.turnWeight
+	}
+	se2.MaxTurn = f.maxTurn
+	return se2
+}
+
+// geometricChecker returns the collision checker of a point robot if robot is
+// nil, and of its footprint otherwise.
+func geometricChecker(obstacles []planner.Circle, cSpace planner.ConfigSpace, robot planner.Robot, f footprintOptions) planner.CollisionChecker {
+	if robot == nil {
+		return planner.PointChecker{Obstacles: obstacles, Space: cSpace}
+	}
+	if f.slices > 0 {
+		return planner.CObstacleChecker{
+			CObstacles: planner.NewCObstacles(cSpace, obstacles, f.polygons, f.footprint(robot), f.slices),
+			Resolution: f.resolution,
+		}
+	}
+	return planner.FootprintChecker{
+		Obstacles:  obstacles,
+		Space:      cSpace,
+		Robot:      robot,
+		Resolution: f.resolution,
+	}
+}
+
+// footprint returns the footprint to build C-obstacles for.
+func (f footprintOptions) footprint(robot planner.Robot) planner.Footprint {
+	if f.hull {
+		return planner.HullFootprint(robot)
+	}
+	return planner.PointFootprint(robot)
+}
+
+// writeResult writes the result of a planner in format to path, or to stdout
+// if path is empty.
+func writeResult(path, format string, r planner.Result, reportEvery int) error {
+	f, err := create(path)
+	if err != nil {
+		return errors.Wrap(err, "could not create output file")
+	}
+	if format == "json" {
+		err = planner.WriteJSON(f, r)
+	} else {
+		err = planner.WriteText(f, r.Problem, r.Solution, reportEvery)
+	}
+	if err != nil {
+		f.Close()
+		return err
+	}
+	return errors.Wrap(f.Close(), "could not close output file")
+}
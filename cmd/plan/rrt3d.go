@@ -0,0 +1,113 @@
+package main
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/hdhauk/enae788v/planner"
+	"github.com/hdhauk/enae788v/render"
+)
+
+// rrt3dMain plans for a robot translating in 3D space with RRT or RRT*, and
+// writes the result as JSON. The result can be drawn from above, the front,
+// the side or at an angle.
+func rrt3dMain(args []string) int {
+	fs := newFlagSet("rrt3d", "")
+	configPath := fs.String("c", "drone/problems.json", "3D config file")
+	pIndex := fs.Int("p", 0, "which problem in config file to solve (0-indexed)")
+	seed := fs.Int64("seed", 0, "seed for the random sampling (default current time)")
+	outPath := fs.String("o", "", "output path for the json result (default stdout)")
+	plannerName := fs.String("planner", "rrt", "planner to use: rrt (first path found), rrtstar or informed (informed rrt*)")
+	star := defaultStar
+	fs.DurationVar(&star.budget, "budget", star.budget, "time budget for rrtstar and informed")
+	figurePath := fs.String("figure", "", "output path for a figure of the result, svg or png by the extension (default none)")
+	view := fs.String("view", string(render.ViewIso), "view of the figure: top, front, side or iso")
+	opts := render.DefaultOptions
+	registerRenderOptions(fs, &opts)
+	var lim limits
+	lim.register(fs)
+	if code, run := parse(fs, args, 0, 0); !run {
+		return code
+	}
+	if *plannerName != "rrt" && *plannerName != "rrtstar" && *plannerName != "informed" {
+		return fail("rrt3d", exitUsage, errors.Errorf("unknown planner %q", *plannerName))
+	}
+	switch render.View(*view) {
+	case render.ViewTop, render.ViewFront, render.ViewSide, render.ViewIso:
+	default:
+		return fail("rrt3d", exitUsage, errors.Errorf("unknown view %q", *view))
+	}
+
+	config, err := planner.LoadConfig3D(*configPath)
+	infeasible, isInfeasible := err.(planner.FeasibilityError)
+	if err != nil && !isInfeasible {
+		return fail("rrt3d", exitInput, err)
+	}
+	if *pIndex < 0 || *pIndex >= len(config.Problems) {
+		return fail("rrt3d", exitUsage, errors.Errorf("invalid problem number %d, the config has %d problems", *pIndex, len(config.Problems)))
+	}
+	if err := infeasible.Problem(*pIndex); err != nil {
+		return fail("rrt3d", exitInput, err)
+	}
+	if *seed == 0 {
+		*seed = time.Now().UnixNano()
+	}
+
+	p := config.Problems[*pIndex]
+	var pl planner.Planner
+	if *plannerName == "rrt" {
+		rrt := planner.NewRRT3D(p, config.ConfigSpace, config.Checker(), *seed)
+		rrt.MaxIterations = lim.maxIter
+		pl = rrt
+	} else {
+		params := planner.RRTStarParams{Budget: star.budget, MaxIterations: lim.maxIter, Informed: *plannerName == "informed"}
+		pl = planner.NewRRTStar3D(p, config.ConfigSpace, config.Checker(), *seed, params)
+	}
+	ctx, cancel := lim.context()
+	defer cancel()
+	solution, err := pl.Plan(ctx)
+	err = errors.Wrapf(err, "%s failed", *plannerName)
+
+	// The partial tree is written and drawn even if no solution was found.
+	result := planner.Result{Problem3D: &p, Planner: *plannerName, Seed: *seed, Solution: solution, Err: err}
+	if err := writeResult(*outPath, "json", result, 0); err != nil {
+		return fail("rrt3d", exitInput, err)
+	}
+	if *figurePath != "" {
+		scene := render.Scene3D{
+			Space:    config.ConfigSpace,
+			Spheres:  config.Spheres,
+			Boxes:    config.Boxes,
+			Problem:  p,
+			Robot:    config.Robot,
+			Solution: solution,
+		}
+		if err := renderFile3D(*figurePath, scene, render.View(*view), opts); err != nil {
+			return fail("rrt3d", exitInput, err)
+		}
+	}
+	if err != nil {
+		return fail("rrt3d", exitNoSolution, err)
+	}
+	return exitOK
+}
+
+// renderFile3D draws a 3D scene seen from view to path, as png if it has the
+// extension .png and as svg otherwise.
+func renderFile3D(path string, scene render.Scene3D, view render.View, opts render.Options) error {
+	f, err := create(path)
+	if err != nil {
+		return errors.Wrap(err, "could not create figure")
+	}
+	if figureFormat("", path) == "png" {
+		err = render.PNG3D(f, scene, view, opts)
+	} else {
+		err = render.SVG3D(f, scene, view, opts)
+	}
+	if err != nil {
+		f.Close()
+		return err
+	}
+	return errors.Wrap(f.Close(), "could not close figure")
+}
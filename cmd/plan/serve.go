@@ -0,0 +1,248 @@
+package main
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/hdhauk/enae788v/planner"
+)
+
+//go:embed viewer.html
+var viewerHTML []byte
+
+// viewer streams the growth of an RRT tree to browsers as server-sent events
+// while planning, and serves a page that draws them.
+//
+// The events are JSON objects with a type field:
+//
+//	problem  {bounds: {xmin, xmax, ymin, ymax}, obstacles, start, goal, robot}
+//	vertex   {id, parent, x, y, theta, edge: [[x, y], ...]}, parent is -1 for the root
+//	done     {solved, reason, cost, path: [[x, y], ...]}
+//
+// Every browser gets the events from the start, so a page opened late or
+// reloaded shows the whole tree.
+type viewer struct {
+	addr  string
+	delay time.Duration
+	cmd   string
+
+	server *http.Server
+	ids    map[*planner.Vertex]int
+
+	mu        sync.Mutex
+	events    [][]byte
+	changed   chan struct{} // closed when an event is published
+	connected chan struct{} // closed when the first browser connects
+	once      sync.Once
+}
+
+func (v *viewer) register(fs *flag.FlagSet) {
+	fs.StringVar(&v.addr, "serve", "", "stream the tree to a browser at this address, such as 127.0.0.1:8080, while planning; "+
+		"planning waits for a browser to connect, and the viewer is served after planning until interrupted. "+
+		"An address without a host is served on 127.0.0.1 only")
+	fs.DurationVar(&v.delay, "serve-delay", 5*time.Millisecond, "pause after every vertex added to the tree with -serve, to watch it grow")
+}
+
+func (v *viewer) enabled() bool {
+	return v.addr != ""
+}
+
+type viewerProblem struct {
+	Type      string           `json:"type"`
+	Bounds    viewerBounds     `json:"bounds"`
+	Obstacles []planner.Circle `json:"obstacles"`
+	Start     planner.Point    `json:"start"`
+	Goal      planner.Circle   `json:"goal"`
+	Robot     planner.Robot    `json:"robot"`
+}
+
+type viewerBounds struct {
+	XMin float64 `json:"xmin"`
+	XMax float64 `json:"xmax"`
+	YMin float64 `json:"ymin"`
+	YMax float64 `json:"ymax"`
+}
+
+type viewerVertex struct {
+	Type   string       `json:"type"`
+	ID     int          `json:"id"`
+	Parent int          `json:"parent"`
+	X      float64      `json:"x"`
+	Y      float64      `json:"y"`
+	Theta  float64      `json:"theta"`
+	Edge   [][2]float64 `json:"edge"`
+}
+
+type viewerDone struct {
+	Type   string       `json:"type"`
+	Solved bool         `json:"solved"`
+	Reason string       `json:"reason,omitempty"`
+	Cost   float64      `json:"cost,omitempty"`
+	Path   [][2]float64 `json:"path"`
+}
+
+// start serves the viewer and blocks until a browser connects, so that it
+// sees the tree grow from the root.
+func (v *viewer) start(cmd string, p planner.Problem, cSpace planner.ConfigSpace, obstacles []planner.Circle, robot planner.Robot) error {
+	addr, err := listenAddr(v.addr)
+	if err != nil {
+		return err
+	}
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return errors.Wrap(err, "could not serve viewer")
+	}
+	v.cmd = cmd
+	v.ids = map[*planner.Vertex]int{}
+	v.changed = make(chan struct{})
+	v.connected = make(chan struct{})
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write(viewerHTML)
+	})
+	mux.HandleFunc("/events", v.serveEvents)
+	v.server = &http.Server{Handler: mux}
+	go v.server.Serve(ln)
+
+	v.publish(viewerProblem{
+		Type:      "problem",
+		Bounds:    viewerBounds{cSpace.XMin, cSpace.XMax, cSpace.YMin, cSpace.YMax},
+		Obstacles: obstacles,
+		Start:     p.Start,
+		Goal:      p.Goal,
+		Robot:     robot,
+	})
+	fmt.Fprintf(os.Stderr, "plan %s: open http://%s in a browser to start planning\n", cmd, browseAddr(ln.Addr()))
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	select {
+	case <-v.connected:
+		return nil
+	case <-ctx.Done():
+		v.server.Close()
+		return errors.New("interrupted before a browser connected")
+	}
+}
+
+// add publishes a vertex added to the tree and pauses to let it be seen.
+func (v *viewer) add(vertex *planner.Vertex) {
+	id := len(v.ids)
+	v.ids[vertex] = id
+	p := planner.Pose(vertex.State)
+	e := viewerVertex{Type: "vertex", ID: id, Parent: -1, X: p.X, Y: p.Y, Theta: p.Theta}
+	if vertex.Parent != nil {
+		e.Parent = v.ids[vertex.Parent]
+		e.Edge = xy(planner.PathStates([]*planner.Motion{vertex.Motion}))
+	}
+	v.publish(e)
+	time.Sleep(v.delay)
+}
+
+// finish publishes the result and serves the viewer until interrupted.
+func (v *viewer) finish(cmd string, sol *planner.Solution, err error) {
+	e := viewerDone{Type: "done", Solved: err == nil}
+	if err != nil {
+		e.Reason = err.Error()
+	} else {
+		e.Cost = sol.Cost
+		e.Path = xy(planner.PathStates(sol.Path))
+	}
+	v.publish(e)
+
+	fmt.Fprintf(os.Stderr, "plan %s: planning done, serving the viewer until interrupted\n", cmd)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	<-ctx.Done()
+	v.server.Close()
+}
+
+func (v *viewer) publish(e interface{}) {
+	b, err := json.Marshal(e)
+	if err != nil {
+		// Only non-finite numbers fail, and the viewer can do without them.
+		fmt.Fprintf(os.Stderr, "plan %s: could not send %T to the viewer: %v\n", v.cmd, e, err)
+		return
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.events = append(v.events, b)
+	close(v.changed)
+	v.changed = make(chan struct{})
+}
+
+// since returns the events after the first n, and a channel that is closed
+// when more are published.
+func (v *viewer) since(n int) ([][]byte, <-chan struct{}) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.events[n:len(v.events):len(v.events)], v.changed
+}
+
+func (v *viewer) serveEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	v.once.Do(func() { close(v.connected) })
+
+	for sent := 0; ; {
+		events, changed := v.since(sent)
+		for _, e := range events {
+			fmt.Fprintf(w, "data: %s\n\n", e)
+		}
+		sent += len(events)
+		flusher.Flush()
+		select {
+		case <-changed:
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// xy returns the positions of states.
+func xy(states []planner.State) [][2]float64 {
+	pts := make([][2]float64, len(states))
+	for i, s := range states {
+		p := planner.Pose(s)
+		pts[i] = [2]float64{p.X, p.Y}
+	}
+	return pts
+}
+
+// listenAddr returns addr with the host defaulting to 127.0.0.1, so that
+// the viewer is only reachable from other machines when asked for.
+func listenAddr(addr string) (string, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return "", errors.Wrap(err, "invalid -serve address")
+	}
+	if host == "" {
+		host = "127.0.0.1"
+	}
+	return net.JoinHostPort(host, port), nil
+}
+
+// browseAddr returns a listening address as it is opened in a browser.
+func browseAddr(addr net.Addr) string {
+	if tcp, ok := addr.(*net.TCPAddr); ok && tcp.IP.IsUnspecified() {
+		return fmt.Sprintf("localhost:%d", tcp.Port)
+	}
+	return addr.String()
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/pkg/errors"
+
+	"github.com/hdhauk/enae788v/planner"
+)
+
+// validateMain checks that every problem in a config is feasible without
+// planning. Given a trajectory csv, it instead re-simulates the trajectory
+// through the dynamics and fails if any constraint or collision is violated.
+func validateMain(args []string) int {
+	fs := newFlagSet("validate", "[trajectory.csv]")
+	configPath := fs.String("c", "hw4/problems.json", "config file")
+	pIndex := fs.Int("p", 0, "which problem in config file the trajectory solves (0-indexed)")
+	precision := fs.Int("precision", 4, "number of decimals the trajectory csv was written with")
+	tolerance := fs.Float64("tol", 0, "tolerance when comparing recorded and simulated states (default derived from -precision)")
+	if code, run := parse(fs, args, 0, 1); !run {
+		return code
+	}
+
+	config, obstacles, robot, err := planner.LoadConfig(*configPath)
+	infeasible, isInfeasible := err.(planner.FeasibilityError)
+	if err != nil && !isInfeasible {
+		return fail("validate", exitInput, err)
+	}
+	if fs.NArg() == 0 {
+		return validateConfig(config, infeasible)
+	}
+	if *pIndex < 0 || *pIndex >= len(config.Problems) {
+		return fail("validate", exitUsage, errors.Errorf("invalid problem number %d, the config has %d problems", *pIndex, len(config.Problems)))
+	}
+
+	f, err := os.Open(fs.Arg(0))
+	if err != nil {
+		return fail("validate", exitInput, errors.Wrap(err, "could not open trajectory file"))
+	}
+	defer f.Close()
+	rows, err := planner.ReadTrajectory(f)
+	if err != nil {
+		return fail("validate", exitInput, errors.Wrap(err, "could not read trajectory"))
+	}
+
+	if *tolerance == 0 {
+		*tolerance = planner.PrecisionTolerance(*precision)
+	}
+	checker := kinodynamicChecker(obstacles, config.ConfigSpace, robot)
+	violations := planner.ValidateTrajectory(rows, config.Problems[*pIndex], config.ConfigSpace, checker, *tolerance)
+	for _, v := range violations {
+		fmt.Println(v)
+	}
+	if len(violations) > 0 {
+		fmt.Printf("INVALID: %d violation(s) in %d rows\n", len(violations), len(rows))
+		return exitNoSolution
+	}
+	fmt.Printf("OK: %d rows\n", len(rows))
+	return exitOK
+}
+
+// validateConfig reports the feasibility of every problem in a config.
+func validateConfig(config *planner.Config, infeasible planner.FeasibilityError) int {
+	for i, prob := range config.Problems {
+		if infeasible.Problem(i) == nil {
+			fmt.Printf("problem %d (%s): OK\n", i, prob.Name)
+		}
+	}
+	for _, issue := range infeasible {
+		fmt.Println(issue)
+	}
+	if len(infeasible) > 0 {
+		fmt.Printf("INFEASIBLE: %d issue(s) in %d problems\n", len(infeasible), len(config.Problems))
+		return exitInput
+	}
+	fmt.Printf("OK: %d problems\n", len(config.Problems))
+	return exitOK
+}
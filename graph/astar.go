@@ -0,0 +1,136 @@
+package graph
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Heuristic estimates the cost from u to the goal.
+type Heuristic func(u, goal *Vertex) float64
+
+// SearchResult is the shortest path found by AStar, and every vertex that was
+// expanded while searching for it.
+type SearchResult struct {
+	Path       []*Vertex
+	SearchTree []*Vertex
+	PathCost   float64
+	Elapsed    time.Duration
+}
+
+// AStar searches for the shortest path from start to goal. With a heuristic
+// that always returns zero it is equal to Dijkstra's algorithm. The search
+// tree is returned together with the error if the goal can not be reached.
+func AStar(vertices map[int]*Vertex, start, goal int, h Heuristic) (*SearchResult, error) {
+	started := time.Now()
+	unvisited := make(map[int]bool)
+	for k := range vertices {
+		unvisited[k] = true
+	}
+	delete(unvisited, start)
+
+	Q := NewQueue()
+	Q.PushVertex(vertices[start])
+
+	goalVertex := vertices[goal]
+	searchTree := []*Vertex{}
+	var success bool
+mainLoop:
+	for Q.Peek() != nil {
+		v := Q.PopVertex()
+		searchTree = append(searchTree, v)
+
+		for neighID, d := range v.Neighbors {
+			u := vertices[neighID]
+			_, notSeen := unvisited[u.ID]
+			newShorterDistance := u.CostToStart > v.CostToStart+d
+			if notSeen || newShorterDistance {
+				delete(unvisited, neighID)
+				u.Finite = true
+				u.Parent = v
+				u.CostToStart = v.CostToStart + d
+				u.Priority = u.CostToStart + h(u, goalVertex)
+				if Q.InQueue(u) {
+					Q.UpdateVertex(u)
+				} else {
+					Q.PushVertex(u)
+				}
+			}
+			if v.ID == goal {
+				goalVertex = v
+				success = true
+				break mainLoop
+			}
+		}
+
+	}
+	if !success {
+		res := &SearchResult{SearchTree: searchTree, Elapsed: time.Since(started)}
+		return res, errors.New("algorithm did not find the goal")
+	}
+
+	startToFinish, pathCost := reconstructPath(vertices[start], goalVertex)
+
+	results := &SearchResult{
+		Path:       startToFinish,
+		PathCost:   pathCost,
+		SearchTree: searchTree,
+		Elapsed:    time.Since(started),
+	}
+
+	return results, nil
+}
+
+func reconstructPath(start, goal *Vertex) ([]*Vertex, float64) {
+	// Walk backword along parent pointers
+	path := []*Vertex{goal}
+	next := goal.Parent
+	current := goal
+	for next.ID != start.ID {
+		path = append(path, next)
+		current = next
+		next = current.Parent
+	}
+	path = append(path, next)
+
+	// Reverse slice
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path, goal.CostToStart
+}
+
+// CartesianDistance is the straight line distance from u to the goal.
+func CartesianDistance(u, goal *Vertex) float64 {
+	dx, dy := goal.X-u.X, goal.Y-u.Y
+	return math.Sqrt(dx*dx + dy*dy)
+}
+
+// Zero is the heuristic that turns A* into Dijkstra's algorithm.
+func Zero(u, goal *Vertex) float64 {
+	return 0
+}
+
+// WriteSearchTree writes every expanded vertex except the start as a csv row
+// of its id and position, followed by the id and position of its parent.
+func WriteSearchTree(w io.Writer, tree []*Vertex) error {
+	bw := bufio.NewWriter(w)
+	for i := 1; i < len(tree); i++ {
+		v := tree[i]
+		fmt.Fprintf(bw, "%d, %f, %f, %d, %f, %f\n", v.ID, v.X, v.Y, v.Parent.ID, v.Parent.X, v.Parent.Y)
+	}
+	return errors.Wrap(bw.Flush(), "could not write search tree")
+}
+
+// WritePath writes the id and position of every vertex along the path.
+func WritePath(w io.Writer, path []*Vertex) error {
+	bw := bufio.NewWriter(w)
+	for _, v := range path {
+		fmt.Fprintf(bw, "%d, %f, %f\n", v.ID, v.X, v.Y)
+	}
+	return errors.Wrap(bw.Flush(), "could not write path")
+}
@@ -0,0 +1,68 @@
+package graph
+
+import (
+	"math"
+	"testing"
+)
+
+func TestAStar(t *testing.T) {
+	for _, h := range []Heuristic{CartesianDistance, Zero} {
+		problems, err := ReadProblems("../hw1/problems/problems.txt")
+		ok(t, err)
+		p := problems[0]
+
+		result, err := AStar(p.Vertices, p.StartID, p.GoalID, h)
+		ok(t, err)
+		assert(t, math.Abs(result.PathCost-84.229) < 1e-3, "expected cost 84.229, got %.3f", result.PathCost)
+		equals(t, p.StartID, result.Path[0].ID)
+		equals(t, p.GoalID, result.Path[len(result.Path)-1].ID)
+
+		var cost float64
+		for i := 1; i < len(result.Path); i++ {
+			cost += result.Path[i-1].Neighbors[result.Path[i].ID]
+		}
+		assert(t, math.Abs(result.PathCost-cost) < 1e-9, "path cost %f does not match edge weights %f", result.PathCost, cost)
+	}
+}
+
+// TestAStarProblems checks the shortest path lengths that hw1 reported for
+// every problem in the problem file.
+func TestAStarProblems(t *testing.T) {
+	problems, err := ReadProblems("../hw1/problems/problems.txt")
+	ok(t, err)
+	costs := []float64{84.229, 155.393, math.Inf(1), 94.848, 58.255, 141.406}
+	equals(t, len(costs), len(problems))
+
+	for i, p := range problems {
+		for _, h := range []Heuristic{CartesianDistance, Zero} {
+			result, err := AStar(p.Vertices, p.StartID, p.GoalID, h)
+			if math.IsInf(costs[i], 1) {
+				assert(t, err != nil, "problem %d: expected no path", i+1)
+				continue
+			}
+			ok(t, err)
+			assert(t, math.Abs(result.PathCost-costs[i]) < 1e-3, "problem %d: expected cost %.3f, got %.3f", i+1, costs[i], result.PathCost)
+		}
+	}
+}
+
+func TestAStarNoPath(t *testing.T) {
+	vertices := map[int]*Vertex{
+		1: {ID: 1, Neighbors: map[int]float64{2: 1}},
+		2: {ID: 2, X: 1, Neighbors: map[int]float64{1: 1}},
+		3: {ID: 3, X: 2, Neighbors: map[int]float64{}},
+	}
+	result, err := AStar(vertices, 1, 3, CartesianDistance)
+	assert(t, err != nil, "expected error for unreachable goal")
+	equals(t, 2, len(result.SearchTree))
+}
+
+func BenchmarkAStar(b *testing.B) {
+	problems, err := ReadProblems("../hw1/problems/problems.txt")
+	ok(b, err)
+	p := problems[0]
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		AStar(p.Vertices, p.StartID, p.GoalID, CartesianDistance)
+	}
+}
@@ -0,0 +1,84 @@
+package graph
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+// Result is a graph search, as written by WriteJSON.
+type Result struct {
+	Problem   *Problem
+	Algorithm string // "astar" or "dijkstra"
+	Search    *SearchResult
+	Err       error // error returned by AStar, nil if it found a path
+}
+
+type jsonResult struct {
+	Problem struct {
+		ID      int `json:"id"`
+		StartID int `json:"start_id"`
+		GoalID  int `json:"goal_id"`
+	} `json:"problem"`
+	Planner string       `json:"planner"`
+	Solved  bool         `json:"solved"`
+	Reason  string       `json:"reason,omitempty"`
+	Cost    *float64     `json:"cost"`
+	Path    []jsonVertex `json:"path"`
+	Tree    []jsonVertex `json:"tree"`
+	Stats   struct {
+		Expanded int     `json:"expanded"`
+		Elapsed  float64 `json:"elapsed_seconds"`
+	} `json:"stats"`
+}
+
+type jsonVertex struct {
+	ID     int     `json:"id"`
+	X      float64 `json:"x"`
+	Y      float64 `json:"y"`
+	Parent *int    `json:"parent,omitempty"`
+}
+
+// WriteJSON writes a graph search as a JSON object with the fields
+//
+//	problem  "id", "start_id" and "goal_id" of the problem
+//	planner  "astar" or "dijkstra"
+//	solved   true if a path to the goal was found
+//	reason   why no path was found, omitted if solved
+//	cost     length of the path, or null if not solved
+//	path     "id", "x" and "y" of the vertices from the start to the goal
+//	tree     the expanded vertices in the order they were expanded, with the
+//	         id of their "parent" except for the start
+//	stats    number of "expanded" vertices and "elapsed_seconds"
+func WriteJSON(w io.Writer, r Result) error {
+	var out jsonResult
+	out.Problem.ID, out.Problem.StartID, out.Problem.GoalID = r.Problem.ID, r.Problem.StartID, r.Problem.GoalID
+	out.Planner = r.Algorithm
+	out.Solved = r.Err == nil
+	if r.Err != nil {
+		out.Reason = r.Err.Error()
+	} else {
+		cost := r.Search.PathCost
+		out.Cost = &cost
+	}
+
+	out.Path = []jsonVertex{}
+	for _, v := range r.Search.Path {
+		out.Path = append(out.Path, jsonVertex{ID: v.ID, X: v.X, Y: v.Y})
+	}
+	out.Tree = []jsonVertex{}
+	for i, v := range r.Search.SearchTree {
+		jv := jsonVertex{ID: v.ID, X: v.X, Y: v.Y}
+		if i > 0 {
+			jv.Parent = &v.Parent.ID
+		}
+		out.Tree = append(out.Tree, jv)
+	}
+	out.Stats.Expanded = len(r.Search.SearchTree)
+	out.Stats.Elapsed = r.Search.Elapsed.Seconds()
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return errors.Wrap(enc.Encode(out), "could not write result")
+}
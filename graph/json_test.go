@@ -0,0 +1,36 @@
+package graph
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestWriteJSON(t *testing.T) {
+	vertices := map[int]*Vertex{
+		1: {ID: 1, Neighbors: map[int]float64{2: 1}},
+		2: {ID: 2, X: 1, Neighbors: map[int]float64{1: 1, 3: 2}},
+		3: {ID: 3, X: 1, Y: 2, Neighbors: map[int]float64{2: 2}},
+	}
+	p := &Problem{ID: 1, Vertices: vertices, StartID: 1, GoalID: 3}
+	search, err := AStar(vertices, 1, 3, CartesianDistance)
+	ok(t, err)
+
+	var buf bytes.Buffer
+	ok(t, WriteJSON(&buf, Result{Problem: p, Algorithm: "astar", Search: search}))
+
+	var out struct {
+		Problem map[string]int
+		Solved  bool
+		Cost    float64
+		Path    []jsonVertex
+		Tree    []jsonVertex
+	}
+	ok(t, json.Unmarshal(buf.Bytes(), &out))
+	equals(t, map[string]int{"id": 1, "start_id": 1, "goal_id": 3}, out.Problem)
+	assert(t, out.Solved, "expected solved")
+	equals(t, 3.0, out.Cost)
+	equals(t, []jsonVertex{{ID: 1}, {ID: 2, X: 1}, {ID: 3, X: 1, Y: 2}}, out.Path)
+	assert(t, out.Tree[0].Parent == nil, "start should have no parent")
+	equals(t, 1, *out.Tree[1].Parent)
+}
@@ -1,12 +1,15 @@
-package main
+package graph
 
 import "container/heap"
 
+// Queue is a priority queue of vertices, ordered by increasing Priority. It
+// implements heap.Interface.
 type Queue struct {
 	vertices []*Vertex
 	inQueue  map[*Vertex]bool
 }
 
+// NewQueue returns an empty queue.
 func NewQueue() *Queue {
 	q := Queue{inQueue: make(map[*Vertex]bool)}
 	heap.Init(&q)
@@ -20,8 +23,8 @@ func (q *Queue) Len() int {
 func (q *Queue) Less(i, j int) bool {
 	vi := q.vertices[i]
 	vj := q.vertices[j]
-	if vi.finite && vj.finite {
-		return vi.priority < vj.priority
+	if vi.Finite && vj.Finite {
+		return vi.Priority < vj.Priority
 	}
 	return true
 }
@@ -32,6 +35,7 @@ func (q *Queue) Swap(i, j int) {
 	q.vertices[j].index = j
 }
 
+// Push is used by the heap package. Use PushVertex instead.
 func (q *Queue) Push(x interface{}) {
 	v := x.(*Vertex)
 	if q.InQueue(v) {
@@ -41,8 +45,10 @@ func (q *Queue) Push(x interface{}) {
 	n := len(q.vertices)
 	v.index = n
 	q.vertices = append(q.vertices, v)
+	q.inQueue[v] = true
 }
 
+// PushVertex adds v to the queue, unless it is already queued.
 func (q *Queue) PushVertex(v *Vertex) {
 	if q.InQueue(v) {
 		return
@@ -51,6 +57,8 @@ func (q *Queue) PushVertex(v *Vertex) {
 
 }
 
+// PopVertex removes and returns the vertex with the lowest priority, or nil if
+// the queue is empty.
 func (q *Queue) PopVertex() *Vertex {
 	if len(q.vertices) == 0 {
 		return nil
@@ -58,6 +66,7 @@ func (q *Queue) PopVertex() *Vertex {
 	return heap.Pop(q).(*Vertex)
 }
 
+// Pop is used by the heap package. Use PopVertex instead.
 func (q *Queue) Pop() interface{} {
 	oldVertices := q.vertices
 	n := len(oldVertices)
@@ -68,7 +77,7 @@ func (q *Queue) Pop() interface{} {
 	return v
 }
 
-// Updates the queue position of vertex v. If not in queue, do nothing
+// UpdateVertex updates the queue position of vertex v. If not in queue, do nothing
 func (q *Queue) UpdateVertex(v *Vertex) {
 	if !q.InQueue(v) {
 		return
@@ -76,11 +85,14 @@ func (q *Queue) UpdateVertex(v *Vertex) {
 	heap.Fix(q, v.index)
 }
 
+// InQueue returns true if v is in the queue.
 func (q *Queue) InQueue(v *Vertex) bool {
 	_, alreadyInQ := q.inQueue[v]
 	return alreadyInQ
 }
 
+// Peek returns the vertex with the lowest priority without removing it, or
+// nil if the queue is empty.
 func (q *Queue) Peek() *Vertex {
 	if len(q.vertices) == 0 {
 		return nil
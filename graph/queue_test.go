@@ -0,0 +1,108 @@
+package graph
+
+import (
+	"container/heap"
+	"fmt"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+var rng = rand.New(rand.NewSource(time.Now().UnixNano()))
+
+func RandomFloat64(min int, max int) float64 {
+	p := rng.Perm(max - min + 1)
+	d := rng.Float64()
+	return float64(p[min]) + d
+}
+
+func TestRandomQueue(t *testing.T) {
+	q := NewQueue()
+
+	for i := 0; i < 100; i++ {
+		v := &Vertex{
+			ID:       i + 1,
+			Priority: RandomFloat64(1, 1000),
+			Finite:   true,
+		}
+		q.PushVertex(v)
+	}
+
+	largest := 0.0
+	for len(q.vertices) > 0 {
+		v := heap.Pop(q).(*Vertex)
+		if v.Priority < largest {
+			t.Errorf("v.distance smaller. Got %.4f, largest seen %.4f", v.Priority, largest)
+		}
+		largest = v.Priority
+	}
+}
+
+func TestChangingQueue(t *testing.T) {
+	q := NewQueue()
+
+	var u *Vertex
+	for i := 0; i < 100; i++ {
+		v := &Vertex{
+			ID:       i + 1,
+			Priority: RandomFloat64(1, 10),
+			Finite:   true,
+		}
+		if i == 50 {
+			u = v
+			fmt.Println(v)
+		}
+		heap.Push(q, v)
+	}
+	magicNumber := 69.69
+	fmt.Println(u)
+	u.Priority = magicNumber
+	heap.Fix(q, u.index)
+
+	largest := 0.0
+	for len(q.vertices) > 0 {
+		v := heap.Pop(q).(*Vertex)
+		if v.Priority < largest {
+			t.Errorf("v.distance smaller. Got %.4f, largest seen %.4f", v.Priority, largest)
+		}
+		largest = v.Priority
+	}
+	if largest != magicNumber {
+		t.Errorf("Expected largest distance to be &%.2f, but found %.4f", magicNumber, largest)
+	}
+}
+
+func TestQueueDuplicatePush(t *testing.T) {
+	q := NewQueue()
+	v := &Vertex{ID: 1, Priority: 1, Finite: true}
+	q.PushVertex(v)
+	q.PushVertex(v)
+	equals(t, 1, q.Len())
+	assert(t, q.InQueue(v), "expected vertex to be queued")
+
+	equals(t, v, q.PopVertex())
+	assert(t, !q.InQueue(v), "expected vertex to leave the queue")
+	assert(t, q.PopVertex() == nil, "expected empty queue")
+}
+
+func TestQueueDecreaseKey(t *testing.T) {
+	q := NewQueue()
+	var vertices []*Vertex
+	for i := 0; i < 10; i++ {
+		v := &Vertex{ID: i + 1, Priority: float64(i + 1), Finite: true}
+		vertices = append(vertices, v)
+		q.PushVertex(v)
+	}
+
+	last := vertices[len(vertices)-1]
+	last.Priority = 0
+	q.UpdateVertex(last)
+	equals(t, last, q.PopVertex())
+
+	largest := 0.0
+	for q.Len() > 0 {
+		v := q.PopVertex()
+		assert(t, v.Priority >= largest, "popped priority %.1f after %.1f", v.Priority, largest)
+		largest = v.Priority
+	}
+}
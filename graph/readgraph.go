@@ -1,4 +1,4 @@
-package main
+package graph
 
 import (
 	"bufio"
@@ -12,14 +12,17 @@ import (
 	"github.com/pkg/errors"
 )
 
-type problem struct {
-	id       int
-	vertices map[int]*Vertex
-	startID  int
-	goalID   int
+// Problem is a shortest path problem between two vertices of a graph.
+type Problem struct {
+	ID       int
+	Vertices map[int]*Vertex
+	StartID  int
+	GoalID   int
 }
 
-func readProblems(filePath string) ([]*problem, error) {
+// ReadProblems reads a problem file, where every problem refers to a node
+// file and an edge file relative to it.
+func ReadProblems(filePath string) ([]*Problem, error) {
 	dat, err := ioutil.ReadFile(filePath)
 	if err != nil {
 		return nil, errors.Wrap(err, "could not read file")
@@ -38,7 +41,7 @@ func readProblems(filePath string) ([]*problem, error) {
 	startIDRegex := regexp.MustCompile(`start\snode ID:\s*(\d*)`)
 	goalIDRegex := regexp.MustCompile(`goal\snode ID:\s*(\d*)`)
 
-	var problems []*problem
+	var problems []*Problem
 	for _, chunk := range chunks {
 		matches := problemIDRegex.FindAllStringSubmatch(chunk, 1)
 		if len(matches) < 1 {
@@ -94,11 +97,11 @@ func readProblems(filePath string) ([]*problem, error) {
 			log.Println("could not parse goal ID")
 		}
 
-		p := &problem{
-			id:       id,
-			startID:  startID,
-			goalID:   goalID,
-			vertices: vertices,
+		p := &Problem{
+			ID:       id,
+			StartID:  startID,
+			GoalID:   goalID,
+			Vertices: vertices,
 		}
 		problems = append(problems, p)
 	}
@@ -145,10 +148,10 @@ func readVertices(filePath string) (map[int]*Vertex, error) {
 			continue
 		}
 		v := &Vertex{
-			id:        id,
-			x:         x,
-			y:         y,
-			neighbors: make(map[int]float64),
+			ID:        id,
+			X:         x,
+			Y:         y,
+			Neighbors: make(map[int]float64),
 		}
 		vertices[id] = v
 	}
@@ -159,22 +162,17 @@ func readVertices(filePath string) (map[int]*Vertex, error) {
 	return vertices, nil
 }
 
-type edge struct {
-	tail, head int
-	distance   float64
-}
-
-func addNeighborsToVertices(vertices map[int]*Vertex, edges []edge) {
+func addNeighborsToVertices(vertices map[int]*Vertex, edges []Edge) {
 	for _, v := range vertices {
 		for _, e := range edges {
-			if e.tail == v.id {
-				v.neighbors[e.head] = e.distance
+			if e.Tail == v.ID {
+				v.Neighbors[e.Head] = e.Weight
 			}
 		}
 	}
 }
 
-func readEdges(filePath string) ([]edge, error) {
+func readEdges(filePath string) ([]Edge, error) {
 	file, err := os.Open(filePath)
 	if err != nil {
 		return nil, errors.Wrapf(err, "could not open edge file %s", filePath)
@@ -186,7 +184,7 @@ func readEdges(filePath string) ([]edge, error) {
 
 	edgeRegex := regexp.MustCompile(`^(\d+),\s*(\d+),\s*(\d+.\d+)`)
 
-	var edges []edge
+	var edges []Edge
 	for scanner.Scan() {
 		matches := edgeRegex.FindAllStringSubmatch(scanner.Text(), 1)
 		if len(matches) < 1 {
@@ -208,7 +206,7 @@ func readEdges(filePath string) ([]edge, error) {
 			log.Println("failed to parse line")
 			continue
 		}
-		edges = append(edges, edge{start, end, dist})
+		edges = append(edges, Edge{start, end, dist})
 	}
 
 	if len(edges) < 1 {
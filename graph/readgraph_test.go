@@ -1,29 +1,29 @@
-package main
+package graph
 
 import (
 	"testing"
 )
 
 func TestReadProblems(t *testing.T) {
-	problems, err := readProblems("problems/problems.txt")
+	problems, err := ReadProblems("../hw1/problems/problems.txt")
 	if err != nil {
 		t.Error(err)
 	}
 
-	if len(problems) != 5 {
-		t.Errorf("expected 5 problems, got %d", len(problems))
+	if len(problems) != 6 {
+		t.Errorf("expected 6 problems, got %d", len(problems))
 	}
 
 	p1 := problems[0]
-	equals(t, p1.id, 1)
-	equals(t, p1.startID, 1)
-	equals(t, p1.goalID, 10)
-	equals(t, len(p1.vertices), 100)
+	equals(t, p1.ID, 1)
+	equals(t, p1.StartID, 1)
+	equals(t, p1.GoalID, 10)
+	equals(t, len(p1.Vertices), 100)
 
 }
 
 func TestReadVertices(t *testing.T) {
-	vertices, err := readVertices("./problems/nodes_1.txt")
+	vertices, err := readVertices("../hw1/problems/nodes_1.txt")
 	if err != nil {
 		t.Error(err)
 	}
@@ -35,7 +35,7 @@ func TestReadVertices(t *testing.T) {
 }
 
 func TestReadEdges(t *testing.T) {
-	edges, err := readEdges("./problems/edges_1.txt")
+	edges, err := readEdges("../hw1/problems/edges_1.txt")
 	if err != nil {
 		t.Error(err)
 	}
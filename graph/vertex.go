@@ -0,0 +1,22 @@
+// Package graph implements A* search on the graphs of hw1, and the priority
+// queue it uses.
+package graph
+
+// Edge is a directed edge between two vertices, identified by their ids.
+type Edge struct {
+	Tail   int
+	Head   int
+	Weight float64
+}
+
+// Vertex is a vertex in a graph embedded in the plane.
+type Vertex struct {
+	ID          int
+	X, Y        float64 // cartesian position
+	Parent      *Vertex
+	CostToStart float64 // current costToStart (dijkstra)
+	Priority    float64 // based on costToStart and heuristic
+	Finite      bool
+	Neighbors   map[int]float64
+	index       int // for use in heap
+}
@@ -8,6 +8,8 @@ import (
 
 	"github.com/fatih/color"
 	"github.com/pkg/errors"
+
+	"github.com/hdhauk/enae788v/graph"
 )
 
 func main() {
@@ -16,99 +18,80 @@ func main() {
 	problemSet := flag.Int("problem", 1, "number identifier for problem set in provided problem file")
 	dijkstra := flag.Bool("dijk", false, "set this flag to not set heuristic return 0, effectively rendering the algorithm equal to Dijkstra")
 	silent := flag.Bool("silent", false, "turn for file outputs, will still report length of shortest path")
-	help := flag.Bool("h", false, "show help")
-	flag.Parse()
-
-	if *help {
-		printHelp()
-		return
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage:\n  %s [flags] <problem file>\n\nFlags:\n", os.Args[0])
+		flag.PrintDefaults()
 	}
+	flag.Parse()
 
-	if len((os.Args)) < 2 {
+	if flag.NArg() != 1 {
 		fmt.Println("please provide a problem file")
-		printHelp()
-		return
+		flag.Usage()
+		os.Exit(2)
 	}
 
 	log.SetFlags(log.Ltime | log.Lshortfile)
 
-	problemsPath := os.Args[len(os.Args)-1]
-
-	problems, err := readProblems(problemsPath)
+	problems, err := graph.ReadProblems(flag.Arg(0))
 	if err != nil {
 		log.Fatal(err)
 	}
+	if *problemSet < 1 || *problemSet > len(problems) {
+		log.Fatalf("invalid problem number %d, the file has %d problems", *problemSet, len(problems))
+	}
 
 	p1 := problems[*problemSet-1]
-	h := cartesianDistance
+	h := graph.CartesianDistance
 	if *dijkstra {
-		h = func(u, goal *Vertex) float64 { return 0.0 }
+		h = graph.Zero
 	}
-	results, err := aStar(p1.vertices, p1.startID, p1.goalID, h)
+	fmt.Println("Start & Goal coordinates:")
+	fmt.Printf("%f,%f\n", p1.Vertices[p1.StartID].X, p1.Vertices[p1.StartID].Y)
+	fmt.Printf("%f,%f\n", p1.Vertices[p1.GoalID].X, p1.Vertices[p1.GoalID].Y)
+	results, err := graph.AStar(p1.Vertices, p1.StartID, p1.GoalID, h)
 	if err != nil {
 		red := color.New(color.FgRed).FprintfFunc()
 		red(os.Stderr, "%s", err)
 		if *silent {
 			return
 		}
-		if err := writeSearchTree(*searchTreePath, results.searchTree); err != nil {
+		if err := writeSearchTree(*searchTreePath, results.SearchTree); err != nil {
 			log.Fatal(err)
 		}
 		return
 	}
 
 	green := color.New(color.FgGreen).PrintfFunc()
-	green("Found shortest path with distance %.3f\n", results.pathCost)
+	green("Found shortest path with distance %.3f\n", results.PathCost)
 
 	if *silent {
 		return
 	}
-	if err := writeSearchTree(*searchTreePath, results.searchTree); err != nil {
+	if err := writeSearchTree(*searchTreePath, results.SearchTree); err != nil {
 		log.Fatal(err)
 	}
 
-	if err := writePath(*shortestPathPath, results.path); err != nil {
+	if err := writePath(*shortestPathPath, results.Path); err != nil {
 		log.Fatal(err)
 	}
 }
 
-func writeSearchTree(path string, tree []*Vertex) error {
-
+func writeSearchTree(path string, tree []*graph.Vertex) error {
 	file, err := os.Create(path)
 	if err != nil {
 		return errors.Wrap(err, "could not create file")
 	}
 	defer file.Close()
 
-	for i := 1; i < len(tree); i++ {
-		v := tree[i]
-		fmt.Fprintf(file, "%d, %f, %f, %d, %f, %f\n", v.id, v.x, v.y, v.parent.id, v.parent.x, v.parent.y)
-	}
-
-	return nil
-
+	return graph.WriteSearchTree(file, tree)
 }
 
-func writePath(filePath string, path []*Vertex) error {
-
+func writePath(filePath string, path []*graph.Vertex) error {
 	file, err := os.Create(filePath)
 	if err != nil {
 		return errors.Wrap(err, "could not create file")
 	}
 	defer file.Close()
 
-	for _, v := range path {
-		fmt.Fprintf(file, "%d, %f, %f\n", v.id, v.x, v.y)
-	}
-
-	return nil
-}
-
-func printHelp() {
-	fmt.Print(`-dijk              set this flag to not set heuristic return 0, effectively rendering the algorithm equal to Dijkstra
--h                 show help
--path     string   path for shortest path path (default "output_path.txt")
--problem  int      number identifier for problem set in provided problem file (default 1)
--tree     string   path for search tree file (default "search_tree.txt")
--silent            turn for file outputs, will still report length of shortest path`)
+	return graph.WritePath(file, path)
 }
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/hdhauk/enae788v/planner"
+)
+
+func main() {
+	configPath := flag.String("c", "problems.json", "config file")
+	pIndex := flag.Int("p", 0, "which problem in config file to solve (0-indexed)")
+	timeout := flag.Duration("timeout", 0, "give up if no solution is found within this duration (default no timeout)")
+	maxIter := flag.Int("max-iter", 0, "give up if no solution is found within this many iterations (default no limit)")
+	format := flag.String("format", "text", "output format: text, read by plot.py, or json")
+	flag.Parse()
+	if *format != "text" && *format != "json" {
+		fmt.Fprintf(flag.CommandLine.Output(), "unknown format %q\n", *format)
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	config, obstacles, _, err := planner.LoadConfig(*configPath)
+	infeasible, isInfeasible := err.(planner.FeasibilityError)
+	if err != nil && !isInfeasible {
+		log.Fatalln(err)
+	}
+
+	if len(config.Problems)-1 < *pIndex || *pIndex < 0 {
+		log.Fatalln("invalid problem number")
+	}
+	if err := infeasible.Problem(*pIndex); err != nil {
+		log.Fatalln(err)
+	}
+
+	p := config.Problems[*pIndex]
+	checker := planner.PointChecker{Obstacles: obstacles, Space: config.ConfigSpace}
+	seed := time.Now().UnixNano()
+	rrt := planner.NewRRT(p, config.ConfigSpace, checker, seed)
+	rrt.MaxIterations = *maxIter
+	ctx := context.Background()
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+	solution, planErr := rrt.Plan(ctx)
+	if _, noSolution := planErr.(*planner.NoSolutionError); planErr != nil && !noSolution {
+		log.Fatalf("RRT failed during execution: %v\n", planErr)
+	}
+
+	// The partial tree is written even if no solution was found.
+	if *format == "json" {
+		err = planner.WriteJSON(os.Stdout, planner.Result{Problem: p, Planner: "rrt", Seed: seed, Solution: solution, Err: planErr})
+	} else {
+		err = planner.WriteText(os.Stdout, p, solution, 0)
+	}
+	if err != nil {
+		log.Fatalln(err)
+	}
+	if planErr != nil {
+		log.Fatalf("RRT failed during execution: %v\n", planErr)
+	}
+}
@@ -1,198 +1,209 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
-	"io"
 	"log"
-	"math"
 	"os"
+	"time"
 
 	"github.com/pkg/profile"
 
-	"github.com/ungerik/go3d/float64/vec2"
+	"github.com/hdhauk/enae788v/planner"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "validate" {
+		validateMain(os.Args[2:])
+		return
+	}
+
 	defer profile.Start(profile.ProfilePath(".")).Stop()
 
 	configPath := flag.String("c", "problems.json", "config file")
 	pIndex := flag.Int("p", 0, "which problem in config file to solve (0-indexed)")
+	outPath := flag.String("o", "", "output path for the trajectory csv (default \"problem<p>_state.csv\")")
+	precision := flag.Int("precision", 4, "number of decimals in the trajectory csv")
+	tracking := planner.DefaultTrackingConfig()
+	track := flag.Bool("track", false, "simulate following the planned trajectory in closed loop and report tracking error")
+	flag.Float64Var(&tracking.PositionNoise, "noise-pos", tracking.PositionNoise, "std. deviation of position noise per step used with -track")
+	flag.Float64Var(&tracking.HeadingNoise, "noise-theta", tracking.HeadingNoise, "std. deviation of heading noise per step used with -track")
+	flag.Float64Var(&tracking.VelocityNoise, "noise-vel", tracking.VelocityNoise, "std. deviation of v and w noise per step used with -track")
+	flag.Int64Var(&tracking.Seed, "noise-seed", tracking.Seed, "seed for the process noise used with -track")
+	flag.Var(planner.Weights(tracking.Q[:]), "track-q", "LQR weights on the x, y, θ, v and w errors used with -track")
+	flag.Var(planner.Weights(tracking.R[:]), "track-r", "LQR weights on the a and γ corrections used with -track")
+	plannerName := flag.String("planner", "rrt", "planner to use: rrt (first feasible trajectory) or sst (keeps improving until -budget is spent)")
+	sstParams := planner.SSTParams{Budget: 10 * time.Second, DeltaBN: 2, DeltaS: 1, MaxPropTime: 2}
+	flag.DurationVar(&sstParams.Budget, "budget", sstParams.Budget, "time budget for sst")
+	flag.Float64Var(&sstParams.DeltaBN, "delta-bn", sstParams.DeltaBN, "best-near selection radius for sst")
+	flag.Float64Var(&sstParams.DeltaS, "delta-s", sstParams.DeltaS, "witness radius for sst")
+	flag.Float64Var(&sstParams.MaxPropTime, "max-prop", sstParams.MaxPropTime, "maximum duration in seconds of a random propagation in sst")
+	costName := flag.String("cost", "duration", "cost minimized by sst: duration or effort")
+	timeout := flag.Duration("timeout", 0, "give up if no solution is found within this duration (default no timeout)")
+	maxIter := flag.Int("max-iter", 0, "give up if no solution is found within this many iterations (default no limit)")
+	format := flag.String("format", "text", "output format: text, read by plot.py, or json")
+	reportEvery := flag.Int("report-every", 1000, "print the best sst cost every n iterations, in addition to every improvement")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage:\n  %s [flags]\n  %s validate [flags] <trajectory.csv>\n\nFlags:\n", os.Args[0], os.Args[0])
+		flag.PrintDefaults()
+	}
 	flag.Parse()
-
-	// Read in config.
-	configFile, err := os.Open(*configPath)
-	defer configFile.Close()
-	if err != nil {
-		log.Fatalf("could not open config file: %v\n", err)
+	if *format != "text" && *format != "json" {
+		fmt.Fprintf(flag.CommandLine.Output(), "unknown format %q\n", *format)
+		flag.Usage()
+		os.Exit(2)
 	}
 
-	config, err := parseConfig(configFile)
-	if err != nil {
-		log.Fatalf("could not parse config file: %v\n", err)
+	config, obstacles, robot, err := planner.LoadConfig(*configPath)
+	infeasible, isInfeasible := err.(planner.FeasibilityError)
+	if err != nil && !isInfeasible {
+		log.Fatalln(err)
 	}
 
 	// Sanity check problem number.
 	if len(config.Problems)-1 < *pIndex || *pIndex < 0 {
 		log.Fatalln("invalid problem number")
 	}
-
-	// Read in obstacles.
-	obstacleFile, err := os.Open(config.ObstaclesPath)
-	if err != nil {
-		log.Fatalf("could not open obstacle file: %v\n", err)
-	}
-	defer obstacleFile.Close()
-	obstacles, err := readObstacles(obstacleFile)
-	if err != nil {
-		log.Fatalf("could not read obstacles from file: %v\n", err)
-	}
-
-	// Read in robot.
-	robotFile, err := os.Open(config.RobotPath)
-	if err != nil {
-		log.Fatalf("could not open robot file: %v\n", err)
-	}
-	defer robotFile.Close()
-	robot, err := readRobot(robotFile)
-	if err != nil {
-		log.Fatalf("could not read robot from file: %v\n", err)
+	if err := infeasible.Problem(*pIndex); err != nil {
+		log.Fatalln(err)
 	}
 
 	// Solve problem.
 	p := config.Problems[*pIndex]
-	safe := getSafeFunc(obstacles, config.ConfigSpace, robot)
+	checker := newChecker(obstacles, config.ConfigSpace, robot)
 	// seed := time.Now().UnixNano()
 	seed := int64(11)
-	path, tree, err := RRT(obstacles, p, &config.ConfigSpace, safe, seed)
+	var solver planner.Planner
+	switch *plannerName {
+	case "rrt":
+		rrt := planner.NewKinodynamicRRT(p, config.ConfigSpace, checker, seed)
+		rrt.MaxIterations = *maxIter
+		solver = rrt
+	case "sst":
+		switch *costName {
+		case "duration":
+			sstParams.Cost = planner.DurationCost
+		case "effort":
+			sstParams.Cost = planner.EffortCost
+		default:
+			log.Fatalf("unknown cost %q\n", *costName)
+		}
+		sstParams.MaxIterations = *maxIter
+		solver = planner.NewSST(p, config.ConfigSpace, checker, seed, sstParams)
+	default:
+		log.Fatalf("unknown planner %q\n", *plannerName)
+	}
+	ctx := context.Background()
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+	solution, planErr := solver.Plan(ctx)
+	if _, noSolution := planErr.(*planner.NoSolutionError); planErr != nil && !noSolution {
+		log.Fatalf("%s failed during execution: %v\n", *plannerName, planErr)
+	}
+
+	// The partial tree is written even if no solution was found.
+	if *format == "json" {
+		err = planner.WriteJSON(os.Stdout, planner.Result{Problem: p, Planner: *plannerName, Seed: seed, Solution: solution, Err: planErr})
+	} else {
+		err = planner.WriteText(os.Stdout, p, solution, *reportEvery)
+	}
 	if err != nil {
-		log.Fatalf("RRT failed during execution: %v\n", err)
+		log.Fatalln(err)
 	}
+	if planErr != nil {
+		log.Fatalf("%s failed during execution: %v\n", *plannerName, planErr)
+	}
+	path := planner.Trajectory(solution.Path)
 
-	// printing
-	fmt.Printf("start=[%.4f,%.4f] goal=[%.4f,%.4f,%.4f]\n\n", p.Start.X, p.Start.Y, p.Goal.X, p.Goal.Y, p.Goal.R)
-	_, _ = path, tree
+	if *track {
+		report := planner.SimulateTracking(p.Start, path, p, config.ConfigSpace, obstacles, robot, tracking)
+		fmt.Println(report)
+	}
 
 	// create output file for delivery
-	f, err := os.OpenFile(fmt.Sprintf("problem%d_state.csv", *pIndex), os.O_RDWR|os.O_CREATE, 0755)
+	if *outPath == "" {
+		*outPath = fmt.Sprintf("problem%d_state.csv", *pIndex)
+	}
+	f, err := os.Create(*outPath)
 	if err != nil {
-		log.Println(err)
+		log.Fatalf("could not create trajectory file: %v\n", err)
 	}
-
-	printPath(path, f)
-	printTree(tree)
-
-}
-
-func printPath(path []*PathPoint, w io.WriteCloser) {
-	fmt.Println("START_PATH")
-	var head, tail *PathPoint
-	for i := len(path) - 1; i > 0; i-- {
-		head = path[i]
-		tail = path[i-1]
-		fmt.Printf("%.4f, %.4f, %.4f, %.4f, %.4f\n",
-			head.x, head.y, tail.x, tail.y, head.θ)
-
-		// 			    t_i,  x_i, y_i, θ_i, v_i, w_i, a_i, γ_i
+	if err := planner.WriteTrajectory(f, p.Start, path, *precision); err != nil {
+		f.Close()
+		log.Fatalf("could not write trajectory: %v\n", err)
 	}
-	fmt.Println("END_PATH")
-	fmt.Println()
-
-	// create csv file
-	for i := 0; i < len(path); i++ {
-		head = path[i]
-		fmt.Fprintf(w, "%.2f,%.2f,%.2f,%.2f,%.2f,%.2f,%.2f,%.2f\n",
-			float64(i)*timestep, head.x, head.y, head.θ, head.v, head.v, head.a, head.γ)
-
+	if err := f.Close(); err != nil {
+		log.Fatalf("could not close trajectory file: %v\n", err)
 	}
 }
 
-func printTree(tree []*Edge) {
-	fmt.Println("START_TREE")
-	for _, v := range tree {
-		fmt.Printf("%.4f, %.4f, %.4f, %.4f\n", v.head.X, v.head.Y, v.tail.X, v.tail.Y)
+// validateMain implements the validate subcommand. It re-simulates a delivery
+// csv through the dynamics and exits with a non-zero status if any constraint
+// or collision is violated.
+func validateMain(args []string) {
+	fs := flag.NewFlagSet("validate", flag.ExitOnError)
+	configPath := fs.String("c", "problems.json", "config file")
+	pIndex := fs.Int("p", 0, "which problem in config file the trajectory solves (0-indexed)")
+	precision := fs.Int("precision", 4, "number of decimals the trajectory csv was written with")
+	tolerance := fs.Float64("tol", 0, "tolerance when comparing recorded and simulated states (default derived from -precision)")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage:\n  %s validate [flags] <trajectory.csv>\n\nFlags:\n", os.Args[0])
+		fs.PrintDefaults()
 	}
-	fmt.Println("END_TREE")
-}
-
-func getSafeFunc(obstacles []Circle, cSpace ConfigSpace, bot Robot) SafeFunc {
-	legalPoint := func(p *PathPoint) bool {
-		inConfigSpace := (cSpace.XMin < p.x && p.x < cSpace.XMax) && (cSpace.YMin < p.y && p.y < cSpace.YMax)
-		legalVelocities := (cSpace.VMin < p.v && p.v < cSpace.VMax) && (cSpace.WMin < p.w && p.w < cSpace.WMax)
-		if !inConfigSpace || !legalVelocities {
-			return false
-		}
+	fs.Parse(args)
 
-		for _, circle := range obstacles {
-			if near(newVertex(p.x, p.y, 0, 0, 0, nil), circle) {
-				return false
-			}
-		}
-		return true
+	if fs.NArg() != 1 {
+		fs.Usage()
+		os.Exit(2)
 	}
 
-	return func(p *PathPoint) bool {
-		for _, robotPoint := range bot {
-			globalPoint := robotPointGlobal(p, &robotPoint)
-			if !legalPoint(globalPoint) {
-				return false
-			}
-		}
-		return true
+	config, obstacles, robot, err := planner.LoadConfig(*configPath)
+	infeasible, isInfeasible := err.(planner.FeasibilityError)
+	if err != nil && !isInfeasible {
+		log.Fatalln(err)
 	}
-}
-
-func robotPointGlobal(base, offset *PathPoint) *PathPoint {
-	b := vec2.T{offset.x, offset.y}
-	b.Rotate(base.θ)
-	a := vec2.T{base.x, base.y}
-	c := a.Add(&b)
-
-	return &PathPoint{x: c[0], y: c[1], θ: 0, v: 0, w: 0}
-}
-
-// deprecated in hw4
-func getPointsAlongPath(start, end Point, epsilon float64) []Point {
-	a := vec2.T{start.X, start.Y}
-	b := vec2.T{end.X, end.Y}
-
-	a2b := b.Sub(&a)
-	a2bNorm := a2b.Normalized()
-
-	distance := a2b.Length()
-	numPoints := math.Floor(distance / epsilon)
-
-	diffTheta, dir := angleDiff(start.Theta, end.Theta)
-	deltaTheta := diffTheta / (numPoints - 1)
-
-	// no longer valid...
-	pointsAlong := []Point{Point{a[0], a[1], a2b.Angle(), 0, 0}}
-	for i := 0.0; i < numPoints; i++ {
-		offset := a2bNorm.Scaled(epsilon)
-		waypoint := a.Add(&offset)
-		theta := start.Theta + deltaTheta*dir*i
-		pointsAlong = append(pointsAlong, Point{waypoint[0], waypoint[1], theta, 0, 0})
+	if len(config.Problems)-1 < *pIndex || *pIndex < 0 {
+		log.Fatalln("invalid problem number")
 	}
-
-	return pointsAlong
-}
-
-// angleDiff returns the absolute value of the difference in angle, and if that is in
-// positive or negative direction.
-func angleDiff(start, end float64) (float64, float64) {
-	mod := func(a, n int) int {
-		return a - int(math.Floor(float64(a/n)))*n
+	if err := infeasible.Problem(*pIndex); err != nil {
+		log.Fatalln(err)
 	}
 
-	// use degrees
-	angleDiff := int(math.Round(end*180/math.Pi - start*180/math.Pi))
-	angleDiff = mod(angleDiff+180, 360) - 180
-	angleDiff64 := float64(angleDiff)
-	angleDiffRad := angleDiff64 * math.Pi / 180.0
-
-	if angleDiffRad < 0 {
-		return math.Abs(angleDiffRad), -1.0
+	trajectoryFile, err := os.Open(fs.Arg(0))
+	if err != nil {
+		log.Fatalf("could not open trajectory file: %v\n", err)
+	}
+	defer trajectoryFile.Close()
+	rows, err := planner.ReadTrajectory(trajectoryFile)
+	if err != nil {
+		log.Fatalf("could not read trajectory: %v\n", err)
+	}
 
+	if *tolerance == 0 {
+		*tolerance = planner.PrecisionTolerance(*precision)
+	}
+	checker := newChecker(obstacles, config.ConfigSpace, robot)
+	violations := planner.ValidateTrajectory(rows, config.Problems[*pIndex], config.ConfigSpace, checker, *tolerance)
+	for _, v := range violations {
+		fmt.Println(v)
 	}
-	return math.Abs(angleDiffRad), 1.0
+	if len(violations) > 0 {
+		fmt.Printf("INVALID: %d violation(s) in %d rows\n", len(violations), len(rows))
+		os.Exit(1)
+	}
+	fmt.Printf("OK: %d rows\n", len(rows))
+}
+
+// newChecker returns the collision checker for the robot footprint, which also
+// enforces the velocity limits of the config space.
+func newChecker(obstacles []planner.Circle, cSpace planner.ConfigSpace, bot planner.Robot) planner.CollisionChecker {
+	return planner.KinodynamicChecker{FootprintChecker: planner.FootprintChecker{
+		Obstacles: obstacles,
+		Space:     cSpace,
+		Robot:     bot,
+	}}
 }
@@ -0,0 +1,441 @@
+package planner
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+// Link is a link of a planar arm, turned by the joint at its start. The joint
+// angle is relative to the previous link, or to the x axis for the first
+// link, and is limited to [Min, Max].
+type Link struct {
+	Length float64 `json:"length"`
+	Min    float64 `json:"min"`
+	Max    float64 `json:"max"`
+}
+
+// Arm is a planar robot arm of links in series from a fixed base.
+type Arm struct {
+	Base  Point  `json:"base"`
+	Links []Link `json:"links"`
+}
+
+// Joints is a configuration of an arm, with the angle of every joint. The
+// arm planners use Joints states, which are not 2D states.
+type Joints []float64
+
+// joints returns the joint angles of an arm state.
+func joints(s State) Joints {
+	q, ok := s.(Joints)
+	if !ok {
+		panic(fmt.Sprintf("planner: %T is not an arm state", s))
+	}
+	return q
+}
+
+// Points returns the forward kinematics of the arm at q: the base, every
+// joint after it and the end effector. The heading of a point is the absolute
+// angle of the link that ends in it.
+func (a Arm) Points(q Joints) []Point {
+	pts := make([]Point, len(a.Links)+1)
+	pts[0] = Point{X: a.Base.X, Y: a.Base.Y}
+	var theta float64
+	for i, l := range a.Links {
+		theta += q[i]
+		sin, cos := math.Sincos(theta)
+		pts[i+1] = Point{X: pts[i].X + l.Length*cos, Y: pts[i].Y + l.Length*sin, Theta: theta}
+	}
+	return pts
+}
+
+// EndEffector returns the position of the end of the last link at q.
+func (a Arm) EndEffector(q Joints) Point {
+	pts := a.Points(q)
+	return pts[len(pts)-1]
+}
+
+// WithinLimits returns true if q has an angle within the limits of every
+// joint.
+func (a Arm) WithinLimits(q Joints) bool {
+	if len(q) != len(a.Links) {
+		return false
+	}
+	for i, l := range a.Links {
+		if q[i] < l.Min || q[i] > l.Max {
+			return false
+		}
+	}
+	return true
+}
+
+// reach returns the length of the arm from joint i to the end effector, the
+// furthest a point of the arm is from the joint.
+func (a Arm) reach(i int) float64 {
+	var d float64
+	for _, l := range a.Links[i:] {
+		d += l.Length
+	}
+	return d
+}
+
+// JointSpace is the state space of an arm, where the distance between two
+// configurations is the euclidean distance between their joint angles.
+// Joints do not wrap around, since their limits keep them within a turn.
+type JointSpace struct{}
+
+// Distance returns the euclidean distance between the joint angles of two
+// states.
+func (JointSpace) Distance(a, b State) float64 {
+	p, q := joints(a), joints(b)
+	var d float64
+	for i := range p {
+		d += (q[i] - p[i]) * (q[i] - p[i])
+	}
+	return math.Sqrt(d)
+}
+
+// JointSampler picks random configurations of an arm uniformly within the
+// joint limits.
+type JointSampler struct {
+	Arm Arm
+}
+
+// Sample returns random Joints.
+func (s JointSampler) Sample(rng *rand.Rand) State {
+	q := make(Joints, len(s.Arm.Links))
+	for i, l := range s.Arm.Links {
+		q[i] = l.Min + rng.Float64()*(l.Max-l.Min)
+	}
+	return q
+}
+
+// JointLine steers an arm along a straight line in joint space, at most
+// Epsilon toward the target, where every joint turns the same fraction of the
+// way. The cost of a motion is its joint space distance.
+type JointLine struct {
+	Epsilon         float64
+	AllowSmallSteps bool // stop at targets closer than Epsilon instead of passing them
+}
+
+// Steer returns a straight line motion of length Epsilon from from toward
+// toward, or nil if the states are the same.
+func (s JointLine) Steer(from, toward State) *Motion {
+	u, v := joints(from), joints(toward)
+	length := JointSpace{}.Distance(u, v)
+	if length == 0 {
+		return nil
+	}
+	t := 1.0
+	if !s.AllowSmallSteps || length > s.Epsilon {
+		t = s.Epsilon / length
+	}
+	w := make(Joints, len(u))
+	for i := range u {
+		w[i] = u[i] + t*(v[i]-u[i])
+	}
+	if t == 1 {
+		copy(w, v)
+	}
+	return &Motion{From: from, To: w, Cost: t * length}
+}
+
+// ArmGoal is satisfied by the configurations of an arm that place its end
+// effector within a circular goal region.
+type ArmGoal struct {
+	Circle
+	Arm Arm
+}
+
+// Satisfied returns true if the end effector at s is within the goal region.
+func (g ArmGoal) Satisfied(s State) bool {
+	return Near(g.Arm.EndEffector(joints(s)), g.Circle)
+}
+
+// armGoalAttempts is the number of random configurations that ArmGoal.Sample
+// tries to move into the goal region.
+const armGoalAttempts = 20
+
+// Sample picks a configuration with the end effector within the goal region,
+// by moving random configurations toward a random point of the region with
+// cyclic coordinate descent within the joint limits. If the region is out of
+// reach it returns the configuration that came closest, which is still a
+// useful sample toward it.
+func (g ArmGoal) Sample(rng *rand.Rand) State {
+	sampler := JointSampler{g.Arm}
+	var best Joints
+	bestDistance := math.Inf(1)
+	for i := 0; i < armGoalAttempts; i++ {
+		r := g.R * math.Sqrt(rng.Float64())
+		sin, cos := math.Sincos(rng.Float64() * 2 * math.Pi)
+		target := Point{X: g.X + r*cos, Y: g.Y + r*sin}
+		q := g.Arm.descend(sampler.Sample(rng).(Joints), target)
+		if g.Satisfied(q) {
+			return q
+		}
+		end := g.Arm.EndEffector(q)
+		if d := math.Hypot(end.X-target.X, end.Y-target.Y); d < bestDistance {
+			best, bestDistance = q, d
+		}
+	}
+	return best
+}
+
+// descend moves the end effector of the arm at q toward target by cyclic
+// coordinate descent: turning every joint in turn, from the last to the
+// first, to point the end effector at the target as far as its limits allow.
+func (a Arm) descend(q Joints, target Point) Joints {
+	const sweeps = 10
+	for k := 0; k < sweeps; k++ {
+		for i := len(a.Links) - 1; i >= 0; i-- {
+			pts := a.Points(q)
+			joint, end := pts[i], pts[len(pts)-1]
+			turn := math.Atan2(target.Y-joint.Y, target.X-joint.X) - math.Atan2(end.Y-joint.Y, end.X-joint.X)
+			turn = math.Remainder(turn, 2*math.Pi)
+			l := a.Links[i]
+			q[i] = math.Max(l.Min, math.Min(l.Max, q[i]+turn))
+		}
+	}
+	return q
+}
+
+// ArmChecker checks the links of an arm against circular obstacles, the
+// config space bounds and each other. Adjacent links only meet at their joint
+// as long as the joint limits after the first are within (-π, π), which
+// LoadArmConfig requires, so only links further apart are checked for self
+// collision. Motions are checked at waypoints close enough that no point of
+// the arm moves more than Resolution between them, which must be positive.
+type ArmChecker struct {
+	Arm        Arm
+	Obstacles  []Circle
+	Space      ConfigSpace
+	Resolution float64
+}
+
+// Valid returns true if the joints of s are within their limits, and every
+// link is inside the config space, outside every obstacle and clear of the
+// other links.
+func (c ArmChecker) Valid(s State) bool {
+	q := joints(s)
+	if !c.Arm.WithinLimits(q) {
+		return false
+	}
+	// The links are inside the config space if their ends are, since it is
+	// convex.
+	pts := c.Arm.Points(q)
+	for _, p := range pts {
+		if !c.Space.Contains(p.X, p.Y) {
+			return false
+		}
+	}
+	for i := 1; i < len(pts); i++ {
+		a, b := pts[i-1], pts[i]
+		for _, o := range c.Obstacles {
+			if segmentDistance(o.X, o.Y, a, b) < o.R {
+				return false
+			}
+		}
+		for j := 1; j < i-1; j++ {
+			if segmentsIntersect(pts[j-1], pts[j], a, b) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// MotionValid returns true if every state along the path of m is valid, or
+// for straight line motions in joint space every waypoint close enough that
+// no point of the arm moves more than Resolution between them. A point of
+// the arm moves at most the turn of every joint before it times its distance
+// from that joint.
+func (c ArmChecker) MotionValid(m *Motion) bool {
+	if len(m.Path) > 0 {
+		return pathValid(c.Valid, m)
+	}
+	if c.Resolution <= 0 {
+		panic(fmt.Sprintf("planner: ArmChecker resolution %v is not positive", c.Resolution))
+	}
+	from, to := joints(m.From), joints(m.To)
+	var sweep float64
+	for i := range from {
+		sweep += math.Abs(to[i]-from[i]) * c.Arm.reach(i)
+	}
+	steps := int(math.Ceil(sweep / c.Resolution))
+	q := make(Joints, len(from))
+	for k := 0; k < steps; k++ {
+		t := float64(k) / float64(steps)
+		for i := range q {
+			q[i] = from[i] + t*(to[i]-from[i])
+		}
+		if !c.Valid(q) {
+			return false
+		}
+	}
+	return c.Valid(m.To)
+}
+
+// segmentsIntersect returns true if the segment from a to b and the segment
+// from c to d cross or touch.
+func segmentsIntersect(a, b, c, d Point) bool {
+	orient := func(p, q, r Point) float64 {
+		return (q.X-p.X)*(r.Y-p.Y) - (q.Y-p.Y)*(r.X-p.X)
+	}
+	d1, d2 := orient(c, d, a), orient(c, d, b)
+	d3, d4 := orient(a, b, c), orient(a, b, d)
+	if ((d1 > 0 && d2 < 0) || (d1 < 0 && d2 > 0)) && ((d3 > 0 && d4 < 0) || (d3 < 0 && d4 > 0)) {
+		return true
+	}
+	// Collinear or touching segments meet where an end of one lies on the
+	// other.
+	return (d1 == 0 && segmentDistance(a.X, a.Y, c, d) == 0) || (d2 == 0 && segmentDistance(b.X, b.Y, c, d) == 0) ||
+		(d3 == 0 && segmentDistance(c.X, c.Y, a, b) == 0) || (d4 == 0 && segmentDistance(d.X, d.Y, a, b) == 0)
+}
+
+// NewArmRRT returns an RRT for an arm that plans in joint space, moving in
+// straight lines between configurations like the robot of hw3 does in the
+// plane.
+func NewArmRRT(prob ArmProblem, arm Arm, checker CollisionChecker, seed int64) *RRT {
+	return &RRT{
+		Start:    prob.Start,
+		Space:    JointSpace{},
+		Sampler:  JointSampler{arm},
+		Steerer:  JointLine{Epsilon: prob.Epsilon, AllowSmallSteps: prob.AllowSmallSteps},
+		Checker:  checker,
+		Goal:     ArmGoal{Circle: prob.Goal, Arm: arm},
+		GoalBias: prob.GoalBias,
+		Rand:     rand.New(rand.NewSource(seed)),
+	}
+}
+
+// ArmProblem is a planning problem of an arm: moving from the start
+// configuration until the end effector is within the goal region. Epsilon is
+// a distance in joint space, in radians.
+type ArmProblem struct {
+	Name            string  `json:"name"`
+	Start           Joints  `json:"start"`
+	Goal            Circle  `json:"goal_region"`
+	GoalBias        float64 `json:"goal_bias"` // probability of sampling from the goal
+	Epsilon         float64 `json:"epsilon"`
+	AllowSmallSteps bool    `json:"allow_steps_smaller_than_epsilon"`
+}
+
+// ArmConfig is the go struct equivalent of the .json file describing the
+// problems of an arm.
+type ArmConfig struct {
+	ObstaclesPath string       `json:"obstacles"`
+	ConfigSpace   ConfigSpace  `json:"config_space"`
+	Arm           Arm          `json:"arm"`
+	Problems      []ArmProblem `json:"problems"`
+}
+
+// LoadArmConfig reads an arm config file together with the obstacles it
+// refers to, resolved from the directory of the config file as by
+// LoadConfig. If a problem is infeasible, the config is returned together
+// with a FeasibilityError naming the problem and field.
+func LoadArmConfig(configPath string) (*ArmConfig, []Circle, error) {
+	configFile, err := os.Open(configPath)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "could not open config file")
+	}
+	defer configFile.Close()
+	var config ArmConfig
+	if err := json.NewDecoder(configFile).Decode(&config); err != nil {
+		return nil, nil, errors.Wrap(err, "could not parse config file")
+	}
+	if len(config.Arm.Links) == 0 {
+		return nil, nil, errors.New("the arm has no links")
+	}
+	for i, l := range config.Arm.Links {
+		if l.Length <= 0 || l.Min > l.Max {
+			return nil, nil, errors.Errorf("link %d: the length must be positive and min at most max", i)
+		}
+		// A link folded back by π would lie on the link before it, which
+		// the ArmChecker does not check.
+		if i > 0 && (l.Min <= -math.Pi || l.Max >= math.Pi) {
+			return nil, nil, errors.Errorf("link %d: the joint limits must be within (-π, π)", i)
+		}
+	}
+
+	obstacleFile, err := os.Open(resolve(filepath.Dir(configPath), config.ObstaclesPath))
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "could not open obstacle file")
+	}
+	defer obstacleFile.Close()
+	obstacles, err := ReadObstacles(obstacleFile)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "could not read obstacles from file")
+	}
+	return &config, obstacles, config.Check(obstacles)
+}
+
+// Check returns a FeasibilityError if any problem of the config is
+// infeasible: a start of the wrong length, outside the joint limits or in
+// collision, or a goal region out of reach of the arm.
+func (c *ArmConfig) Check(obstacles []Circle) error {
+	checker := ArmChecker{Arm: c.Arm, Obstacles: obstacles, Space: c.ConfigSpace}
+	var issues FeasibilityError
+	for i, prob := range c.Problems {
+		add := func(field, format string, args ...interface{}) {
+			issues = append(issues, Issue{Problem: i, Name: prob.Name, Field: field, Reason: fmt.Sprintf(format, args...)})
+		}
+		switch {
+		case len(prob.Start) != len(c.Arm.Links):
+			add("start", "has %d joint angles for an arm of %d links", len(prob.Start), len(c.Arm.Links))
+		case !c.Arm.WithinLimits(prob.Start):
+			add("start", "%v is outside the joint limits", prob.Start)
+		case !checker.Valid(prob.Start):
+			add("start", "%v leaves the arm in collision or outside the config space", prob.Start)
+		}
+		g, base := prob.Goal, c.Arm.Base
+		if math.Hypot(g.X-base.X, g.Y-base.Y)-g.R >= c.Arm.reach(0) {
+			add("goal_region", "%v is out of reach of the arm, %.2f from the base", g, c.Arm.reach(0))
+		}
+		if prob.Epsilon <= 0 {
+			add("epsilon", "%.2f is not positive", prob.Epsilon)
+		}
+	}
+	if len(issues) == 0 {
+		return nil
+	}
+	return issues
+}
+
+// WriteArmPath writes every configuration along a path of an arm as csv rows
+// of the joint angles and the position of the end effector, with precision
+// decimals.
+func WriteArmPath(w io.Writer, arm Arm, path []*Motion, precision int) error {
+	cw := csv.NewWriter(w)
+	header := make([]string, 0, len(arm.Links)+2)
+	for i := range arm.Links {
+		header = append(header, fmt.Sprintf("q%d", i+1))
+	}
+	if err := cw.Write(append(header, "x", "y")); err != nil {
+		return errors.Wrap(err, "could not write header")
+	}
+
+	format := func(f float64) string {
+		return strconv.FormatFloat(f, 'f', precision, 64)
+	}
+	for _, s := range PathStates(path) {
+		q := joints(s)
+		var record []string
+		for _, a := range q {
+			record = append(record, format(a))
+		}
+		end := arm.EndEffector(q)
+		if err := cw.Write(append(record, format(end.X), format(end.Y))); err != nil {
+			return errors.Wrap(err, "could not write row")
+		}
+	}
+	cw.Flush()
+	return errors.Wrap(cw.Error(), "could not flush csv")
+}
@@ -0,0 +1,159 @@
+package planner
+
+import (
+	"bytes"
+	"context"
+	"math"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// testArm is an arm of two links of length 10 from the origin, that can
+// fold back almost onto itself.
+var testArm = Arm{Links: []Link{{Length: 10, Min: -math.Pi, Max: math.Pi}, {Length: 10, Min: -3, Max: 3}}}
+
+func TestArmPoints(t *testing.T) {
+	pts := testArm.Points(Joints{math.Pi / 2, -math.Pi / 2})
+	equals(t, 3, len(pts))
+	assert(t, math.Abs(pts[1].X) < 1e-9 && pts[1].Y == 10, "unexpected elbow %v", pts[1])
+	end := testArm.EndEffector(Joints{math.Pi / 2, -math.Pi / 2})
+	assert(t, math.Abs(end.X-10) < 1e-9 && end.Y == 10 && end.Theta == 0, "unexpected end effector %v", end)
+
+	assert(t, testArm.WithinLimits(Joints{0, 3}), "on the limit")
+	assert(t, !testArm.WithinLimits(Joints{0, 3.1}), "beyond the limit")
+	assert(t, !testArm.WithinLimits(Joints{0}), "too few joints")
+}
+
+func TestArmChecker(t *testing.T) {
+	checker := ArmChecker{
+		Arm:        testArm,
+		Obstacles:  []Circle{{X: 15, Y: 4, R: 2}, {X: -5, Y: -5, R: 2}},
+		Space:      ConfigSpace{XMin: -25, XMax: 25, YMin: -25, YMax: 25},
+		Resolution: 0.5,
+	}
+	var tests = []struct {
+		name string
+		q    Joints
+		exp  bool
+	}{
+		{"stretched out", Joints{0, 0}, true},
+		{"forearm through the obstacle", Joints{0, math.Atan2(4, 5)}, false},
+		{"forearm bent past the obstacle", Joints{0, math.Pi / 2}, true},
+		{"upper arm through the obstacle", Joints{-3 * math.Pi / 4, 0}, false},
+		{"outside the joint limits", Joints{0, -3.1}, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			equals(t, tc.exp, checker.Valid(tc.q))
+		})
+	}
+
+	// The forearm sweeps through the obstacle between two free
+	// configurations.
+	equals(t, false, checker.MotionValid(&Motion{From: Joints{0, 0}, To: Joints{0, math.Pi / 2}}))
+	equals(t, true, checker.MotionValid(&Motion{From: Joints{0, 0}, To: Joints{0, -math.Pi / 2}}))
+
+	// The third link of an arm folding onto itself crosses the first.
+	folding := ArmChecker{
+		Arm:   Arm{Links: []Link{{Length: 10, Min: -3, Max: 3}, {Length: 4, Min: -3, Max: 3}, {Length: 10, Min: -3, Max: 3}}},
+		Space: checker.Space,
+	}
+	assert(t, folding.Valid(Joints{0, math.Pi / 2, math.Pi/2 - 0.5}), "third link clear of the first")
+	assert(t, !folding.Valid(Joints{0, math.Pi / 2, math.Pi/2 + 0.5}), "third link crossing the first")
+
+	defer func() {
+		assert(t, recover() != nil, "expected a panic for a zero resolution")
+	}()
+	folding.MotionValid(&Motion{From: Joints{0, 0, 0}, To: Joints{0, 0.1, 0}})
+}
+
+func TestSegmentsIntersect(t *testing.T) {
+	a, b := Point{X: 0, Y: 0}, Point{X: 10, Y: 0}
+	assert(t, segmentsIntersect(a, b, Point{X: 5, Y: -1}, Point{X: 5, Y: 1}), "crossing")
+	assert(t, segmentsIntersect(a, b, Point{X: 5, Y: 0}, Point{X: 5, Y: 1}), "touching")
+	assert(t, segmentsIntersect(a, b, Point{X: 8, Y: 0}, Point{X: 12, Y: 0}), "overlapping collinear")
+	assert(t, !segmentsIntersect(a, b, Point{X: 11, Y: 0}, Point{X: 12, Y: 0}), "collinear apart")
+	assert(t, !segmentsIntersect(a, b, Point{X: 5, Y: 1}, Point{X: 6, Y: 2}), "apart")
+}
+
+func TestJointLine(t *testing.T) {
+	m := JointLine{Epsilon: 1}.Steer(Joints{0, 0}, Joints{3, 4})
+	to := m.To.(Joints)
+	assert(t, math.Abs(to[0]-0.6) < 1e-9 && math.Abs(to[1]-0.8) < 1e-9, "unexpected step %v", to)
+	equals(t, 1.0, m.Cost)
+
+	m = JointLine{Epsilon: 1, AllowSmallSteps: true}.Steer(Joints{0, 0}, Joints{0.3, 0.4})
+	equals(t, Joints{0.3, 0.4}, m.To)
+	assert(t, JointLine{Epsilon: 1}.Steer(Joints{1, 2}, Joints{1, 2}) == nil, "steered in place")
+}
+
+func TestArmGoal(t *testing.T) {
+	goal := ArmGoal{Circle: Circle{X: -5, Y: 12, R: 1}, Arm: testArm}
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		q := goal.Sample(rng).(Joints)
+		assert(t, goal.Satisfied(q), "sample %v is not in the goal region", q)
+		assert(t, testArm.WithinLimits(q), "sample %v is outside the joint limits", q)
+	}
+
+	// Out of reach, the samples point toward the goal.
+	far := ArmGoal{Circle: Circle{X: 30, Y: 0, R: 1}, Arm: testArm}
+	end := testArm.EndEffector(far.Sample(rng).(Joints))
+	assert(t, end.X > 19.5, "unexpected end effector %v", end)
+}
+
+func TestLoadArmConfig(t *testing.T) {
+	config, obstacles, err := LoadArmConfig(filepath.Join("..", "arm", "problems.json"))
+	ok(t, err)
+	equals(t, 3, len(config.Arm.Links))
+	assert(t, len(obstacles) > 0, "no obstacles")
+
+	config.Problems[0].Start = Joints{0}
+	config.Problems[1].Goal = Circle{X: 50, Y: 90, R: 5}
+	issues, isInfeasible := config.Check(obstacles).(FeasibilityError)
+	assert(t, isInfeasible, "expected a FeasibilityError")
+	equals(t, 2, len(issues))
+	equals(t, "start", issues[0].Field)
+	equals(t, "goal_region", issues[1].Field)
+
+	// A joint that folds back onto the link before it is rejected.
+	dir := t.TempDir()
+	ok(t, os.WriteFile(filepath.Join(dir, "obstacles.txt"), []byte("50,50,5\n"), 0644))
+	folding := `{"obstacles": "obstacles.txt", "config_space": {"x_max": 100, "y_max": 100},
+		"arm": {"links": [{"length": 10, "min": -4, "max": 4}, {"length": 10, "min": -3.15, "max": 3}]}}`
+	ok(t, os.WriteFile(filepath.Join(dir, "arm.json"), []byte(folding), 0644))
+	_, _, err = LoadArmConfig(filepath.Join(dir, "arm.json"))
+	assert(t, err != nil, "expected an error for joint limits beyond π")
+}
+
+// TestArmRRT plans every arm problem, and checks that the path is continuous
+// and collision free and ends in the goal region.
+func TestArmRRT(t *testing.T) {
+	config, obstacles, err := LoadArmConfig(filepath.Join("..", "arm", "problems.json"))
+	ok(t, err)
+	checker := ArmChecker{Arm: config.Arm, Obstacles: obstacles, Space: config.ConfigSpace, Resolution: 0.5}
+	for i, prob := range config.Problems {
+		t.Run(prob.Name, func(t *testing.T) {
+			sol, err := NewArmRRT(prob, config.Arm, checker, int64(i+1)).Plan(context.Background())
+			ok(t, err)
+			equals(t, prob.Start, sol.Path[0].From)
+			for j, m := range sol.Path {
+				assert(t, checker.MotionValid(m), "motion %d is in collision", j)
+				if j > 0 {
+					equals(t, sol.Path[j-1].To, m.From)
+				}
+			}
+			goal := ArmGoal{Circle: prob.Goal, Arm: config.Arm}
+			assert(t, goal.Satisfied(sol.Path[len(sol.Path)-1].To), "path does not end in the goal region")
+
+			var buf bytes.Buffer
+			ok(t, WriteArmPath(&buf, config.Arm, sol.Path, 4))
+			lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+			equals(t, "q1,q2,q3,x,y", lines[0])
+			equals(t, len(sol.Path)+2, len(lines))
+		})
+	}
+}
@@ -0,0 +1,283 @@
+package planner
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// BITStarParams configures the BIT* planner.
+type BITStarParams struct {
+	Budget        time.Duration // planning continues improving until the budget is spent
+	BatchSize     int           // states sampled per batch
+	MaxIterations int           // stop after processing this many edges if non-zero
+	SE2           SE2           // metric and turn limit of a robot with a heading, the zero value plans in the plane
+}
+
+// BITStar finds paths for geometric robots that move in straight lines, such
+// as the robots of hw2 and hw3, and keeps shortening the best path with the
+// Batch Informed Trees algorithm. It samples batches of states, and searches
+// the graph of the tree and the samples in order of the cost of the best path
+// through every edge that the straight line distances allow, like A*. Only
+// edges that could shorten the best path are checked for collisions. Once a
+// path is found, batches are sampled from the ellipse of states that could
+// shorten it, and vertices that cannot are pruned as in informed RRT*.
+type BITStar struct {
+	Problem Problem
+	Space   ConfigSpace
+	Checker CollisionChecker
+	Params  BITStarParams
+	Rand    *rand.Rand
+}
+
+// NewBITStar returns a BIT* planner for the problem.
+func NewBITStar(prob Problem, cSpace ConfigSpace, checker CollisionChecker, seed int64, params BITStarParams) *BITStar {
+	return &BITStar{
+		Problem: prob,
+		Space:   cSpace,
+		Checker: checker,
+		Params:  params,
+		Rand:    rand.New(rand.NewSource(seed)),
+	}
+}
+
+// bitSearch is the state of a BIT* search. The vertices of the tree have a
+// finite cost, and the samples not yet in the tree an infinite cost.
+type bitSearch struct {
+	*BITStar
+	checker   CollisionChecker
+	goal      *GoalRegion
+	vertices  []*Vertex
+	samples   []*Vertex
+	children  map[*Vertex][]*Vertex
+	reached   []*Vertex        // vertices in the goal region
+	old       map[*Vertex]bool // vertices in the tree when the batch was sampled
+	vertexQ   *queue
+	edgeQ     *queue
+	radius    float64
+	best      *Vertex
+	bestCost  float64
+	prunedFor float64 // best cost at the last pruning
+}
+
+// Plan searches batch after batch until the budget is spent, ctx is done or
+// a limit is reached. It returns the shortest path found along with the tree
+// and the best cost at every improvement. Without a budget, an iteration limit
+// or a deadline on ctx it would never return, so that is an error, as is an
+// empty batch.
+func (p *BITStar) Plan(ctx context.Context) (*Solution, error) {
+	if p.Params.BatchSize <= 0 {
+		return nil, errors.Errorf("batch size %d is not positive", p.Params.BatchSize)
+	}
+	if _, ok := ctx.Deadline(); !ok && p.Params.Budget <= 0 && p.Params.MaxIterations <= 0 {
+		return nil, errors.New("no budget, iteration limit or deadline to stop planning")
+	}
+	solution := &Solution{Cost: math.Inf(1), Progress: []Progress{{Cost: math.Inf(1)}}}
+	root := &Vertex{State: p.Problem.Start}
+	s := &bitSearch{
+		BITStar:   p,
+		checker:   countingChecker{p.Checker, &solution.Stats},
+		goal:      NewGoalRegion(p.Problem, p.Space),
+		vertices:  []*Vertex{root},
+		children:  map[*Vertex][]*Vertex{},
+		vertexQ:   &queue{},
+		edgeQ:     &queue{},
+		bestCost:  math.Inf(1),
+		prunedFor: math.Inf(1),
+	}
+	started := time.Now()
+	var deadline time.Time
+	if p.Params.Budget > 0 {
+		deadline = started.Add(p.Params.Budget)
+	}
+
+	reason := "time budget spent"
+	if !s.checker.Valid(root.State) {
+		solution.Stats.Elapsed = time.Since(started)
+		_, err := noSolution("start is in collision or outside the config space", solution.Stats, nil)
+		return solution, err
+	}
+	for i := 1; ; i++ {
+		if !deadline.IsZero() && !time.Now().Before(deadline) {
+			break
+		}
+		if err := ctx.Err(); err != nil {
+			reason = err.Error()
+			break
+		}
+		if p.Params.MaxIterations > 0 && i > p.Params.MaxIterations {
+			reason = "iteration limit reached"
+			break
+		}
+		solution.Stats.Iterations = i
+
+		if s.vertexQ.Len() == 0 && s.edgeQ.Len() == 0 {
+			s.batch()
+		}
+		s.step()
+		if s.best != nil && s.bestCost < solution.Cost {
+			solution.Cost = s.bestCost
+			solution.Path = backtrack(s.best)
+			solution.Progress = append(solution.Progress, Progress{Iteration: i, Elapsed: time.Since(started), Cost: solution.Cost})
+		}
+	}
+
+	for _, v := range s.vertices {
+		if v.Motion != nil {
+			solution.Tree = append(solution.Tree, v.Motion)
+		}
+	}
+	solution.Stats.Elapsed = time.Since(started)
+	if s.best == nil {
+		_, err := noSolution(reason, solution.Stats, solution.Tree)
+		return solution, err
+	}
+	return solution, nil
+}
+
+// batch prunes the tree and samples if the best path got shorter since the
+// last batch, drops the samples connected to the tree, samples a new batch of
+// states and queues every vertex of the tree for expansion.
+func (s *bitSearch) batch() {
+	if s.bestCost < s.prunedFor {
+		s.prune()
+	}
+	var samples []*Vertex
+	for _, x := range s.samples {
+		if math.IsInf(x.Cost, 1) {
+			samples = append(samples, x)
+		}
+	}
+	s.samples = samples
+
+	sampler := UniformSampler{s.Space}
+	for i := 0; i < s.Params.BatchSize; i++ {
+		var u State
+		switch {
+		case s.best != nil:
+			u = sampleInformed(s.Problem, s.Space, s.Rand, s.bestCost)
+		case s.Rand.Float64() < s.Problem.GoalBias:
+			u = s.goal.Sample(s.Rand)
+		default:
+			u = sampler.Sample(s.Rand)
+		}
+		// Where the goal region is wide, the ellipse also holds states that
+		// cannot shorten the best path.
+		if lowerBound(s.Problem, u) < s.bestCost && s.checker.Valid(u) {
+			s.samples = append(s.samples, &Vertex{State: u, Cost: math.Inf(1)})
+		}
+	}
+
+	s.old = map[*Vertex]bool{}
+	for _, v := range s.vertices {
+		s.old[v] = true
+		s.vertexQ.push(v.Cost+costToGo(s.Problem, v.State), v, nil)
+	}
+	s.radius = connectionRadius(s.Space, len(s.vertices)+len(s.samples), 0)
+}
+
+// prune drops the samples and vertices through which no path is shorter than
+// the best path. The descendants of pruned vertices that could still shorten
+// it become samples again.
+func (s *bitSearch) prune() {
+	s.prunedFor = s.bestCost
+	var samples []*Vertex
+	for _, x := range s.samples {
+		if lowerBound(s.Problem, x.State) < s.bestCost {
+			samples = append(samples, x)
+		}
+	}
+
+	var removed map[*Vertex]bool
+	s.vertices, removed = prune(func(u State) float64 { return lowerBound(s.Problem, u) }, s.vertices, s.children, s.bestCost)
+	for v := range removed {
+		if lowerBound(s.Problem, v.State) < s.bestCost {
+			v.Parent, v.Motion, v.Cost = nil, nil, math.Inf(1)
+			samples = append(samples, v)
+		}
+	}
+	s.samples = samples
+	s.reached = keep(s.reached, removed)
+}
+
+// step expands the vertices that could lead to shorter edges than the best
+// queued edge, and processes that edge. The batch ends when no queued edge
+// can shorten the best path.
+func (s *bitSearch) step() {
+	for s.vertexQ.Len() > 0 && s.vertexQ.top() <= s.edgeQ.top() {
+		s.expand(s.vertexQ.pop().from)
+	}
+	if s.edgeQ.Len() == 0 {
+		return
+	}
+	e := s.edgeQ.pop()
+	v, x := e.from, e.to
+	estimate := s.Params.SE2.Distance(v.State, x.State)
+	if v.Cost+estimate+costToGo(s.Problem, x.State) >= s.bestCost {
+		*s.vertexQ, *s.edgeQ = nil, nil
+		return
+	}
+	if v.Cost+estimate >= x.Cost {
+		return
+	}
+
+	m := s.Params.SE2.connector().Steer(v.State, x.State)
+	if m == nil || !s.checker.MotionValid(m) {
+		return
+	}
+	cost := v.Cost + m.Cost
+	if cost >= x.Cost {
+		return
+	}
+	if math.IsInf(x.Cost, 1) {
+		x.Parent, x.Motion, x.Cost = v, m, cost
+		s.vertices = append(s.vertices, x)
+		s.vertexQ.push(cost+costToGo(s.Problem, x.State), x, nil)
+		if s.goal.Satisfied(x.State) {
+			s.reached = append(s.reached, x)
+		}
+	} else {
+		s.children[x.Parent] = without(s.children[x.Parent], x)
+		x.Parent, x.Motion = v, m
+		updateCost(s.children, x, cost)
+	}
+	s.children[v] = append(s.children[v], x)
+
+	// Rewiring shortens the paths to vertices already in the goal region.
+	for _, w := range s.reached {
+		if s.best == nil || w.Cost < s.bestCost {
+			s.best, s.bestCost = w, w.Cost
+		}
+	}
+}
+
+// expand queues the edges from v to the samples near it, and for vertices
+// added to the tree in this batch, to the vertices near it that it could
+// give shorter paths. Edges that cannot shorten the best path are left out.
+func (s *bitSearch) expand(v *Vertex) {
+	space := s.Params.SE2
+	q, start := Pose(v.State), s.Problem.Start
+	fromStart := math.Hypot(q.X-start.X, q.Y-start.Y)
+	useful := func(x *Vertex, d float64) bool {
+		return fromStart+d+costToGo(s.Problem, x.State) < s.bestCost
+	}
+	for _, x := range s.samples {
+		if d := space.Distance(v.State, x.State); math.IsInf(x.Cost, 1) && d <= s.radius && useful(x, d) {
+			s.edgeQ.push(v.Cost+d+costToGo(s.Problem, x.State), v, x)
+		}
+	}
+	if s.old[v] {
+		return
+	}
+	for _, w := range s.vertices {
+		if w == v || w == v.Parent || w.Parent == v {
+			continue
+		}
+		if d := space.Distance(v.State, w.State); d <= s.radius && useful(w, d) && v.Cost+d < w.Cost {
+			s.edgeQ.push(v.Cost+d+costToGo(s.Problem, w.State), v, w)
+		}
+	}
+}
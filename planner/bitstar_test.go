@@ -0,0 +1,53 @@
+package planner
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestBITStar(t *testing.T) {
+	prob, cSpace, checker, shortest := aroundObstacle()
+	params := BITStarParams{BatchSize: 100, MaxIterations: 20000}
+	solution, err := NewBITStar(prob, cSpace, checker, 1, params).Plan(context.Background())
+	ok(t, err)
+	checkShortPath(t, "bitstar", prob, checker, shortest, solution)
+
+	// Only edges that could shorten the path are checked, so BIT* needs
+	// fewer checks than RRT* for a path as short.
+	star := NewRRTStar(prob, cSpace, checker, 1, RRTStarParams{MaxIterations: 3000})
+	starSolution, err := star.Plan(context.Background())
+	ok(t, err)
+	assert(t, solution.Cost <= starSolution.Cost, "bitstar cost %f should be at most the rrt* cost %f", solution.Cost, starSolution.Cost)
+	assert(t, solution.Stats.MotionChecks < starSolution.Stats.MotionChecks, "bitstar made %d motion checks, rrt* %d", solution.Stats.MotionChecks, starSolution.Stats.MotionChecks)
+}
+
+func TestBITStarNoSolution(t *testing.T) {
+	cSpace := ConfigSpace{XMin: 0, XMax: 50, YMin: 0, YMax: 50}
+	prob := Problem{Start: Point{X: 5, Y: 5}, Goal: Circle{X: 45, Y: 45, R: 2}, Epsilon: 2, GoalBias: 0.05}
+	enclosed := PointChecker{Obstacles: []Circle{{X: 45, Y: 45, R: 4}}, Space: cSpace}
+
+	params := BITStarParams{BatchSize: 50, MaxIterations: 1000}
+	solution, err := NewBITStar(prob, cSpace, enclosed, 1, params).Plan(context.Background())
+	noSol, isNoSol := err.(*NoSolutionError)
+	assert(t, isNoSol, "expected a *NoSolutionError, got %v", err)
+	equals(t, 1000, noSol.Iterations)
+	assert(t, len(noSol.Tree) > 0, "expected the partial tree")
+	equals(t, 0, len(solution.Path))
+}
+
+func TestBITStarParams(t *testing.T) {
+	prob, cSpace, checker, _ := aroundObstacle()
+	plan := func(ctx context.Context, params BITStarParams) error {
+		_, err := NewBITStar(prob, cSpace, checker, 1, params).Plan(ctx)
+		return err
+	}
+	assert(t, plan(context.Background(), BITStarParams{MaxIterations: 100}) != nil, "expected an error for an empty batch")
+	assert(t, plan(context.Background(), BITStarParams{BatchSize: 100}) != nil, "expected an error without a limit")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	err := plan(ctx, BITStarParams{BatchSize: 100})
+	_, isNoSol := err.(*NoSolutionError)
+	assert(t, err == nil || isNoSol, "expected the deadline of ctx to stop planning, got %v", err)
+}
@@ -0,0 +1,150 @@
+package planner
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Issue is a reason why a problem of a config can not be solved.
+type Issue struct {
+	Problem int    // index of the problem in the config
+	Name    string // name of the problem
+	Field   string // json field the issue was found in
+	Reason  string
+}
+
+func (i Issue) String() string {
+	return fmt.Sprintf("problem %d (%s): %s %s", i.Problem, i.Name, i.Field, i.Reason)
+}
+
+// FeasibilityError lists the issues of every infeasible problem in a config.
+type FeasibilityError []Issue
+
+func (e FeasibilityError) Error() string {
+	lines := make([]string, len(e))
+	for i, issue := range e {
+		lines[i] = issue.String()
+	}
+	return "infeasible problems: " + strings.Join(lines, "; ")
+}
+
+// Problem returns the issues of problem i, or nil if it is feasible.
+func (e FeasibilityError) Problem(i int) error {
+	var issues FeasibilityError
+	for _, issue := range e {
+		if issue.Problem == i {
+			issues = append(issues, issue)
+		}
+	}
+	if len(issues) == 0 {
+		return nil
+	}
+	return issues
+}
+
+// Check returns a FeasibilityError if any problem of the config is
+// infeasible with the given obstacles and robot, which is nil for a point
+// robot.
+func (c *Config) Check(obstacles []Circle, robot Robot) error {
+	var issues FeasibilityError
+	for i, prob := range c.Problems {
+		issues = append(issues, CheckProblem(i, prob, c.ConfigSpace, obstacles, robot)...)
+	}
+	if len(issues) == 0 {
+		return nil
+	}
+	return issues
+}
+
+// CheckProblem returns the issues that make problem i infeasible: a start
+// outside the config space, in collision or with velocities outside the
+// limits, a goal region without collision free positions, and goal
+// constraints that can not be met.
+func CheckProblem(i int, prob Problem, cSpace ConfigSpace, obstacles []Circle, robot Robot) []Issue {
+	var issues []Issue
+	add := func(field, format string, args ...interface{}) {
+		issues = append(issues, Issue{Problem: i, Name: prob.Name, Field: field, Reason: fmt.Sprintf(format, args...)})
+	}
+
+	start := prob.Start
+	if !cSpace.Contains(start.X, start.Y) {
+		add("start", "(%.2f, %.2f) is outside the config space", start.X, start.Y)
+	} else if o, collides := collidingObstacle(start, obstacles); collides {
+		add("start", "(%.2f, %.2f) is inside obstacle %v", start.X, start.Y, o)
+	} else if robot != nil && !(FootprintChecker{Obstacles: obstacles, Space: cSpace, Robot: robot}).Valid(start) {
+		add("start", "(%.2f, %.2f, θ=%.2f) leaves the robot footprint in collision or outside the config space", start.X, start.Y, start.Theta)
+	}
+	if cSpace.hasDynamics() {
+		if !(cSpace.VMin < start.V && start.V < cSpace.VMax) {
+			add("start.v", "%.2f is outside the velocity limits (%.2f, %.2f)", start.V, cSpace.VMin, cSpace.VMax)
+		}
+		if !(cSpace.WMin < start.W && start.W < cSpace.WMax) {
+			add("start.w", "%.2f is outside the angular rate limits (%.2f, %.2f)", start.W, cSpace.WMin, cSpace.WMax)
+		}
+	}
+
+	if prob.Goal.R <= 0 {
+		add("goal_region", "radius %.2f is not positive", prob.Goal.R)
+	} else if !intersectsFreeSpace(prob.Goal, cSpace, obstacles) {
+		add("goal_region", "%v has no collision free position inside the config space", prob.Goal)
+	}
+	if h := prob.GoalHeading; h != nil && h.Tolerance < 0 {
+		add("goal_heading", "tolerance %.2f is negative", h.Tolerance)
+	}
+	if b := prob.GoalVelocity; b != nil {
+		if b.Min > b.Max {
+			add("goal_velocity", "%v is empty", *b)
+		} else if cSpace.hasDynamics() && (b.Max <= cSpace.VMin || b.Min >= cSpace.VMax) {
+			add("goal_velocity", "%v does not overlap the velocity limits (%.2f, %.2f)", *b, cSpace.VMin, cSpace.VMax)
+		}
+	}
+	if b := prob.GoalAngularRate; b != nil {
+		if b.Min > b.Max {
+			add("goal_angular_rate", "%v is empty", *b)
+		} else if cSpace.hasDynamics() && (b.Max <= cSpace.WMin || b.Min >= cSpace.WMax) {
+			add("goal_angular_rate", "%v does not overlap the angular rate limits (%.2f, %.2f)", *b, cSpace.WMin, cSpace.WMax)
+		}
+	}
+
+	if prob.Epsilon <= 0 {
+		add("epsilon", "%.2f is not positive", prob.Epsilon)
+	}
+	if prob.GoalBias < 0 || prob.GoalBias > 1 {
+		add("goal_bias", "%.2f is not a probability", prob.GoalBias)
+	}
+	return issues
+}
+
+// hasDynamics returns true if the config space has velocity limits, which
+// robots without dynamics leave at zero.
+func (c ConfigSpace) hasDynamics() bool {
+	return c.VMin != c.VMax
+}
+
+func collidingObstacle(p Point, obstacles []Circle) (Circle, bool) {
+	for _, o := range obstacles {
+		if Near(p, o) {
+			return o, true
+		}
+	}
+	return Circle{}, false
+}
+
+// intersectsFreeSpace returns true if a grid of positions covering the
+// circle has a position inside the config space and outside every obstacle.
+func intersectsFreeSpace(c Circle, cSpace ConfigSpace, obstacles []Circle) bool {
+	const steps = 16
+	step := c.R / steps
+	for i := -steps; i <= steps; i++ {
+		for j := -steps; j <= steps; j++ {
+			p := Point{X: c.X + float64(i)*step, Y: c.Y + float64(j)*step}
+			if !Near(p, c) || !cSpace.Contains(p.X, p.Y) {
+				continue
+			}
+			if _, collides := collidingObstacle(p, obstacles); !collides {
+				return true
+			}
+		}
+	}
+	return false
+}
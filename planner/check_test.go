@@ -0,0 +1,72 @@
+package planner
+
+import (
+	"testing"
+)
+
+func TestCheckProblem(t *testing.T) {
+	cSpace := ConfigSpace{XMin: 0, XMax: 50, YMin: 0, YMax: 50}
+	dynamic := ConfigSpace{XMin: 0, XMax: 50, YMin: 0, YMax: 50, VMin: -5, VMax: 5, WMin: -1, WMax: 1}
+	obstacles := []Circle{{X: 25, Y: 25, R: 5}}
+	robot := Robot{{X: -2}, {X: 2}}
+	feasible := Problem{Start: Point{X: 5, Y: 5}, Goal: Circle{X: 45, Y: 45, R: 5}, Epsilon: 1, GoalBias: 0.05}
+
+	with := func(change func(p *Problem)) Problem {
+		p := feasible
+		change(&p)
+		return p
+	}
+	cases := []struct {
+		name   string
+		prob   Problem
+		cSpace ConfigSpace
+		robot  Robot
+		fields []string
+	}{
+		{"feasible", feasible, cSpace, nil, nil},
+		{"feasible with dynamics", feasible, dynamic, robot, nil},
+		{"start outside", with(func(p *Problem) { p.Start.X = -1 }), cSpace, nil, []string{"start"}},
+		{"start in obstacle", with(func(p *Problem) { p.Start = Point{X: 24, Y: 24} }), cSpace, nil, []string{"start"}},
+		{"footprint in obstacle", with(func(p *Problem) { p.Start = Point{X: 31, Y: 25} }), cSpace, robot, []string{"start"}},
+		{"footprint outside", with(func(p *Problem) { p.Start = Point{X: 1, Y: 5} }), cSpace, robot, []string{"start"}},
+		{"start velocity", with(func(p *Problem) { p.Start.V, p.Start.W = 6, -2 }), dynamic, nil, []string{"start.v", "start.w"}},
+		{"goal in obstacle", with(func(p *Problem) { p.Goal = Circle{X: 25, Y: 25, R: 2} }), cSpace, nil, []string{"goal_region"}},
+		{"goal outside", with(func(p *Problem) { p.Goal = Circle{X: 60, Y: 60, R: 5} }), cSpace, nil, []string{"goal_region"}},
+		{"goal partly outside", with(func(p *Problem) { p.Goal = Circle{X: 52, Y: 25, R: 5} }), cSpace, nil, nil},
+		{"goal radius", with(func(p *Problem) { p.Goal.R = 0 }), cSpace, nil, []string{"goal_region"}},
+		{"goal velocity", with(func(p *Problem) { p.GoalVelocity = &Bounds{Min: 6, Max: 7} }), dynamic, nil, []string{"goal_velocity"}},
+		{"empty goal angular rate", with(func(p *Problem) { p.GoalAngularRate = &Bounds{Min: 1, Max: 0} }), dynamic, nil, []string{"goal_angular_rate"}},
+		{"epsilon and goal bias", with(func(p *Problem) { p.Epsilon, p.GoalBias = 0, 2 }), cSpace, nil, []string{"epsilon", "goal_bias"}},
+	}
+	for _, c := range cases {
+		issues := CheckProblem(3, c.prob, c.cSpace, obstacles, c.robot)
+		var fields []string
+		for _, issue := range issues {
+			equals(t, 3, issue.Problem)
+			fields = append(fields, issue.Field)
+		}
+		assert(t, len(fields) == len(c.fields), "%s: expected issues in %v, got %v", c.name, c.fields, issues)
+		for i := range fields {
+			equals(t, c.fields[i], fields[i])
+		}
+	}
+}
+
+func TestConfigCheck(t *testing.T) {
+	config := &Config{
+		ConfigSpace: ConfigSpace{XMin: 0, XMax: 50, YMin: 0, YMax: 50},
+		Problems: []Problem{
+			{Name: "ok", Start: Point{X: 5, Y: 5}, Goal: Circle{X: 45, Y: 45, R: 5}, Epsilon: 1},
+			{Name: "bad", Start: Point{X: 5, Y: 5}, Goal: Circle{X: 45, Y: 45, R: 5}},
+		},
+	}
+	err := config.Check(nil, nil)
+	infeasible, isInfeasible := err.(FeasibilityError)
+	assert(t, isInfeasible, "expected a FeasibilityError, got %v", err)
+	ok(t, infeasible.Problem(0))
+	assert(t, infeasible.Problem(1) != nil, "expected problem 1 to be infeasible")
+	equals(t, "infeasible problems: problem 1 (bad): epsilon 0.00 is not positive", err.Error())
+
+	config.Problems = config.Problems[:1]
+	ok(t, config.Check(nil, nil))
+}
@@ -0,0 +1,299 @@
+package planner
+
+import (
+	"math"
+	"sort"
+
+	"github.com/hdhauk/enae788v/angle"
+)
+
+// Polygon is a convex obstacle given by its vertices.
+type Polygon []Point
+
+// Footprint is a robot footprint decomposed into convex parts, each given by
+// its vertices relative to the robot center.
+type Footprint [][]Point
+
+// PointFootprint returns a footprint with every point of r as a part of its
+// own. Its C-obstacles collide where a FootprintChecker would, apart from the
+// inflation of the slices.
+func PointFootprint(r Robot) Footprint {
+	f := make(Footprint, len(r))
+	for i, p := range r {
+		f[i] = []Point{p}
+	}
+	return f
+}
+
+// HullFootprint returns a footprint with the convex hull of r as its only
+// part. Its C-obstacles cover those of PointFootprint, with a single
+// C-obstacle per obstacle.
+func HullFootprint(r Robot) Footprint {
+	return Footprint{convexHull(r)}
+}
+
+// reach returns the largest distance from the robot center to a vertex of the
+// footprint.
+func (f Footprint) reach() float64 {
+	var d float64
+	for _, part := range f {
+		d = math.Max(d, Robot(part).reach())
+	}
+	return d
+}
+
+// CObstacle is a configuration space obstacle of the robot center at a fixed
+// heading: every position within R of the convex polygon of Vertices, which
+// can also be a single point or a segment. The Minkowski sum of a circle and
+// the rotated and mirrored part of a footprint has the radius of the circle,
+// and that of a polygon has no radius.
+type CObstacle struct {
+	Vertices []Point
+	R        float64
+}
+
+// Contains returns true if (x, y) is closer than R to the polygon, or inside
+// it.
+func (o CObstacle) Contains(x, y float64) bool {
+	return o.distance(x, y) < o.R
+}
+
+// distance returns the distance from (x, y) to the polygon, which is negative
+// inside it.
+func (o CObstacle) distance(x, y float64) float64 {
+	v := o.Vertices
+	d := math.Inf(1)
+	inside := len(v) >= 3
+	for i := range v {
+		a, b := v[i], v[(i+1)%len(v)]
+		d = math.Min(d, segmentDistance(x, y, a, b))
+		if (b.X-a.X)*(y-a.Y)-(b.Y-a.Y)*(x-a.X) <= 0 {
+			inside = false
+		}
+	}
+	if inside {
+		return -d
+	}
+	return d
+}
+
+// bounds returns the corners of the box around the C-obstacle.
+func (o CObstacle) bounds() (Point, Point) {
+	lo, hi := Point{X: math.Inf(1), Y: math.Inf(1)}, Point{X: math.Inf(-1), Y: math.Inf(-1)}
+	for _, v := range o.Vertices {
+		lo.X, lo.Y = math.Min(lo.X, v.X-o.R), math.Min(lo.Y, v.Y-o.R)
+		hi.X, hi.Y = math.Max(hi.X, v.X+o.R), math.Max(hi.Y, v.Y+o.R)
+	}
+	return lo, hi
+}
+
+// CSlice is the configuration space of the robot center at the headings
+// nearest Theta. The center is free if it is strictly within the bounds, that
+// keep the footprint inside the config space, and outside every C-obstacle.
+type CSlice struct {
+	Theta                  float64
+	XMin, XMax, YMin, YMax float64
+	Obstacles              []CObstacle
+
+	// The C-obstacles overlapping every cell of a grid over the config
+	// space, row by row.
+	cell   float64
+	nx, ny int
+	cells  [][]int32
+}
+
+// cSliceCells is the number of cells of the grid of a slice along the longer
+// side of the config space.
+const cSliceCells = 64
+
+// Free returns true if the robot center at (x, y) is free.
+func (s *CSlice) Free(x, y float64) bool {
+	if !(s.XMin < x && x < s.XMax && s.YMin < y && y < s.YMax) {
+		return false
+	}
+	for _, i := range s.cells[s.index(x, y)] {
+		if s.Obstacles[i].Contains(x, y) {
+			return false
+		}
+	}
+	return true
+}
+
+// index returns the cell of the grid containing (x, y), clamped to the grid.
+func (s *CSlice) index(x, y float64) int {
+	i := int(math.Min(math.Max(math.Floor((x-s.XMin)/s.cell), 0), float64(s.nx-1)))
+	j := int(math.Min(math.Max(math.Floor((y-s.YMin)/s.cell), 0), float64(s.ny-1)))
+	return j*s.nx + i
+}
+
+// CObstacles are the C-obstacles of a robot footprint among circular and
+// convex polygonal obstacles, at evenly spaced headings. A slice stands in
+// for every heading within half a slice of its own, so its C-obstacles and
+// bounds are grown by Inflation, the furthest a vertex of the footprint moves
+// when turning by half a slice. A free center is then free at every heading
+// of the slice, but centers that are free might not be.
+type CObstacles struct {
+	Space     ConfigSpace
+	Slices    []CSlice
+	Reach     float64 // largest distance from the robot center to a vertex of the footprint
+	Inflation float64
+}
+
+// NewCObstacles computes the C-obstacles of footprint among the obstacles at
+// the given number of headings, the first at 0. The C-obstacles of a part
+// are the Minkowski sums of the obstacles and the part rotated to the heading
+// and mirrored through the robot center.
+func NewCObstacles(cSpace ConfigSpace, circles []Circle, polygons []Polygon, footprint Footprint, slices int) *CObstacles {
+	c := &CObstacles{Space: cSpace, Slices: make([]CSlice, slices), Reach: footprint.reach()}
+	c.Inflation = 2 * c.Reach * math.Sin(math.Pi/float64(2*slices))
+	for k := range c.Slices {
+		theta := float64(k) * 2 * math.Pi / float64(slices)
+		sin, cos := math.Sincos(theta)
+		s := &c.Slices[k]
+		s.Theta = theta
+
+		// The parts rotated to the heading and mirrored through the center.
+		var parts [][]Point
+		lo, hi := Point{X: math.Inf(1), Y: math.Inf(1)}, Point{X: math.Inf(-1), Y: math.Inf(-1)}
+		for _, part := range footprint {
+			var mirrored []Point
+			for _, p := range part {
+				q := Point{X: -(p.X*cos - p.Y*sin), Y: -(p.X*sin + p.Y*cos)}
+				mirrored = append(mirrored, q)
+				lo.X, lo.Y = math.Min(lo.X, q.X), math.Min(lo.Y, q.Y)
+				hi.X, hi.Y = math.Max(hi.X, q.X), math.Max(hi.Y, q.Y)
+			}
+			parts = append(parts, mirrored)
+		}
+		s.XMin, s.XMax = cSpace.XMin+hi.X+c.Inflation, cSpace.XMax+lo.X-c.Inflation
+		s.YMin, s.YMax = cSpace.YMin+hi.Y+c.Inflation, cSpace.YMax+lo.Y-c.Inflation
+
+		for _, part := range parts {
+			for _, o := range circles {
+				s.Obstacles = append(s.Obstacles, CObstacle{Vertices: minkowskiSum([]Point{{X: o.X, Y: o.Y}}, part), R: o.R + c.Inflation})
+			}
+			for _, o := range polygons {
+				s.Obstacles = append(s.Obstacles, CObstacle{Vertices: minkowskiSum(o, part), R: c.Inflation})
+			}
+		}
+		s.grid(cSpace)
+	}
+	return c
+}
+
+// grid sorts the C-obstacles of the slice into the cells they overlap.
+func (s *CSlice) grid(cSpace ConfigSpace) {
+	s.cell = math.Max(cSpace.XMax-cSpace.XMin, cSpace.YMax-cSpace.YMin) / cSliceCells
+	s.nx = int(math.Max(math.Ceil((s.XMax-s.XMin)/s.cell), 1))
+	s.ny = int(math.Max(math.Ceil((s.YMax-s.YMin)/s.cell), 1))
+	s.cells = make([][]int32, s.nx*s.ny)
+	for n, o := range s.Obstacles {
+		lo, hi := o.bounds()
+		if hi.X <= s.XMin || lo.X >= s.XMax || hi.Y <= s.YMin || lo.Y >= s.YMax {
+			continue
+		}
+		first, last := s.index(lo.X, lo.Y), s.index(hi.X, hi.Y)
+		for j := first / s.nx; j <= last/s.nx; j++ {
+			for i := first % s.nx; i <= last%s.nx; i++ {
+				s.cells[j*s.nx+i] = append(s.cells[j*s.nx+i], int32(n))
+			}
+		}
+	}
+}
+
+// Slice returns the slice of the heading theta.
+func (c *CObstacles) Slice(theta float64) *CSlice {
+	n := len(c.Slices)
+	k := int(math.Round(angle.Normalize(theta)/(2*math.Pi)*float64(n))) % n
+	return &c.Slices[k]
+}
+
+// Free returns true if the robot placed at p is free in the slice of its
+// heading.
+func (c *CObstacles) Free(p Point) bool {
+	return c.Slice(p.Theta).Free(p.X, p.Y)
+}
+
+// CObstacleChecker checks a robot footprint against precomputed C-obstacles,
+// which is faster than checking every point of the footprint against every
+// obstacle. Motions are checked as by a FootprintChecker.
+type CObstacleChecker struct {
+	CObstacles *CObstacles
+	Resolution float64
+}
+
+// Valid returns true if the robot placed at s is free.
+func (c CObstacleChecker) Valid(s State) bool {
+	return c.CObstacles.Free(Pose(s))
+}
+
+// MotionValid returns true if the robot does not collide anywhere along m.
+func (c CObstacleChecker) MotionValid(m *Motion) bool {
+	return sweptValid(c.Valid, c.CObstacles.Reach, c.Resolution, m)
+}
+
+// minkowskiSum returns the convex hull of the sums of the points of a and b.
+func minkowskiSum(a, b []Point) []Point {
+	var sums []Point
+	for _, p := range a {
+		for _, q := range b {
+			sums = append(sums, Point{X: p.X + q.X, Y: p.Y + q.Y})
+		}
+	}
+	return convexHull(sums)
+}
+
+// convexHull returns the vertices of the convex hull of points in counter
+// clockwise order, without collinear points. The hull of collinear points is
+// the segment between the two furthest apart.
+func convexHull(points []Point) []Point {
+	pts := make([]Point, 0, len(points))
+	for _, p := range points {
+		pts = append(pts, Point{X: p.X, Y: p.Y})
+	}
+	sort.Slice(pts, func(i, j int) bool {
+		return pts[i].X < pts[j].X || (pts[i].X == pts[j].X && pts[i].Y < pts[j].Y)
+	})
+	unique := pts[:0]
+	for i, p := range pts {
+		if i == 0 || p != pts[i-1] {
+			unique = append(unique, p)
+		}
+	}
+	pts = unique
+	if len(pts) < 3 {
+		return pts
+	}
+	cross := func(o, a, b Point) float64 {
+		return (a.X-o.X)*(b.Y-o.Y) - (a.Y-o.Y)*(b.X-o.X)
+	}
+
+	// Andrew's monotone chain: the lower hull from left to right, then the
+	// upper hull back.
+	var hull []Point
+	for pass := 0; pass < 2; pass++ {
+		start := len(hull)
+		for _, p := range pts {
+			for len(hull) >= start+2 && cross(hull[len(hull)-2], hull[len(hull)-1], p) <= 0 {
+				hull = hull[:len(hull)-1]
+			}
+			hull = append(hull, p)
+		}
+		hull = hull[:len(hull)-1]
+		for i, j := 0, len(pts)-1; i < j; i, j = i+1, j-1 {
+			pts[i], pts[j] = pts[j], pts[i]
+		}
+	}
+	return hull
+}
+
+// segmentDistance returns the distance from (x, y) to the segment from a to
+// b.
+func segmentDistance(x, y float64, a, b Point) float64 {
+	dx, dy := b.X-a.X, b.Y-a.Y
+	t := 0.0
+	if l := dx*dx + dy*dy; l > 0 {
+		t = math.Max(0, math.Min(1, ((x-a.X)*dx+(y-a.Y)*dy)/l))
+	}
+	return math.Hypot(x-a.X-t*dx, y-a.Y-t*dy)
+}
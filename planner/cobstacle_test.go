@@ -0,0 +1,143 @@
+package planner
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestConvexHull(t *testing.T) {
+	square := []Point{{X: 0, Y: 0}, {X: 1, Y: 1}, {X: 2, Y: 0}, {X: 2, Y: 2}, {X: 1, Y: 0}, {X: 0, Y: 2}, {X: 0, Y: 0}}
+	equals(t, []Point{{X: 0, Y: 0}, {X: 2, Y: 0}, {X: 2, Y: 2}, {X: 0, Y: 2}}, convexHull(square))
+	equals(t, []Point{{X: -1, Y: 0}, {X: 1, Y: 0}}, convexHull([]Point{{X: 0}, {X: 1}, {X: -1}, {X: 0.5}}))
+	equals(t, []Point{{X: 3, Y: 4}}, convexHull([]Point{{X: 3, Y: 4}, {X: 3, Y: 4}, {X: 3, Y: 4}}))
+
+	// The heading of the robot points is not part of the hull.
+	hull := HullFootprint(Robot{{X: -1, Theta: 1}, {X: 1}, {X: 0, Y: 0.5}, {X: 0, Y: 0.1}})
+	equals(t, Footprint{{{X: -1}, {X: 1}, {X: 0, Y: 0.5}}}, hull)
+}
+
+func TestCObstacle(t *testing.T) {
+	disk := CObstacle{Vertices: []Point{{X: 0, Y: 0}, {X: 2, Y: 0}}, R: 1}
+	assert(t, disk.Contains(1, 0.9), "inside the capsule")
+	assert(t, disk.Contains(2.5, 0.5), "inside the rounded end")
+	assert(t, !disk.Contains(1, 1), "on the boundary")
+	assert(t, !disk.Contains(3.1, 0), "beyond the end")
+
+	square := CObstacle{Vertices: []Point{{X: 0, Y: 0}, {X: 2, Y: 0}, {X: 2, Y: 2}, {X: 0, Y: 2}}}
+	assert(t, square.Contains(1, 1), "inside the polygon")
+	assert(t, !square.Contains(2, 1), "on the edge of a polygon without radius")
+	equals(t, -1.0, square.distance(1, 1))
+}
+
+func TestCObstacles(t *testing.T) {
+	cSpace := ConfigSpace{XMin: 0, XMax: 20, YMin: 0, YMax: 10}
+	robot := Robot{{X: 1, Y: 0}, {X: -1, Y: 0}}
+	square := Polygon{{X: 8, Y: 4}, {X: 10, Y: 4}, {X: 10, Y: 6}, {X: 8, Y: 6}}
+	c := NewCObstacles(cSpace, []Circle{{X: 15, Y: 5, R: 0.9}}, []Polygon{square}, PointFootprint(robot), 360)
+	equals(t, 360, len(c.Slices))
+	assert(t, c.Inflation > 0 && c.Inflation < 0.01, "unexpected inflation %v", c.Inflation)
+
+	// Facing along x the robot reaches 1 beyond its center on either side.
+	s := c.Slice(0)
+	equals(t, 0.0, s.Theta)
+	assert(t, math.Abs(s.XMin-1-c.Inflation) < 1e-9 && math.Abs(s.YMin-c.Inflation) < 1e-9, "unexpected bounds %v %v", s.XMin, s.YMin)
+	assert(t, c.Slice(2*math.Pi-1e-6) == s, "headings just below 2π are in the first slice")
+	assert(t, c.Slice(math.Pi/2).Theta == math.Pi/2, "unexpected slice %v", c.Slice(math.Pi/2).Theta)
+
+	var tests = []struct {
+		name string
+		p    Point
+		free bool
+	}{
+		{"front point inside the polygon", Point{X: 7.5, Y: 5}, false},
+		{"clear of the polygon", Point{X: 6.5, Y: 5}, true},
+		{"turned to pass below the polygon", Point{X: 9, Y: 2.5, Theta: math.Pi / 2}, true},
+		{"turned to reach into the polygon", Point{X: 9, Y: 3, Theta: math.Pi / 2}, false},
+		{"rear point inside the circle", Point{X: 16.5, Y: 5}, false},
+		{"between the points around the circle", Point{X: 15, Y: 5}, true},
+		{"outside the config space", Point{X: 19.5, Y: 5}, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			equals(t, tc.free, c.Free(tc.p))
+		})
+	}
+}
+
+// TestPolygonCObstacles compares the C-obstacles of a polygon at a slice
+// turned away from the axes with placing every point of the footprint: a
+// free center has no point in the polygon, and a center whose points are all
+// further than the inflation from it is free.
+func TestPolygonCObstacles(t *testing.T) {
+	cSpace := ConfigSpace{XMin: 0, XMax: 20, YMin: 0, YMax: 20}
+	robot := Robot{{X: 2, Y: 0}, {X: -1, Y: 1}, {X: -1, Y: -1}}
+	triangle := Polygon{{X: 8, Y: 8}, {X: 13, Y: 9}, {X: 9, Y: 12}}
+	c := NewCObstacles(cSpace, nil, []Polygon{triangle}, PointFootprint(robot), 16)
+	s := &c.Slices[3]
+	assert(t, s.Theta != 0, "expected a turned slice")
+	polygon := CObstacle{Vertices: triangle}
+
+	rng := rand.New(rand.NewSource(1))
+	sin, cos := math.Sincos(s.Theta)
+	var free, clear int
+	for i := 0; i < 20000; i++ {
+		x, y := 4+rng.Float64()*12, 4+rng.Float64()*12
+		nearest := math.Inf(1)
+		for _, p := range robot {
+			nearest = math.Min(nearest, polygon.distance(x+p.X*cos-p.Y*sin, y+p.X*sin+p.Y*cos))
+		}
+		if s.Free(x, y) {
+			assert(t, nearest >= 0, "(%.3f, %.3f) is free but a point of the footprint is in the polygon", x, y)
+			free++
+		}
+		if nearest > c.Inflation {
+			assert(t, s.Free(x, y), "(%.3f, %.3f) is clear of the polygon but not free", x, y)
+			clear++
+		}
+	}
+	assert(t, clear > 0 && free < 20000, "expected centers both in and clear of the C-obstacles, %d free, %d clear", free, clear)
+}
+
+// TestCObstacleChecker compares the C-obstacles with checking every point of
+// the footprint of hw3 at random states: a free state is always valid, and
+// with enough slices nearly every valid state is free.
+func TestCObstacleChecker(t *testing.T) {
+	prob, cSpace, obstacles, robot := loadProblem(t, "hw3", 0)
+	exact := FootprintChecker{Obstacles: obstacles, Space: cSpace, Robot: robot, Resolution: 0.5}
+	points := CObstacleChecker{CObstacles: NewCObstacles(cSpace, obstacles, nil, PointFootprint(robot), 64), Resolution: 0.5}
+	hull := CObstacleChecker{CObstacles: NewCObstacles(cSpace, obstacles, nil, HullFootprint(robot), 64), Resolution: 0.5}
+
+	rng := rand.New(rand.NewSource(1))
+	var valid, pointsFree, hullFree int
+	for i := 0; i < 20000; i++ {
+		p := Point{X: rng.Float64() * 100, Y: rng.Float64() * 100, Theta: rng.Float64() * 2 * math.Pi}
+		ok := exact.Valid(p)
+		if ok {
+			valid++
+		}
+		if points.Valid(p) {
+			assert(t, ok, "%v is free among the C-obstacles of the points but not valid", p)
+			pointsFree++
+		}
+		if hull.Valid(p) {
+			assert(t, points.Valid(p), "%v is free among the C-obstacles of the hull but not of the points", p)
+			hullFree++
+		}
+	}
+	assert(t, float64(pointsFree) > 0.99*float64(valid), "only %d of %d valid states are free", pointsFree, valid)
+	assert(t, float64(hullFree) > 0.98*float64(valid), "only %d of %d valid states are free of the hull", hullFree, valid)
+
+	m := StraightLine{Epsilon: 10}.Steer(prob.Start, Point{X: prob.Start.X + 10, Y: prob.Start.Y, Theta: prob.Start.Theta + 1})
+	equals(t, exact.MotionValid(m), points.MotionValid(m))
+}
+
+func BenchmarkCObstacleChecker(b *testing.B) {
+	prob, cSpace, obstacles, robot := loadProblem(b, "hw3", 0)
+	checker := CObstacleChecker{CObstacles: NewCObstacles(cSpace, obstacles, nil, HullFootprint(robot), 64), Resolution: 0.5}
+	m := StraightLine{Epsilon: 10}.Steer(prob.Start, Point{X: prob.Start.X + 10, Y: prob.Start.Y, Theta: prob.Start.Theta + 1})
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		checker.MotionValid(m)
+	}
+}
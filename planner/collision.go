@@ -0,0 +1,222 @@
+package planner
+
+import (
+	"math"
+
+	"github.com/hdhauk/enae788v/angle"
+	"github.com/ungerik/go3d/float64/vec2"
+)
+
+// CheckerFunc is a CollisionChecker that validates single states with a
+// function. A motion is valid if every state along it is valid.
+type CheckerFunc func(s State) bool
+
+// Valid returns f(s).
+func (f CheckerFunc) Valid(s State) bool {
+	return f(s)
+}
+
+// MotionValid returns true if every state along m is valid.
+func (f CheckerFunc) MotionValid(m *Motion) bool {
+	return pathValid(f, m)
+}
+
+// pathValid returns true if every state along the path of m is valid, or
+// the final state if m has no path.
+func pathValid(valid func(State) bool, m *Motion) bool {
+	if len(m.Path) == 0 {
+		return valid(m.To)
+	}
+	for _, s := range m.Path {
+		if !valid(s) {
+			return false
+		}
+	}
+	return true
+}
+
+// PointChecker checks a point robot against circular obstacles. Motions are
+// straight lines, and are checked exactly.
+type PointChecker struct {
+	Obstacles []Circle
+	Space     ConfigSpace
+}
+
+// Valid returns true if s is inside the configuration space and outside every
+// obstacle.
+func (c PointChecker) Valid(s State) bool {
+	p := Pose(s)
+	if !c.Space.Contains(p.X, p.Y) {
+		return false
+	}
+	for _, o := range c.Obstacles {
+		if Near(p, o) {
+			return false
+		}
+	}
+	return true
+}
+
+// MotionValid returns true if the straight line from m.From to m.To does not
+// intersect any obstacle.
+func (c PointChecker) MotionValid(m *Motion) bool {
+	if !c.Valid(m.To) {
+		return false
+	}
+
+	v, w := Pose(m.From), Pose(m.To)
+	a := &vec2.T{w.X - v.X, w.Y - v.Y}
+	aNorm := a.Normalized()
+	for _, o := range c.Obstacles {
+		// https://stackoverflow.com/a/1079478/7035436
+		b := &vec2.T{o.X - v.X, o.Y - v.Y}
+		theta := vec2.Angle(a, b)
+		lengthC := b.Length() * math.Cos(theta)
+		c := aNorm
+		c.Scale(lengthC)
+		d := c
+		d.Sub(b)
+
+		if d.Length() < o.R && c.Length() < a.Length() {
+			return false
+		}
+
+		if c.Length() < a.Length() {
+			e := *a
+			e.Sub(b)
+
+			if e.Length() < o.R {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// FootprintChecker checks every point of a robot footprint against circular
+// obstacles. Straight line motions are checked at waypoints close enough that
+// no point of the footprint moves more than Resolution between them, and
+// other motions at every state along their path.
+type FootprintChecker struct {
+	Obstacles  []Circle
+	Space      ConfigSpace
+	Robot      Robot
+	Resolution float64
+}
+
+// Valid returns true if every point of the robot placed at s is inside the
+// configuration space and outside every obstacle.
+func (c FootprintChecker) Valid(s State) bool {
+	base := Pose(s)
+	for _, robotPoint := range c.Robot {
+		p := RobotPointGlobal(base, robotPoint)
+		if !c.Space.Contains(p.X, p.Y) {
+			return false
+		}
+		for _, o := range c.Obstacles {
+			if Near(p, o) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// MotionValid returns true if the robot does not collide anywhere along m.
+func (c FootprintChecker) MotionValid(m *Motion) bool {
+	return sweptValid(c.Valid, c.Robot.reach(), c.Resolution, m)
+}
+
+// sweptValid returns true if every state along the path of m is valid, or for
+// straight line motions every waypoint close enough that no point within
+// reach of the robot center moves more than resolution between them. The
+// waypoints turn from the heading of m.From to that of m.To along the
+// shortest arc, which also checks turns in place.
+func sweptValid(valid func(State) bool, reach, resolution float64, m *Motion) bool {
+	if len(m.Path) > 0 {
+		return pathValid(valid, m)
+	}
+
+	// Points of the footprint away from the center move further than the
+	// center where the heading turns.
+	from, to := Pose(m.From), Pose(m.To)
+	sweep := math.Hypot(to.X-from.X, to.Y-from.Y) + reach*angle.Distance(from.Theta, to.Theta)
+	steps := int(math.Ceil(sweep / resolution))
+	for i := 0; i < steps; i++ {
+		if !valid(Interpolate(from, to, float64(i)/float64(steps))) {
+			return false
+		}
+	}
+	return valid(m.To)
+}
+
+// reach returns the largest distance from the robot center to a point of the
+// footprint.
+func (r Robot) reach() float64 {
+	var d float64
+	for _, p := range r {
+		d = math.Max(d, math.Hypot(p.X, p.Y))
+	}
+	return d
+}
+
+// SE2 returns the state space of the robot moving in straight lines: turns
+// weigh as much as the distance swept by the furthest point of the footprint,
+// and a step toward a sample turns at most π/4. A point robot has no heading,
+// so it plans in the plane.
+func (r Robot) SE2() SE2 {
+	if r.reach() == 0 {
+		return SE2{}
+	}
+	return SE2{TurnWeight: r.reach(), MaxTurn: math.Pi / 4}
+}
+
+// KinodynamicChecker is a FootprintChecker that also requires the velocities
+// to be within the limits of the configuration space.
+type KinodynamicChecker struct {
+	FootprintChecker
+}
+
+// Valid returns true if the robot placed at s does not collide, and its
+// velocities are within limits.
+func (c KinodynamicChecker) Valid(s State) bool {
+	p, cSpace := Pose(s), c.Space
+	legalVelocities := (cSpace.VMin < p.V && p.V < cSpace.VMax) && (cSpace.WMin < p.W && p.W < cSpace.WMax)
+	return legalVelocities && c.FootprintChecker.Valid(s)
+}
+
+// MotionValid returns true if every state along m is valid.
+func (c KinodynamicChecker) MotionValid(m *Motion) bool {
+	return pathValid(c.Valid, m)
+}
+
+// RobotPointGlobal returns the position of offset, given relative to the
+// robot center, when the robot is placed at base.
+func RobotPointGlobal(base, offset Point) Point {
+	b := vec2.T{offset.X, offset.Y}
+	b.Rotate(base.Theta)
+	a := vec2.T{base.X, base.Y}
+	c := a.Add(&b)
+
+	return Point{X: c[0], Y: c[1]}
+}
+
+// Interpolate returns the pose a fraction t of the way from a to b, moving
+// along the straight line between their positions and turning along the
+// shortest arc between their headings.
+func Interpolate(a, b Point, t float64) Point {
+	return Point{X: a.X + t*(b.X-a.X), Y: a.Y + t*(b.Y-a.Y), Theta: angle.Lerp(a.Theta, b.Theta, t)}
+}
+
+// PointsAlongPath returns waypoints epsilon apart on the straight line from
+// start toward end, beginning at start. The heading turns from that of start
+// to that of end along the shortest arc, in proportion to the distance
+// travelled.
+func PointsAlongPath(start, end Point, epsilon float64) []Point {
+	distance := math.Hypot(end.X-start.X, end.Y-start.Y)
+	pointsAlong := []Point{start}
+	for i := 1.0; i <= math.Floor(distance/epsilon); i++ {
+		pointsAlong = append(pointsAlong, Interpolate(start, end, i*epsilon/distance))
+	}
+	return pointsAlong
+}
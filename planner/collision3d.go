@@ -0,0 +1,172 @@
+package planner
+
+import "math"
+
+// Checker3D checks every point of a robot footprint in 3D space against
+// sphere and box obstacles. The robot translates without turning, so every
+// point of the footprint moves along a straight line as the center does, and
+// straight line motions are checked exactly by testing those segments.
+type Checker3D struct {
+	Spheres []Sphere
+	Boxes   []Box
+	Space   ConfigSpace3D
+	Robot   Robot3D // nil for a point robot
+}
+
+// points returns the points of the robot placed at p.
+func (c Checker3D) points(p Point3D) []Point3D {
+	if c.Robot == nil {
+		return []Point3D{p}
+	}
+	pts := make([]Point3D, len(c.Robot))
+	for i, o := range c.Robot {
+		pts[i] = Point3D{X: p.X + o.X, Y: p.Y + o.Y, Z: p.Z + o.Z}
+	}
+	return pts
+}
+
+// Valid returns true if every point of the robot placed at s is inside the
+// configuration space and outside every obstacle.
+func (c Checker3D) Valid(s State) bool {
+	for _, p := range c.points(Pose3D(s)) {
+		if !c.Space.Contains(p) {
+			return false
+		}
+		for _, o := range c.Spheres {
+			if o.Contains(p) {
+				return false
+			}
+		}
+		for _, o := range c.Boxes {
+			if o.Contains(p) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// MotionValid returns true if no point of the robot passes through an
+// obstacle or leaves the configuration space along m.
+func (c Checker3D) MotionValid(m *Motion) bool {
+	if len(m.Path) > 0 {
+		return pathValid(c.Valid, m)
+	}
+	// The config space is convex, so the segments stay inside it if their
+	// ends do.
+	if !c.Valid(m.From) || !c.Valid(m.To) {
+		return false
+	}
+	from, to := c.points(Pose3D(m.From)), c.points(Pose3D(m.To))
+	for i := range from {
+		for _, o := range c.Spheres {
+			if o.Intersects(from[i], to[i]) {
+				return false
+			}
+		}
+		for _, o := range c.Boxes {
+			if o.Intersects(from[i], to[i]) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Contains returns true if p is strictly inside the sphere.
+func (s Sphere) Contains(p Point3D) bool {
+	return distance3D(p, s.Center()) < s.R
+}
+
+// Intersects returns true if the segment from a to b passes strictly inside
+// the sphere.
+func (s Sphere) Intersects(a, b Point3D) bool {
+	d := Point3D{X: b.X - a.X, Y: b.Y - a.Y, Z: b.Z - a.Z}
+	t := 0.0
+	if l := d.X*d.X + d.Y*d.Y + d.Z*d.Z; l > 0 {
+		t = ((s.X-a.X)*d.X + (s.Y-a.Y)*d.Y + (s.Z-a.Z)*d.Z) / l
+		t = math.Max(0, math.Min(1, t))
+	}
+	return s.Contains(Point3D{X: a.X + t*d.X, Y: a.Y + t*d.Y, Z: a.Z + t*d.Z})
+}
+
+// rotation returns the matrix that rotates the axes of the box into place:
+// by the yaw about z, then the pitch about the turned y axis, then the roll
+// about the turned x axis.
+func (b Box) rotation() [3][3]float64 {
+	sy, cy := math.Sincos(b.Yaw)
+	sp, cp := math.Sincos(b.Pitch)
+	sr, cr := math.Sincos(b.Roll)
+	return [3][3]float64{
+		{cy * cp, cy*sp*sr - sy*cr, cy*sp*cr + sy*sr},
+		{sy * cp, sy*sp*sr + cy*cr, sy*sp*cr - cy*sr},
+		{-sp, cp * sr, cp * cr},
+	}
+}
+
+// local returns p in the frame of the box, centered and aligned with it.
+func (b Box) local(r [3][3]float64, p Point3D) [3]float64 {
+	d := [3]float64{p.X - b.Center.X, p.Y - b.Center.Y, p.Z - b.Center.Z}
+	var q [3]float64
+	for i := range q {
+		q[i] = r[0][i]*d[0] + r[1][i]*d[1] + r[2][i]*d[2]
+	}
+	return q
+}
+
+// half returns half the size of the box along each of its axes.
+func (b Box) half() [3]float64 {
+	return [3]float64{b.Size.X / 2, b.Size.Y / 2, b.Size.Z / 2}
+}
+
+// Contains returns true if p is strictly inside the box.
+func (b Box) Contains(p Point3D) bool {
+	q, h := b.local(b.rotation(), p), b.half()
+	return math.Abs(q[0]) < h[0] && math.Abs(q[1]) < h[1] && math.Abs(q[2]) < h[2]
+}
+
+// Intersects returns true if the segment from p to q passes strictly inside
+// the box. The segment is clipped to the slab between every pair of opposite
+// faces, and passes inside if a part of it is left.
+func (b Box) Intersects(p, q Point3D) bool {
+	r, h := b.rotation(), b.half()
+	u, v := b.local(r, p), b.local(r, q)
+	enter, exit := 0.0, 1.0
+	for i := range h {
+		d := v[i] - u[i]
+		if d == 0 {
+			if math.Abs(u[i]) >= h[i] {
+				return false
+			}
+			continue
+		}
+		t0, t1 := (-h[i]-u[i])/d, (h[i]-u[i])/d
+		if t0 > t1 {
+			t0, t1 = t1, t0
+		}
+		enter, exit = math.Max(enter, t0), math.Min(exit, t1)
+		if enter >= exit {
+			return false
+		}
+	}
+	return true
+}
+
+// Corners returns the eight corners of the box, the first four on the face
+// of its lowest local z in order around it, and the last four above them.
+func (b Box) Corners() [8]Point3D {
+	r, h := b.rotation(), b.half()
+	var corners [8]Point3D
+	for i, s := range [8][3]float64{
+		{-1, -1, -1}, {1, -1, -1}, {1, 1, -1}, {-1, 1, -1},
+		{-1, -1, 1}, {1, -1, 1}, {1, 1, 1}, {-1, 1, 1},
+	} {
+		l := [3]float64{s[0] * h[0], s[1] * h[1], s[2] * h[2]}
+		corners[i] = Point3D{
+			X: b.Center.X + r[0][0]*l[0] + r[0][1]*l[1] + r[0][2]*l[2],
+			Y: b.Center.Y + r[1][0]*l[0] + r[1][1]*l[1] + r[1][2]*l[2],
+			Z: b.Center.Z + r[2][0]*l[0] + r[2][1]*l[1] + r[2][2]*l[2],
+		}
+	}
+	return corners
+}
@@ -0,0 +1,84 @@
+package planner
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSphere(t *testing.T) {
+	s := Sphere{X: 10, Y: 10, Z: 10, R: 5}
+	assert(t, s.Contains(Point3D{X: 12, Y: 10, Z: 13}), "point inside")
+	assert(t, !s.Contains(Point3D{X: 15, Y: 10, Z: 10}), "point on the surface")
+
+	var tests = []struct {
+		name string
+		a, b Point3D
+		exp  bool
+	}{
+		{"through the center", Point3D{X: 0, Y: 10, Z: 10}, Point3D{X: 20, Y: 10, Z: 10}, true},
+		{"tangent", Point3D{X: 0, Y: 10, Z: 15}, Point3D{X: 20, Y: 10, Z: 15}, false},
+		{"ends before the sphere", Point3D{X: 0, Y: 10, Z: 10}, Point3D{X: 4, Y: 10, Z: 10}, false},
+		{"ends inside", Point3D{X: 0, Y: 10, Z: 10}, Point3D{X: 8, Y: 10, Z: 10}, true},
+		{"a point", Point3D{X: 0, Y: 0, Z: 0}, Point3D{X: 0, Y: 0, Z: 0}, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			equals(t, tc.exp, s.Intersects(tc.a, tc.b))
+		})
+	}
+}
+
+func TestBox(t *testing.T) {
+	aligned := Box{Center: Point3D{X: 10, Y: 10, Z: 10}, Size: Point3D{X: 10, Y: 4, Z: 2}}
+	assert(t, aligned.Contains(Point3D{X: 14, Y: 11, Z: 10.5}), "point inside")
+	assert(t, !aligned.Contains(Point3D{X: 14, Y: 12, Z: 10}), "point on a face")
+
+	// Turned a quarter about z, the long side of the box is along y.
+	turned := aligned
+	turned.Yaw = math.Pi / 2
+	assert(t, turned.Contains(Point3D{X: 10, Y: 14, Z: 10}), "point inside the turned box")
+	assert(t, !turned.Contains(Point3D{X: 14, Y: 10, Z: 10}), "point outside the turned box")
+	c := turned.Corners()
+	assert(t, math.Abs(c[0].X-12) < 1e-9 && math.Abs(c[0].Y-5) < 1e-9 && c[0].Z == 9, "unexpected corner %v", c[0])
+	assert(t, math.Abs(c[6].X-8) < 1e-9 && math.Abs(c[6].Y-15) < 1e-9 && c[6].Z == 11, "unexpected corner %v", c[6])
+
+	var tests = []struct {
+		name string
+		box  Box
+		p, q Point3D
+		exp  bool
+	}{
+		{"through", aligned, Point3D{X: 10, Y: 0, Z: 10}, Point3D{X: 10, Y: 20, Z: 10}, true},
+		{"along a face", aligned, Point3D{X: 10, Y: 12, Z: 0}, Point3D{X: 10, Y: 12, Z: 20}, false},
+		{"past a corner", aligned, Point3D{X: 14, Y: 0, Z: 10}, Point3D{X: 17, Y: 3, Z: 10}, false},
+		{"stops short", aligned, Point3D{X: 10, Y: 0, Z: 10}, Point3D{X: 10, Y: 7, Z: 10}, false},
+		{"starts inside", aligned, Point3D{X: 10, Y: 10, Z: 10}, Point3D{X: 30, Y: 30, Z: 30}, true},
+		{"through the turned box", turned, Point3D{X: 0, Y: 14, Z: 10}, Point3D{X: 20, Y: 14, Z: 10}, true},
+		{"past the turned box", turned, Point3D{X: 14, Y: 0, Z: 10}, Point3D{X: 14, Y: 20, Z: 10}, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			equals(t, tc.exp, tc.box.Intersects(tc.p, tc.q))
+		})
+	}
+}
+
+func TestChecker3D(t *testing.T) {
+	checker := Checker3D{
+		Spheres: []Sphere{{X: 10, Y: 10, Z: 10, R: 3}},
+		Boxes:   []Box{{Center: Point3D{X: 30, Y: 10, Z: 10}, Size: Point3D{X: 2, Y: 20, Z: 20}}},
+		Space:   ConfigSpace3D{XMin: 0, XMax: 40, YMin: 0, YMax: 20, ZMin: 0, ZMax: 20},
+		Robot:   Robot3D{{}, {Z: 4}},
+	}
+	equals(t, true, checker.Valid(Point3D{X: 20, Y: 10, Z: 10}))
+	equals(t, false, checker.Valid(Point3D{X: 10, Y: 10, Z: 5}))  // the top point is in the sphere
+	equals(t, false, checker.Valid(Point3D{X: 20, Y: 10, Z: 17})) // the top point is above the space
+
+	// The center passes under the sphere, but the top point does not.
+	from, to := Point3D{X: 2, Y: 10, Z: 5}, Point3D{X: 18, Y: 10, Z: 5}
+	equals(t, false, checker.MotionValid(&Motion{From: from, To: to}))
+	checker.Robot = nil
+	equals(t, true, checker.MotionValid(&Motion{From: from, To: to}))
+	// The box is a wall across the space.
+	equals(t, false, checker.MotionValid(&Motion{From: Point3D{X: 20, Y: 10, Z: 10}, To: Point3D{X: 35, Y: 10, Z: 10}}))
+}
@@ -0,0 +1,190 @@
+package planner
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPointChecker(t *testing.T) {
+	checker := PointChecker{
+		Obstacles: []Circle{
+			Circle{5, 5, 3},
+			Circle{63, 25, 8},
+			Circle{53, 25, 8},
+		},
+		Space: ConfigSpace{XMin: 0, XMax: 100, YMin: 0, YMax: 100},
+	}
+
+	var tests = []struct {
+		name string
+		a    Point
+		b    Point
+		exp  bool
+	}{
+		{"through obstacle, b on perimeter", Point{X: 4, Y: 9}, Point{X: 8, Y: 5}, false},
+		{"b on perimeter", Point{X: 4, Y: 9}, Point{X: 5, Y: 8}, true},
+		{"b just inside obstacle", Point{X: 4, Y: 9}, Point{X: 5, Y: 7.99}, false},
+		{"a and b on each side", Point{X: 1, Y: 5}, Point{X: 9, Y: 5}, false},
+		{"tangent on top", Point{X: 1, Y: 8}, Point{X: 9, Y: 8}, true},
+		{"barely not tangent", Point{X: 1, Y: 8}, Point{X: 9, Y: 8.1}, true},
+		{"above directly toward center", Point{X: 5, Y: 11}, Point{X: 5, Y: 9}, true},
+		{"above directly away from center", Point{X: 5, Y: 9}, Point{X: 5, Y: 11}, true},
+		{"to the right directly toward center", Point{X: 11, Y: 5}, Point{X: 9, Y: 5}, true},
+		{"to the right almost directly toward center", Point{X: 11, Y: 5}, Point{X: 9, Y: 4.999}, true},
+		{"to the right angled down", Point{X: 12, Y: 5}, Point{X: 9, Y: 3}, true},
+		{"special case", Point{X: 11, Y: 5}, Point{X: 10.1, Y: 4.9}, true},
+		{"over angled downward", Point{X: 59.1185, Y: 34.2603}, Point{X: 67.4492, Y: 16.078}, false},
+		{"over angled upward", Point{X: 52.00, Y: 16.60}, Point{X: 51.15, Y: 36.58}, false},
+		{"outside config space", Point{X: 90, Y: 90}, Point{X: 101, Y: 90}, false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			equals(t, tc.exp, checker.MotionValid(&Motion{From: tc.a, To: tc.b}))
+		})
+	}
+}
+
+func TestFootprintChecker(t *testing.T) {
+	checker := FootprintChecker{
+		Obstacles:  []Circle{{X: 10, Y: 5, R: 1}},
+		Space:      ConfigSpace{XMin: 0, XMax: 20, YMin: 0, YMax: 20},
+		Robot:      Robot{{X: 0, Y: 0}, {X: 0, Y: 2}},
+		Resolution: 0.5,
+	}
+
+	assert(t, checker.Valid(Point{X: 10, Y: 2}), "footprint below obstacle")
+	assert(t, !checker.Valid(Point{X: 10, Y: 3}), "footprint reaches into obstacle")
+	assert(t, checker.Valid(Point{X: 10, Y: 3, Theta: -math.Pi / 2}), "footprint rotated away from obstacle")
+	assert(t, !checker.Valid(Point{X: 19, Y: 19}), "footprint outside config space")
+
+	assert(t, checker.MotionValid(&Motion{From: Point{X: 2, Y: 1}, To: Point{X: 18, Y: 1}}), "motion below obstacle")
+	assert(t, !checker.MotionValid(&Motion{From: Point{X: 2, Y: 3}, To: Point{X: 18, Y: 3}}), "motion through obstacle")
+
+	// The waypoints of a straight motion turn from the heading of m.From to
+	// the heading of m.To.
+	turning := FootprintChecker{Space: ConfigSpace{XMin: 0, XMax: 30, YMin: 0, YMax: 30}, Robot: Robot{{X: 5, Y: -1}}, Resolution: 0.5}
+	m := &Motion{From: Point{X: 10, Y: 10, Theta: math.Pi / 2}, To: Point{X: 20, Y: 10, Theta: math.Pi / 2}}
+	turning.Obstacles = []Circle{{X: 17, Y: 9, R: 0.8}}
+	assert(t, turning.MotionValid(m), "footprint facing up passes below the obstacle")
+	turning.Obstacles = []Circle{{X: 15, Y: 15, R: 0.8}}
+	assert(t, !turning.MotionValid(m), "footprint facing up sweeps through the obstacle")
+
+	// A footprint point 5 from the center sweeps a quarter circle while the
+	// center moves 1, past an obstacle between the waypoints 0.5 apart.
+	arm := FootprintChecker{Space: turning.Space, Robot: Robot{{X: 5, Y: 0}}, Resolution: 0.5}
+	arm.Obstacles = []Circle{{X: 10.5 + 5*math.Cos(math.Pi/4), Y: 10 + 5*math.Sin(math.Pi/4), R: 0.5}}
+	m = &Motion{From: Point{X: 10, Y: 10, Theta: math.Pi / 2}, To: Point{X: 11, Y: 10, Theta: 0}}
+	assert(t, arm.Valid(m.From) && arm.Valid(m.To), "footprint clear of the obstacle at both ends")
+	assert(t, !arm.MotionValid(m), "footprint turning while moving sweeps through the obstacle")
+
+	// Turning in place sweeps the footprint along the shortest arc.
+	arm.Obstacles = []Circle{{X: 10 + 5*math.Cos(math.Pi/4), Y: 10 + 5*math.Sin(math.Pi/4), R: 0.5}}
+	m = &Motion{From: Point{X: 10, Y: 10}, To: Point{X: 10, Y: 10, Theta: math.Pi / 2}}
+	assert(t, arm.Valid(m.From) && arm.Valid(m.To), "footprint clear of the obstacle at both ends")
+	assert(t, !arm.MotionValid(m), "footprint turning in place sweeps through the obstacle")
+	m.To = Point{X: 10, Y: 10, Theta: -math.Pi / 2}
+	assert(t, arm.MotionValid(m), "footprint turning the other way misses the obstacle")
+
+	kinodynamic := KinodynamicChecker{checker}
+	kinodynamic.Space.VMin, kinodynamic.Space.VMax = -1, 1
+	kinodynamic.Space.WMin, kinodynamic.Space.WMax = -1, 1
+	assert(t, kinodynamic.Valid(&PathPoint{Point: Point{X: 10, Y: 2, V: 0.5}}), "velocity within limits")
+	assert(t, !kinodynamic.Valid(&PathPoint{Point: Point{X: 10, Y: 2, V: 1.5}}), "velocity outside limits")
+}
+
+func TestRobotPointToGlobal(t *testing.T) {
+
+	var tests = []struct {
+		name        string
+		robotCenter Point
+		robotPoint  Point
+		exp         Point
+	}{
+		{"0 degree", Point{X: 10, Y: 10, Theta: 0}, Point{X: 1, Y: 1}, Point{X: 11, Y: 11}},
+		{"90 degree", Point{X: 10, Y: 10, Theta: math.Pi / 2}, Point{X: 1, Y: 1}, Point{X: 9, Y: 11}},
+		{"-180 degree", Point{X: 10, Y: 10, Theta: -math.Pi}, Point{X: 1, Y: 1}, Point{X: 9, Y: 9}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := RobotPointGlobal(tc.robotCenter, tc.robotPoint)
+			assert(t, math.Abs(tc.exp.X-got.X) < 1e-9, "expected x=%f, got %f", tc.exp.X, got.X)
+			assert(t, math.Abs(tc.exp.Y-got.Y) < 1e-9, "expected y=%f, got %f", tc.exp.Y, got.Y)
+		})
+	}
+
+}
+
+func TestPointsAlongPath(t *testing.T) {
+
+	var tests = []struct {
+		name    string
+		start   Point
+		end     Point
+		epsilon float64
+		exp     []Point
+	}{
+		{"vertical_line", Point{X: 0, Y: 0}, Point{X: 0, Y: 5}, 1.0,
+			[]Point{
+				Point{X: 0, Y: 0, Theta: math.Pi / 2},
+				Point{X: 0, Y: 1},
+				Point{X: 0, Y: 2},
+				Point{X: 0, Y: 3},
+				Point{X: 0, Y: 4},
+				Point{X: 0, Y: 5},
+			},
+		},
+		{"horizontal_line", Point{X: 0, Y: 0}, Point{X: 2.5, Y: 0}, 0.5,
+			[]Point{
+				Point{X: 0, Y: 0},
+				Point{X: 0.5, Y: 0},
+				Point{X: 1, Y: 0},
+				Point{X: 1.5, Y: 0},
+				Point{X: 2.0, Y: 0},
+				Point{X: 2.5, Y: 0},
+			},
+		},
+		{"horizontal_line_different_angles", Point{X: 0, Y: 0, Theta: math.Pi / 2}, Point{X: 2.5, Y: 0, Theta: -math.Pi / 2}, 0.5,
+			[]Point{
+				Point{X: 0, Y: 0},
+				Point{X: 0.5, Y: 0},
+				Point{X: 1, Y: 0},
+				Point{X: 1.5, Y: 0},
+				Point{X: 2.0, Y: 0},
+				Point{X: 2.5, Y: 0},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := PointsAlongPath(tc.start, tc.end, tc.epsilon)
+			equals(t, len(tc.exp), len(got))
+			for i := 0; i < len(got); i++ {
+				assert(t, math.Abs(tc.exp[i].X-got[i].X) < 1e-9, "waypoint %d: expected x=%f, got %f", i, tc.exp[i].X, got[i].X)
+				assert(t, math.Abs(tc.exp[i].Y-got[i].Y) < 1e-9, "waypoint %d: expected y=%f, got %f", i, tc.exp[i].Y, got[i].Y)
+			}
+		})
+	}
+
+	// The heading turns evenly with the distance travelled, from that of the
+	// start.
+	got := PointsAlongPath(Point{X: 0, Y: 0}, Point{X: 2, Y: 0, Theta: math.Pi / 2}, 0.5)
+	equals(t, 5, len(got))
+	for i := 0; i < len(got); i++ {
+		exp := float64(i) / 4 * math.Pi / 2
+		assert(t, math.Abs(got[i].Theta-exp) < 1e-9, "waypoint %d: expected theta=%f, got %f", i, exp, got[i].Theta)
+	}
+
+}
+
+func BenchmarkFootprintChecker(b *testing.B) {
+	prob, cSpace, obstacles, robot := loadProblem(b, "hw3", 0)
+	checker := FootprintChecker{Obstacles: obstacles, Space: cSpace, Robot: robot, Resolution: 0.5}
+	m := StraightLine{Epsilon: 10}.Steer(prob.Start, Point{X: prob.Start.X + 10, Y: prob.Start.Y, Theta: prob.Start.Theta + 1})
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		checker.MotionValid(m)
+	}
+}
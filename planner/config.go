@@ -0,0 +1,260 @@
+package planner
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Circle defines a ball in 2D space.
+type Circle struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+	R float64 `json:"r"`
+}
+
+func (c Circle) String() string {
+	return fmt.Sprintf("(%.2f, %.2f, r=%.2f)", c.X, c.Y, c.R)
+}
+
+// Point is a point in 2D space with an optional direction angle, and the
+// linear and angular velocity used by robots with dynamics.
+type Point struct {
+	X     float64 `json:"x"`
+	Y     float64 `json:"y"`
+	Theta float64 `json:"theta"`
+	V     float64 `json:"v"` // linear velocity
+	W     float64 `json:"w"` // angular velocity
+}
+
+// Problem defines a specific path planning problem with a given config space.
+type Problem struct {
+	Name            string             `json:"name"`
+	Start           Point              `json:"start"`
+	Goal            Circle             `json:"goal_region"`
+	GoalHeading     *HeadingConstraint `json:"goal_heading,omitempty"`
+	GoalVelocity    *Bounds            `json:"goal_velocity,omitempty"`
+	GoalAngularRate *Bounds            `json:"goal_angular_rate,omitempty"`
+	GoalBias        float64            `json:"goal_bias"` // probability of sampling from the goal
+	Epsilon         float64            `json:"epsilon"`
+	Delta           float64            `json:"delta"`
+	AllowSmallSteps bool               `json:"allow_steps_smaller_than_epsilon"`
+}
+
+// HeadingConstraint requires the heading to be within Tolerance of Theta.
+type HeadingConstraint struct {
+	Theta     float64 `json:"theta"`
+	Tolerance float64 `json:"tolerance"`
+}
+
+func (h HeadingConstraint) String() string {
+	return fmt.Sprintf("(θ=%.2f±%.2f)", h.Theta, h.Tolerance)
+}
+
+// Bounds is a closed interval [Min, Max].
+type Bounds struct {
+	Min float64 `json:"min"`
+	Max float64 `json:"max"`
+}
+
+// Contains returns true if a is within the bounds.
+func (b Bounds) Contains(a float64) bool {
+	return b.Min <= a && a <= b.Max
+}
+
+func (b Bounds) String() string {
+	return fmt.Sprintf("[%.2f, %.2f]", b.Min, b.Max)
+}
+
+// ConfigSpace is the workspace bounds, together with the velocity and control
+// limits of robots with dynamics. The limits are left at zero for robots
+// without dynamics.
+type ConfigSpace struct {
+	XMin     float64 `json:"x_min"`
+	XMax     float64 `json:"x_max"`
+	YMin     float64 `json:"y_min"`
+	YMax     float64 `json:"y_max"`
+	VMax     float64 `json:"v_max"`
+	VMin     float64 `json:"v_min"`
+	WMax     float64 `json:"w_max"`
+	WMin     float64 `json:"w_min"`
+	AMax     float64 `json:"a_max"`
+	AMin     float64 `json:"a_min"`
+	GammaMax float64 `json:"gamma_max"`
+	GammaMin float64 `json:"gamma_min"`
+}
+
+// Contains returns true if (x, y) is strictly inside the workspace bounds.
+func (c ConfigSpace) Contains(x, y float64) bool {
+	return c.XMin < x && x < c.XMax && c.YMin < y && y < c.YMax
+}
+
+// Config is the go struct equivalent of the .json file describing the problems.
+type Config struct {
+	ObstaclesPath string      `json:"obstacles"`
+	RobotPath     string      `json:"robot_path"`
+	ConfigSpace   ConfigSpace `json:"config_space"`
+	Problems      []Problem   `json:"problems"`
+}
+
+// Robot is simply a set of points defining the edges of the robot, relative
+// to its center.
+type Robot []Point
+
+// ParseConfig decodes a problems.json file.
+func ParseConfig(reader io.Reader) (*Config, error) {
+	var c Config
+	if err := json.NewDecoder(reader).Decode(&c); err != nil {
+		return nil, errors.Wrap(err, "could not decode JSON")
+	}
+	return &c, nil
+}
+
+// ReadObstacles reads circular obstacles from csv rows of x, y and radius.
+func ReadObstacles(reader io.Reader) ([]Circle, error) {
+	r := csv.NewReader(reader)
+	r.TrimLeadingSpace = true
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read csv")
+	}
+
+	var obstacles []Circle
+	for i, v := range records {
+		if len(v) < 3 {
+			return nil, errors.Errorf("row %d: expected x, y and r, got %d values", i, len(v))
+		}
+		var values [3]float64
+		for j := range values {
+			f, err := strconv.ParseFloat(strings.TrimSpace(v[j]), 64)
+			if err != nil {
+				return nil, errors.Wrapf(err, "row %d: non-float value in csv", i)
+			}
+			values[j] = f
+		}
+		obstacles = append(obstacles, Circle{values[0], values[1], values[2]})
+	}
+
+	return obstacles, nil
+}
+
+// ReadPolygons reads polygonal obstacles from csv rows of the x and y of
+// their vertices. The C-obstacles of a polygon that is not convex are those
+// of its convex hull.
+func ReadPolygons(reader io.Reader) ([]Polygon, error) {
+	r := csv.NewReader(reader)
+	r.TrimLeadingSpace = true
+	r.FieldsPerRecord = -1
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read csv")
+	}
+
+	var polygons []Polygon
+	for i, v := range records {
+		if len(v) < 2 || len(v)%2 != 0 {
+			return nil, errors.Errorf("row %d: expected x and y of every vertex, got %d values", i, len(v))
+		}
+		polygon := make(Polygon, len(v)/2)
+		for j := range v {
+			f, err := strconv.ParseFloat(strings.TrimSpace(v[j]), 64)
+			if err != nil {
+				return nil, errors.Wrapf(err, "row %d: non-float value in csv", i)
+			}
+			if j%2 == 0 {
+				polygon[j/2].X = f
+			} else {
+				polygon[j/2].Y = f
+			}
+		}
+		polygons = append(polygons, polygon)
+	}
+
+	return polygons, nil
+}
+
+// ReadRobot reads the points of a robot footprint from csv rows of x and y.
+func ReadRobot(reader io.Reader) (Robot, error) {
+	r := csv.NewReader(reader)
+	r.TrimLeadingSpace = true
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read csv values")
+	}
+
+	var bot Robot
+	for i, v := range records {
+		if len(v) < 2 {
+			return nil, errors.Errorf("row %d: expected x and y, got %d values", i, len(v))
+		}
+		x, err := strconv.ParseFloat(strings.TrimSpace(v[0]), 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "row %d: non-float value in csv", i)
+		}
+		y, err := strconv.ParseFloat(strings.TrimSpace(v[1]), 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "row %d: non-float value in csv", i)
+		}
+		bot = append(bot, Point{X: x, Y: y})
+	}
+	return bot, nil
+}
+
+// LoadConfig reads the config file together with the obstacles and robot it
+// refers to. Relative obstacle and robot paths are resolved from the directory
+// of the config file. The robot is nil if the config has no robot path. If a
+// problem is infeasible, the config is returned together with a
+// FeasibilityError naming the problem and field.
+func LoadConfig(configPath string) (*Config, []Circle, Robot, error) {
+	configFile, err := os.Open(configPath)
+	if err != nil {
+		return nil, nil, nil, errors.Wrap(err, "could not open config file")
+	}
+	defer configFile.Close()
+	config, err := ParseConfig(configFile)
+	if err != nil {
+		return nil, nil, nil, errors.Wrap(err, "could not parse config file")
+	}
+	dir := filepath.Dir(configPath)
+
+	// Read in obstacles.
+	obstacleFile, err := os.Open(resolve(dir, config.ObstaclesPath))
+	if err != nil {
+		return nil, nil, nil, errors.Wrap(err, "could not open obstacle file")
+	}
+	defer obstacleFile.Close()
+	obstacles, err := ReadObstacles(obstacleFile)
+	if err != nil {
+		return nil, nil, nil, errors.Wrap(err, "could not read obstacles from file")
+	}
+
+	// Read in robot.
+	if config.RobotPath == "" {
+		return config, obstacles, nil, config.Check(obstacles, nil)
+	}
+	robotFile, err := os.Open(resolve(dir, config.RobotPath))
+	if err != nil {
+		return nil, nil, nil, errors.Wrap(err, "could not open robot file")
+	}
+	defer robotFile.Close()
+	robot, err := ReadRobot(robotFile)
+	if err != nil {
+		return nil, nil, nil, errors.Wrap(err, "could not read robot from file")
+	}
+
+	return config, obstacles, robot, config.Check(obstacles, robot)
+}
+
+func resolve(dir, path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(dir, path)
+}
@@ -0,0 +1,142 @@
+package planner
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/pkg/errors"
+)
+
+// Sphere defines a ball in 3D space.
+type Sphere struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+	Z float64 `json:"z"`
+	R float64 `json:"r"`
+}
+
+func (s Sphere) String() string {
+	return fmt.Sprintf("(%.2f, %.2f, %.2f, r=%.2f)", s.X, s.Y, s.Z, s.R)
+}
+
+// Center returns the center of the sphere.
+func (s Sphere) Center() Point3D {
+	return Point3D{X: s.X, Y: s.Y, Z: s.Z}
+}
+
+// Box is a box in 3D space given by its center and its size along each of its
+// axes. The box is axis-aligned unless it is oriented by the yaw, pitch and
+// roll angles in radians.
+type Box struct {
+	Center Point3D `json:"center"`
+	Size   Point3D `json:"size"`
+	Yaw    float64 `json:"yaw,omitempty"`   // about z
+	Pitch  float64 `json:"pitch,omitempty"` // then about the turned y axis
+	Roll   float64 `json:"roll,omitempty"`  // then about the turned x axis
+}
+
+// ConfigSpace3D is the workspace bounds of a robot in 3D space.
+type ConfigSpace3D struct {
+	XMin float64 `json:"x_min"`
+	XMax float64 `json:"x_max"`
+	YMin float64 `json:"y_min"`
+	YMax float64 `json:"y_max"`
+	ZMin float64 `json:"z_min"`
+	ZMax float64 `json:"z_max"`
+}
+
+// Contains returns true if p is strictly inside the workspace bounds.
+func (c ConfigSpace3D) Contains(p Point3D) bool {
+	return c.XMin < p.X && p.X < c.XMax && c.YMin < p.Y && p.Y < c.YMax && c.ZMin < p.Z && p.Z < c.ZMax
+}
+
+func (c ConfigSpace3D) volume() float64 {
+	return (c.XMax - c.XMin) * (c.YMax - c.YMin) * (c.ZMax - c.ZMin)
+}
+
+// Robot3D is the set of points of a robot footprint in 3D space, relative to
+// its center.
+type Robot3D []Point3D
+
+// Problem3D defines a path planning problem in 3D space.
+type Problem3D struct {
+	Name            string  `json:"name"`
+	Start           Point3D `json:"start"`
+	Goal            Sphere  `json:"goal_region"`
+	GoalBias        float64 `json:"goal_bias"` // probability of sampling from the goal
+	Epsilon         float64 `json:"epsilon"`
+	AllowSmallSteps bool    `json:"allow_steps_smaller_than_epsilon"`
+}
+
+// Config3D is the go struct equivalent of the .json file describing problems
+// in 3D space. The obstacles and the robot footprint are part of the file,
+// and a config without a robot plans for a point robot.
+type Config3D struct {
+	ConfigSpace ConfigSpace3D `json:"config_space"`
+	Spheres     []Sphere      `json:"spheres"`
+	Boxes       []Box         `json:"boxes"`
+	Robot       Robot3D       `json:"robot,omitempty"`
+	Problems    []Problem3D   `json:"problems"`
+}
+
+// Checker returns the collision checker of the obstacles and robot of the
+// config.
+func (c *Config3D) Checker() Checker3D {
+	return Checker3D{Spheres: c.Spheres, Boxes: c.Boxes, Space: c.ConfigSpace, Robot: c.Robot}
+}
+
+// ParseConfig3D decodes a 3D problems file.
+func ParseConfig3D(reader io.Reader) (*Config3D, error) {
+	var c Config3D
+	if err := json.NewDecoder(reader).Decode(&c); err != nil {
+		return nil, errors.Wrap(err, "could not decode JSON")
+	}
+	return &c, nil
+}
+
+// LoadConfig3D reads a 3D problems file. If a problem is infeasible, the
+// config is returned together with a FeasibilityError naming the problem and
+// field.
+func LoadConfig3D(configPath string) (*Config3D, error) {
+	f, err := os.Open(configPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not open config file")
+	}
+	defer f.Close()
+	config, err := ParseConfig3D(f)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not parse config file")
+	}
+	return config, config.Check()
+}
+
+// Check returns a FeasibilityError if any problem of the config is
+// infeasible: a start outside the config space or in collision, a goal
+// region centered outside the config space, or a step that is not positive.
+func (c *Config3D) Check() error {
+	checker := c.Checker()
+	var issues FeasibilityError
+	for i, prob := range c.Problems {
+		add := func(field, format string, args ...interface{}) {
+			issues = append(issues, Issue{Problem: i, Name: prob.Name, Field: field, Reason: fmt.Sprintf(format, args...)})
+		}
+		if !checker.Valid(prob.Start) {
+			add("start", "%v leaves the robot in collision or outside the config space", prob.Start)
+		}
+		if g := prob.Goal; !c.ConfigSpace.Contains(g.Center()) || g.R <= 0 {
+			add("goal_region", "%v is not a region inside the config space", g)
+		}
+		if prob.Epsilon <= 0 {
+			add("epsilon", "%.2f is not positive", prob.Epsilon)
+		}
+		if prob.GoalBias < 0 || prob.GoalBias > 1 {
+			add("goal_bias", "%.2f is not a probability", prob.GoalBias)
+		}
+	}
+	if len(issues) == 0 {
+		return nil
+	}
+	return issues
+}
@@ -0,0 +1,166 @@
+package planner
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func assert(tb testing.TB, condition bool, msg string, v ...interface{}) {
+	if !condition {
+		_, file, line, _ := runtime.Caller(1)
+		fmt.Printf("\033[31m%s:%d: "+msg+"\033[39m\n\n", append([]interface{}{filepath.Base(file), line}, v...)...)
+		tb.FailNow()
+	}
+}
+
+// ok fails the test if an err is not nil.
+func ok(tb testing.TB, err error) {
+	if err != nil {
+		_, file, line, _ := runtime.Caller(1)
+		fmt.Printf("\033[31m%s:%d: unexpected error: %s\033[39m\n\n", filepath.Base(file), line, err.Error())
+		tb.FailNow()
+	}
+}
+
+// equals fails the test if exp is not equal to act.
+func equals(tb testing.TB, exp, act interface{}) {
+	if !reflect.DeepEqual(exp, act) {
+		_, file, line, _ := runtime.Caller(1)
+		fmt.Printf("\033[31m%s:%d:\n\n\texp: %#v\n\n\tgot: %#v\033[39m\n\n", filepath.Base(file), line, exp, act)
+		tb.FailNow()
+	}
+}
+
+func TestParseConfig(t *testing.T) {
+	want := Config{
+		ObstaclesPath: "obstacles.txt",
+		RobotPath:     "H3_robot.txt",
+		ConfigSpace:   ConfigSpace{XMin: 0, XMax: 100.0, YMin: 0, YMax: 100.0, VMax: 5, VMin: -5},
+		Problems: []Problem{
+			Problem{
+				Name:    "custom test",
+				Start:   Point{X: 75.0, Y: 85.0, Theta: 3},
+				Goal:    Circle{100.0, 0.0, 20.0},
+				Epsilon: 10,
+			},
+		},
+	}
+
+	s := strings.NewReader(`{
+		"obstacles": "obstacles.txt",
+		"robot_path": "H3_robot.txt",
+		"config_space": {
+			"x_min": 0,
+			"x_max": 100,
+			"y_min": 0,
+			"y_max": 100,
+			"v_min": -5,
+			"v_max": 5
+		},
+
+		"problems": [{
+			"name": "custom test",
+			"start": {
+				"x": 75,
+				"y": 85,
+				"theta":3
+			},
+			"goal_region": {
+				"x": 100,
+				"y": 0,
+				"r": 20
+			},
+			"epsilon": 10
+		}]
+	}`)
+
+	c, err := ParseConfig(s)
+	ok(t, err)
+	equals(t, &want, c)
+	assert(t, len(c.Problems) == 1, "expected to have 1 problem")
+}
+
+func TestParseGoalConstraints(t *testing.T) {
+	s := strings.NewReader(`{
+		"problems": [{
+			"goal_region": {"x": 10, "y": 10, "r": 8},
+			"goal_heading": {"theta": -1.57, "tolerance": 0.2},
+			"goal_velocity": {"min": -0.5, "max": 0.5},
+			"goal_angular_rate": {"min": -0.1, "max": 0.2},
+			"goal_bias": 0.05
+		}]
+	}`)
+
+	c, err := ParseConfig(s)
+	ok(t, err)
+	p := c.Problems[0]
+	equals(t, &HeadingConstraint{Theta: -1.57, Tolerance: 0.2}, p.GoalHeading)
+	equals(t, &Bounds{Min: -0.5, Max: 0.5}, p.GoalVelocity)
+	equals(t, &Bounds{Min: -0.1, Max: 0.2}, p.GoalAngularRate)
+	equals(t, 0.05, p.GoalBias)
+
+	c, err = ParseConfig(strings.NewReader(`{"problems": [{"goal_region": {"x": 10, "y": 10, "r": 8}}]}`))
+	ok(t, err)
+	assert(t, c.Problems[0].GoalHeading == nil && c.Problems[0].GoalVelocity == nil, "constraints should be optional")
+}
+
+func TestReadObstacles(t *testing.T) {
+	obstacles, err := ReadObstacles(strings.NewReader("50,50,8\n55, 70, 8.5\n"))
+	ok(t, err)
+	equals(t, []Circle{{50, 50, 8}, {55, 70, 8.5}}, obstacles)
+
+	_, err = ReadObstacles(strings.NewReader("50,50,eight\n"))
+	assert(t, err != nil, "expected error for non-float radius")
+
+	_, err = ReadObstacles(strings.NewReader("50,50\n"))
+	assert(t, err != nil, "expected error for missing radius")
+}
+
+func TestReadPolygons(t *testing.T) {
+	polygons, err := ReadPolygons(strings.NewReader("8,4, 10,4, 10,6\n1, 2, 3, 4, 5, 6, 7, 8\n"))
+	ok(t, err)
+	equals(t, []Polygon{
+		{{X: 8, Y: 4}, {X: 10, Y: 4}, {X: 10, Y: 6}},
+		{{X: 1, Y: 2}, {X: 3, Y: 4}, {X: 5, Y: 6}, {X: 7, Y: 8}},
+	}, polygons)
+
+	_, err = ReadPolygons(strings.NewReader("8,4,10\n"))
+	assert(t, err != nil, "expected error for a vertex without y")
+
+	_, err = ReadPolygons(strings.NewReader("8,4,ten,4\n"))
+	assert(t, err != nil, "expected error for non-float coordinate")
+}
+
+func TestReadRobot(t *testing.T) {
+	var want Robot
+	want = []Point{
+		Point{X: 1, Y: 2},
+		Point{X: 3, Y: 4},
+		Point{X: -5.3, Y: 6},
+		Point{X: 7, Y: 8},
+	}
+
+	s := strings.NewReader(`1.0, 2.0
+		3.0, 4.0
+		-5.3, 6.0
+		7.0, 8.0`)
+	bot, err := ReadRobot(s)
+	ok(t, err)
+	equals(t, want, bot)
+}
+
+func TestLoadConfig(t *testing.T) {
+	for _, hw := range []string{"hw2", "hw3", "hw4"} {
+		t.Run(hw, func(t *testing.T) {
+			config, obstacles, robot, err := LoadConfig(filepath.Join("..", hw, "problems.json"))
+			ok(t, err)
+			assert(t, len(config.Problems) > 0, "expected problems")
+			assert(t, len(obstacles) > 0, "expected obstacles")
+			assert(t, (hw == "hw2") == (robot == nil), "only hw2 has no robot")
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package planner
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// FMTStarParams configures the FMT* planner.
+type FMTStarParams struct {
+	Samples       int // states sampled before planning
+	MaxIterations int // stop after expanding this many vertices if non-zero
+	SE2           SE2 // metric of a robot with a heading, the zero value plans in the plane
+}
+
+// FMTStar finds paths for geometric robots that move in straight lines, such
+// as the robots of hw2 and hw3, with the Fast Marching Tree algorithm. It
+// samples a batch of collision free states up front, and grows a tree over
+// them in order of increasing cost from the start, like Dijkstra's algorithm.
+// Every unvisited sample near the expanded vertex is connected to the
+// neighbor in the tree that gives it the shortest path, and only that motion
+// is checked for collisions, which keeps the number of checks low. The path
+// is the shortest through the samples as the number of samples grows.
+type FMTStar struct {
+	Problem Problem
+	Space   ConfigSpace
+	Checker CollisionChecker
+	Params  FMTStarParams
+	Rand    *rand.Rand
+}
+
+// NewFMTStar returns an FMT* planner for the problem.
+func NewFMTStar(prob Problem, cSpace ConfigSpace, checker CollisionChecker, seed int64, params FMTStarParams) *FMTStar {
+	return &FMTStar{
+		Problem: prob,
+		Space:   cSpace,
+		Checker: checker,
+		Params:  params,
+		Rand:    rand.New(rand.NewSource(seed)),
+	}
+}
+
+// Plan samples the states and grows the tree until it reaches the goal
+// region. It gives up when the tree cannot grow, ctx is done or the
+// iteration limit is reached.
+func (p *FMTStar) Plan(ctx context.Context) (*Solution, error) {
+	prob, params, rng := p.Problem, p.Params, p.Rand
+	goal := NewGoalRegion(prob, p.Space)
+	sampler := UniformSampler{p.Space}
+	connect := params.SE2.connector()
+	space := params.SE2
+
+	var stats Stats
+	checker := countingChecker{p.Checker, &stats}
+	started := time.Now()
+	var tree []*Motion
+	fail := func(reason string) (*Solution, error) {
+		stats.Elapsed = time.Since(started)
+		return noSolution(reason, stats, tree)
+	}
+	if !checker.Valid(prob.Start) {
+		return fail("start is in collision or outside the config space")
+	}
+
+	root := &Vertex{State: prob.Start}
+	vertices := []*Vertex{root}
+	unvisited := map[*Vertex]bool{}
+	for i := 0; i < params.Samples; i++ {
+		var u State
+		if rng.Float64() < prob.GoalBias {
+			u = goal.Sample(rng)
+		} else {
+			u = sampler.Sample(rng)
+		}
+		if checker.Valid(u) {
+			v := &Vertex{State: u, Cost: math.Inf(1)}
+			vertices = append(vertices, v)
+			unvisited[v] = true
+		}
+	}
+
+	radius := connectionRadius(p.Space, len(vertices), 0)
+	neighbors := map[*Vertex][]*Vertex{}
+	near := func(v *Vertex) []*Vertex {
+		if ns, ok := neighbors[v]; ok {
+			return ns
+		}
+		ns := []*Vertex{}
+		for _, w := range vertices {
+			if w != v && space.Distance(v.State, w.State) <= radius {
+				ns = append(ns, w)
+			}
+		}
+		neighbors[v] = ns
+		return ns
+	}
+
+	open := map[*Vertex]bool{root: true}
+	q := &queue{}
+	q.push(0, root, nil)
+	for i := 1; ; i++ {
+		if err := ctx.Err(); err != nil {
+			return fail(err.Error())
+		}
+		if params.MaxIterations > 0 && i > params.MaxIterations {
+			return fail("iteration limit reached")
+		}
+		if q.Len() == 0 {
+			return fail("the samples do not connect the start to the goal region")
+		}
+		stats.Iterations = i
+		// The root alone is not a path, see Solution.Path.
+		z := q.pop().from
+		if z != root && goal.Satisfied(z.State) {
+			stats.Elapsed = time.Since(started)
+			progress := []Progress{{Cost: math.Inf(1)}, {Iteration: i, Elapsed: stats.Elapsed, Cost: z.Cost}}
+			return &Solution{Path: backtrack(z), Tree: tree, Cost: z.Cost, Progress: progress, Stats: stats}, nil
+		}
+
+		// Samples are connected through their best neighbor in the open set
+		// only, and are left unvisited if that motion is not safe.
+		var added []*Vertex
+		for _, x := range near(z) {
+			if !unvisited[x] {
+				continue
+			}
+			var y *Vertex
+			for _, w := range near(x) {
+				if open[w] && (y == nil || w.Cost+space.Distance(w.State, x.State) < y.Cost+space.Distance(y.State, x.State)) {
+					y = w
+				}
+			}
+			m := connect.Steer(y.State, x.State)
+			if m == nil || !checker.MotionValid(m) {
+				continue
+			}
+			x.Parent, x.Motion, x.Cost = y, m, y.Cost+m.Cost
+			delete(unvisited, x)
+			added = append(added, x)
+			tree = append(tree, m)
+		}
+		for _, x := range added {
+			open[x] = true
+			q.push(x.Cost, x, nil)
+		}
+		delete(open, z)
+	}
+}
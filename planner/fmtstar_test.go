@@ -0,0 +1,36 @@
+package planner
+
+import (
+	"context"
+	"testing"
+)
+
+func TestFMTStar(t *testing.T) {
+	prob, cSpace, checker, shortest := aroundObstacle()
+	solution, err := NewFMTStar(prob, cSpace, checker, 1, FMTStarParams{Samples: 2000}).Plan(context.Background())
+	ok(t, err)
+	checkShortPath(t, "fmtstar", prob, checker, shortest, solution)
+
+	// The start is not a solution even if it is in the goal region.
+	prob.Goal = Circle{X: 5, Y: 5, R: 3}
+	solution, err = NewFMTStar(prob, cSpace, checker, 1, FMTStarParams{Samples: 200}).Plan(context.Background())
+	ok(t, err)
+	assert(t, len(solution.Path) > 0, "expected a path")
+}
+
+func TestFMTStarNoSolution(t *testing.T) {
+	cSpace := ConfigSpace{XMin: 0, XMax: 50, YMin: 0, YMax: 50}
+	prob := Problem{Start: Point{X: 5, Y: 5}, Goal: Circle{X: 45, Y: 45, R: 2}, Epsilon: 2, GoalBias: 0.05}
+	enclosed := PointChecker{Obstacles: []Circle{{X: 45, Y: 45, R: 4}}, Space: cSpace}
+
+	solution, err := NewFMTStar(prob, cSpace, enclosed, 1, FMTStarParams{Samples: 500}).Plan(context.Background())
+	noSol, isNoSol := err.(*NoSolutionError)
+	assert(t, isNoSol, "expected a *NoSolutionError, got %v", err)
+	assert(t, len(noSol.Tree) > 0, "expected the partial tree")
+	equals(t, 0, len(solution.Path))
+
+	solution, err = NewFMTStar(prob, cSpace, enclosed, 1, FMTStarParams{Samples: 500, MaxIterations: 10}).Plan(context.Background())
+	noSol, isNoSol = err.(*NoSolutionError)
+	assert(t, isNoSol, "expected a *NoSolutionError, got %v", err)
+	equals(t, 10, noSol.Iterations)
+}
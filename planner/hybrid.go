@@ -0,0 +1,425 @@
+package planner
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/hdhauk/enae788v/angle"
+	"github.com/hdhauk/enae788v/graph"
+)
+
+// HybridAStarParams configures the Hybrid A* planner.
+type HybridAStarParams struct {
+	Resolution    float64 // side of the grid cells in x and y
+	Headings      int     // number of heading cells
+	SpeedStep     float64 // change in linear velocity of a motion primitive
+	Duration      float64 // duration in seconds of a motion primitive
+	ShotEvery     int     // try to reach the goal directly every n expansions if non-zero
+	MaxIterations int     // stop after this many expansions if non-zero
+}
+
+// DefaultHybridAStarParams returns parameters that work for the problems in
+// hw4/problems.json.
+func DefaultHybridAStarParams() HybridAStarParams {
+	return HybridAStarParams{Resolution: 1, Headings: 72, SpeedStep: 1, Duration: 1, ShotEvery: 10}
+}
+
+// HybridAStar finds trajectories for the robot with dynamics of hw4 with the
+// Hybrid A* algorithm. It searches a grid of (x, y, θ) cells, one grid for
+// every speed, but keeps the continuous state reached in every cell, so the
+// trajectory is forward simulated with the Euler steps of Propagate all the
+// way. Unlike RRT it is deterministic, and the trajectories it finds are
+// close to the fastest the primitives allow.
+//
+// A vertex is expanded with motion primitives that change the linear
+// velocity by ±SpeedStep or not at all, and steer with a bang-bang angular
+// acceleration that turns the robot without changing its angular rate, so the
+// velocities at every vertex are on a grid as well. The heuristic is the
+// shortest time it takes to reach the goal region within the top speed of
+// the primitives and the acceleration limits, along the shortest path through
+// the grid cells that are not covered by an obstacle. Every ShotEvery expansions the planner tries to reach the goal
+// directly, by stopping, turning toward the goal center and driving straight
+// into the goal region. A valid shot ends the search once no vertex could
+// reach the goal region faster, which saves the search from steering the
+// primitives into a goal heading and velocity.
+type HybridAStar struct {
+	Problem   Problem
+	Space     ConfigSpace
+	Obstacles []Circle // for the heuristic
+	Checker   CollisionChecker
+	Params    HybridAStarParams
+}
+
+// NewHybridAStar returns a Hybrid A* planner for the problem.
+func NewHybridAStar(prob Problem, cSpace ConfigSpace, obstacles []Circle, checker CollisionChecker, params HybridAStarParams) *HybridAStar {
+	return &HybridAStar{Problem: prob, Space: cSpace, Obstacles: obstacles, Checker: checker, Params: params}
+}
+
+// hybridCell is the cell of a vertex in the search.
+type hybridCell struct {
+	x, y, heading, speed int
+}
+
+// Control is a constant acceleration A and steering acceleration Gamma
+// applied for a number of Timesteps.
+type Control struct {
+	A     float64 `json:"a"`
+	Gamma float64 `json:"gamma"`
+	Steps int     `json:"steps"`
+}
+
+// shotMargin is the fraction of the velocity and control limits that the
+// motions of the planner use, so that rounding in the simulation does not
+// break them.
+const shotMargin = 0.95
+
+// Plan expands the vertex with the lowest cost plus heuristic until it
+// reaches the goal region. It gives up when every reachable cell is
+// expanded, ctx is done or the iteration limit is reached.
+func (p *HybridAStar) Plan(ctx context.Context) (*Solution, error) {
+	prob, params := p.Problem, p.Params
+	goal := NewGoalRegion(prob, p.Space)
+
+	var stats Stats
+	checker := countingChecker{p.Checker, &stats}
+	started := time.Now()
+	var tree []*Motion
+	fail := func(reason string) (*Solution, error) {
+		stats.Elapsed = time.Since(started)
+		return noSolution(reason, stats, tree)
+	}
+	succeed := func(w *Vertex) (*Solution, error) {
+		stats.Elapsed = time.Since(started)
+		progress := []Progress{{Cost: math.Inf(1)}, {Iteration: stats.Iterations, Elapsed: stats.Elapsed, Cost: w.Cost}}
+		return &Solution{Path: backtrack(w), Tree: tree, Cost: w.Cost, Progress: progress, Stats: stats}, nil
+	}
+
+	root := &Vertex{State: &PathPoint{Point: prob.Start}}
+	if !checker.Valid(root.State) {
+		return fail("start is in collision or outside the config space")
+	}
+	h := p.heuristic()
+	primitives := p.primitives()
+	top := p.topSpeed()
+
+	best := map[hybridCell]*Vertex{p.cell(root.State): root}
+	closed := map[hybridCell]bool{}
+	var shot *Vertex // the fastest shot into the goal region so far
+	q := &queue{}
+	q.push(h(prob.Start), root, nil)
+	for q.Len() > 0 {
+		if err := ctx.Err(); err != nil {
+			return fail(err.Error())
+		}
+		if params.MaxIterations > 0 && stats.Iterations >= params.MaxIterations {
+			return fail("iteration limit reached")
+		}
+		v := q.pop().from
+		if v == shot {
+			return succeed(v)
+		}
+		c := p.cell(v.State)
+		if closed[c] || best[c] != v {
+			continue
+		}
+		closed[c] = true
+		stats.Iterations++
+
+		// The root alone is not a path, see Solution.Path.
+		if v != root && goal.Satisfied(v.State) {
+			return succeed(v)
+		}
+		// A shot is queued with its cost, and ends the search unless the
+		// primitives find a faster way first.
+		if params.ShotEvery > 0 && stats.Iterations%params.ShotEvery == 0 {
+			m := p.shot(v.State.(*PathPoint))
+			if m != nil && (shot == nil || v.Cost+m.Cost < shot.Cost) && goal.Satisfied(m.To) && checker.MotionValid(m) {
+				shot = &Vertex{State: m.To, Parent: v, Motion: m, Cost: v.Cost + m.Cost}
+				tree = append(tree, m)
+				q.push(shot.Cost, shot, nil)
+			}
+		}
+
+		for _, controls := range primitives {
+			m := follow(v.State.(*PathPoint), controls)
+			k := p.cell(m.To)
+			if closed[k] || math.Abs(Pose(m.To).V) > top+p.Params.SpeedStep/2 {
+				continue
+			}
+			cost := v.Cost + m.Cost
+			if w, ok := best[k]; ok && w.Cost <= cost {
+				continue
+			}
+			estimate := h(Pose(m.To))
+			if math.IsInf(estimate, 1) || !checker.MotionValid(m) {
+				continue
+			}
+			w := &Vertex{State: m.To, Parent: v, Motion: m, Cost: cost}
+			best[k] = w
+			tree = append(tree, m)
+			q.push(cost+estimate, w, nil)
+		}
+	}
+	return fail("every reachable cell was expanded")
+}
+
+// cell returns the grid cell of a state.
+func (p *HybridAStar) cell(u State) hybridCell {
+	s, res := Pose(u), p.Params.Resolution
+	return hybridCell{
+		x:       int(math.Floor((s.X - p.Space.XMin) / res)),
+		y:       int(math.Floor((s.Y - p.Space.YMin) / res)),
+		heading: int(angle.Normalize(s.Theta)/(2*math.Pi)*float64(p.Params.Headings)) % p.Params.Headings,
+		speed:   int(math.Round(s.V / p.Params.SpeedStep)),
+	}
+}
+
+// primitives returns the controls of the motion primitives. Every primitive
+// accelerates by -1, 0 or 1 SpeedStep per Duration, and steers with one of
+// five angular accelerations for the first half of it and the opposite for
+// the second half.
+func (p *HybridAStar) primitives() [][]Control {
+	half := int(math.Round(p.Params.Duration / 2 / Timestep))
+	a := p.Params.SpeedStep / (2 * float64(half) * Timestep)
+	gammaMax := math.Min(limit(p.Space.GammaMin, p.Space.GammaMax), limit(p.Space.WMin, p.Space.WMax)/(float64(half)*Timestep))
+	var primitives [][]Control
+	for _, accel := range []float64{-a, 0, a} {
+		for _, steer := range []float64{-1, -0.5, 0, 0.5, 1} {
+			gamma := steer * gammaMax
+			primitives = append(primitives, []Control{{accel, gamma, half}, {accel, -gamma, half}})
+		}
+	}
+	return primitives
+}
+
+// limit returns the largest magnitude within bounds min and max, with the
+// margin of shotMargin.
+func limit(min, max float64) float64 {
+	return shotMargin * math.Min(-min, max)
+}
+
+// follow forward simulates from p with the controls in turn.
+func follow(p *PathPoint, controls []Control) *Motion {
+	m := &Motion{From: p}
+	current := p
+	for _, c := range controls {
+		if c.Steps == 0 {
+			continue
+		}
+		step := Propagate(current, c.A, c.Gamma, float64(c.Steps)*Timestep)
+		m.Path = append(m.Path, step.Path...)
+		current = step.To.(*PathPoint)
+	}
+	if len(m.Path) == 0 {
+		return nil
+	}
+	m.To, m.Cost = current, current.T-p.T
+	return m
+}
+
+// shot returns a motion from p that stops, turns toward the goal center,
+// drives straight until it is halfway between the boundary of the goal region
+// and its center, and turns to the goal heading if there is one. It may
+// drive backward to turn less. It returns nil if a turn or the drive cannot
+// be done within the limits.
+func (p *HybridAStar) shot(from *PathPoint) *Motion {
+	cSpace, g := p.Space, p.Problem.Goal
+	vMax, aMax := limit(cSpace.VMin, cSpace.VMax), limit(cSpace.AMin, cSpace.AMax)
+	wMax, gammaMax := limit(cSpace.WMin, cSpace.WMax), limit(cSpace.GammaMin, cSpace.GammaMax)
+
+	// The pose after every part decides the next, so the parts are simulated
+	// one after the other.
+	current := from
+	var path []State
+	apply := func(controls ...Control) {
+		if m := follow(current, controls); m != nil {
+			path = append(path, m.Path...)
+			current = m.To.(*PathPoint)
+		}
+	}
+	move := func(distance, rateMax, accelMax float64, linear bool) bool {
+		accel, ramp, coast, ok := profile(distance, rateMax, accelMax)
+		if !ok {
+			return false
+		}
+		if linear {
+			apply(Control{accel, 0, ramp}, Control{0, 0, coast}, Control{-accel, 0, ramp})
+		} else {
+			apply(Control{0, accel, ramp}, Control{0, 0, coast}, Control{0, -accel, ramp})
+		}
+		return true
+	}
+
+	s := current.Point
+	stop := int(math.Ceil(math.Max(math.Abs(s.V)/(aMax*Timestep), math.Abs(s.W)/(gammaMax*Timestep))))
+	if stop > 0 {
+		apply(Control{-s.V / (float64(stop) * Timestep), -s.W / (float64(stop) * Timestep), stop})
+	}
+
+	s = current.Point
+	if d := math.Hypot(g.X-s.X, g.Y-s.Y); d > g.R/2 {
+		bearing := math.Atan2(g.Y-s.Y, g.X-s.X)
+		turn, distance := angle.Diff(s.Theta, bearing), d-g.R/2
+		if math.Abs(turn) > math.Pi/2 {
+			turn, distance = angle.Diff(s.Theta, bearing+math.Pi), -distance
+		}
+		if !move(turn, wMax, gammaMax, false) || !move(distance, vMax, aMax, true) {
+			return nil
+		}
+	}
+	if heading := p.Problem.GoalHeading; heading != nil {
+		if !move(angle.Diff(current.Theta, heading.Theta), wMax, gammaMax, false) {
+			return nil
+		}
+	}
+	if len(path) == 0 {
+		return nil
+	}
+	return &Motion{From: from, To: current, Path: path, Cost: current.T - from.T}
+}
+
+// maxProfileSteps bounds the duration of a profile, in Timesteps.
+const maxProfileSteps = 2000
+
+// profile returns the fastest way to move distance from and to rest with a
+// rate that ramps up with constant acceleration for ramp Timesteps, coasts
+// for coast Timesteps and ramps down again, without exceeding rateMax and
+// accelMax. It returns false if it takes longer than maxProfileSteps.
+func profile(distance, rateMax, accelMax float64) (accel float64, ramp, coast int, ok bool) {
+	if distance == 0 {
+		return 0, 0, 0, true
+	}
+	h := Timestep
+	for n := 2; n <= maxProfileSteps; n++ {
+		for ramp = 1; 2*ramp <= n; ramp++ {
+			coast = n - 2*ramp
+			// The Euler steps move with the rate before every step, which
+			// with unit acceleration gives a distance of h²·ramp·(ramp+coast).
+			accel = distance / (h * h * float64(ramp) * float64(ramp+coast))
+			if math.Abs(accel) <= accelMax && math.Abs(accel)*float64(ramp)*h <= rateMax {
+				return accel, ramp, coast, true
+			}
+		}
+	}
+	return 0, 0, 0, false
+}
+
+// topSpeed returns the largest multiple of SpeedStep strictly within the
+// velocity limits, which is the top speed of the vertices. Faster vertices
+// would be at the limits up to rounding.
+func (p *HybridAStar) topSpeed() float64 {
+	return topSpeed(p.Space, p.Params.SpeedStep)
+}
+
+// topSpeed returns the largest multiple of step strictly within the velocity
+// limits of cSpace, or the limit itself if step is larger.
+func topSpeed(cSpace ConfigSpace, step float64) float64 {
+	vMax := math.Min(-cSpace.VMin, cSpace.VMax)
+	if speed := math.Ceil(vMax/step)*step - step; speed > 0 {
+		return speed
+	}
+	return vMax
+}
+
+// heuristic returns the shortest time it takes to reach the goal region from
+// a state within the top speed of the primitives and the acceleration limits.
+func (p *HybridAStar) heuristic() func(Point) float64 {
+	return timeToGoal(p.Problem, p.Space, p.Obstacles, p.Params.Resolution, p.topSpeed())
+}
+
+// timeToGoal returns the shortest time it takes to reach the goal region from
+// a state within speed and the acceleration limits, along the shortest path
+// through the grid cells of side res that an obstacle does not cover
+// entirely. The time is infinite where no such path exists.
+func timeToGoal(prob Problem, cSpace ConfigSpace, obstacles []Circle, res, speed float64) func(Point) float64 {
+	g := prob.Goal
+	nx := int(math.Ceil((cSpace.XMax - cSpace.XMin) / res))
+	ny := int(math.Ceil((cSpace.YMax - cSpace.YMin) / res))
+	center := func(i, j int) (float64, float64) {
+		return cSpace.XMin + (float64(i)+0.5)*res, cSpace.YMin + (float64(j)+0.5)*res
+	}
+	covered := func(i, j int) bool {
+		x, y := center(i, j)
+		for _, o := range obstacles {
+			if math.Hypot(x-o.X, y-o.Y) < o.R-res/math.Sqrt2 {
+				return true
+			}
+		}
+		return false
+	}
+
+	// Dijkstra's algorithm from the cells in the goal region, with the
+	// queue of hw1.
+	cells := make([]*graph.Vertex, nx*ny)
+	q := graph.NewQueue()
+	for i := 0; i < nx; i++ {
+		for j := 0; j < ny; j++ {
+			if covered(i, j) {
+				continue
+			}
+			x, y := center(i, j)
+			v := &graph.Vertex{ID: i*ny + j, X: x, Y: y, CostToStart: math.Inf(1), Finite: true}
+			cells[v.ID] = v
+			if Near(Point{X: x, Y: y}, g) || math.Abs(x-g.X) <= res/2 && math.Abs(y-g.Y) <= res/2 {
+				v.CostToStart, v.Priority = 0, 0
+				q.PushVertex(v)
+			}
+		}
+	}
+	for v := q.PopVertex(); v != nil; v = q.PopVertex() {
+		i, j := v.ID/ny, v.ID%ny
+		for di := -1; di <= 1; di++ {
+			for dj := -1; dj <= 1; dj++ {
+				if i+di < 0 || i+di >= nx || j+dj < 0 || j+dj >= ny {
+					continue
+				}
+				u := cells[(i+di)*ny+j+dj]
+				if u == nil || u == v {
+					continue
+				}
+				if d := v.CostToStart + res*math.Hypot(float64(di), float64(dj)); d < u.CostToStart {
+					u.CostToStart, u.Priority, u.Parent = d, d, v
+					if q.InQueue(u) {
+						q.UpdateVertex(u)
+					} else {
+						q.PushVertex(u)
+					}
+				}
+			}
+		}
+	}
+
+	// The goal velocity bounds the speed at the end.
+	accel := limit(cSpace.AMin, cSpace.AMax) / shotMargin
+	final := speed
+	if b := prob.GoalVelocity; b != nil {
+		final = math.Min(speed, math.Max(math.Abs(b.Min), math.Abs(b.Max)))
+	}
+	return func(s Point) float64 {
+		i := int(math.Floor((s.X - cSpace.XMin) / res))
+		j := int(math.Floor((s.Y - cSpace.YMin) / res))
+		if i < 0 || i >= nx || j < 0 || j >= ny || cells[i*ny+j] == nil {
+			return math.Inf(1)
+		}
+		return minTime(cells[i*ny+j].CostToStart, math.Abs(s.V), final, speed, accel)
+	}
+}
+
+// minTime returns the shortest time it takes to move distance in a straight
+// line from speed v0 to at most speed vf, without exceeding speed vMax or
+// acceleration aMax. If the distance is too short to slow down to vf, it is
+// the time at vMax.
+func minTime(distance, v0, vf, vMax, aMax float64) float64 {
+	v0 = math.Min(v0, vMax)
+	// The peak speed if the robot speeds up and then slows down.
+	peak := math.Sqrt(aMax*distance + (v0*v0+vf*vf)/2)
+	switch {
+	case peak > vMax:
+		cruise := distance - (2*vMax*vMax-v0*v0-vf*vf)/(2*aMax)
+		return (2*vMax-v0-vf)/aMax + cruise/vMax
+	case peak >= v0 && peak >= vf:
+		return (2*peak - v0 - vf) / aMax
+	}
+	return distance / vMax
+}
@@ -0,0 +1,133 @@
+package planner
+
+import (
+	"context"
+	"math"
+	"path/filepath"
+	"testing"
+)
+
+// checkTrajectory fails the test unless the solution is a continuous, valid
+// trajectory from the start to a state that satisfies the goal, and its cost
+// is its duration.
+func checkTrajectory(t *testing.T, prob Problem, cSpace ConfigSpace, checker CollisionChecker, solution *Solution) {
+	prev := &PathPoint{Point: prob.Start}
+	for i, m := range solution.Path {
+		equals(t, prev, m.From.(*PathPoint))
+		for _, s := range m.Path {
+			p := s.(*PathPoint)
+			assert(t, math.Abs(p.T-prev.T-Timestep) < 1e-9, "motion %d: states should be one timestep apart", i)
+			assert(t, cSpace.AMin <= p.A && p.A <= cSpace.AMax && cSpace.GammaMin <= p.Gamma && p.Gamma <= cSpace.GammaMax, "motion %d: controls %f, %f out of limits", i, p.A, p.Gamma)
+			assert(t, checker.Valid(p), "motion %d: state %v should be valid", i, p)
+			prev = p
+		}
+		equals(t, prev, m.To.(*PathPoint))
+	}
+	assert(t, NewGoalRegion(prob, cSpace).Satisfied(prev), "trajectory should end in the goal, got %v", prev)
+	assert(t, math.Abs(solution.Cost-prev.T) < 1e-9, "cost %f should be the duration %f", solution.Cost, prev.T)
+}
+
+func TestHybridAStar(t *testing.T) {
+	config, obstacles, robot, err := LoadConfig(filepath.Join("..", "hw4", "problems.json"))
+	ok(t, err)
+	cSpace := config.ConfigSpace
+	checker := KinodynamicChecker{FootprintChecker{Obstacles: obstacles, Space: cSpace, Robot: robot}}
+	for _, i := range []int{0, 1} {
+		prob := config.Problems[i]
+		solution, err := NewHybridAStar(prob, cSpace, obstacles, checker, DefaultHybridAStarParams()).Plan(context.Background())
+		ok(t, err)
+		checkTrajectory(t, prob, cSpace, checker, solution)
+
+		// The search is deterministic.
+		again, err := NewHybridAStar(prob, cSpace, obstacles, checker, DefaultHybridAStarParams()).Plan(context.Background())
+		ok(t, err)
+		equals(t, solution.Cost, again.Cost)
+		equals(t, Trajectory(solution.Path), Trajectory(again.Path))
+	}
+}
+
+func TestHybridAStarDocking(t *testing.T) {
+	cSpace := ConfigSpace{
+		XMin: 0, XMax: 30,
+		YMin: 0, YMax: 30,
+		VMin: -5, VMax: 5,
+		WMin: -1.5, WMax: 1.5,
+		AMin: -2, AMax: 2,
+		GammaMin: -1.5, GammaMax: 1.5,
+	}
+	prob := Problem{
+		Start:           Point{X: 5, Y: 5},
+		Goal:            Circle{X: 20, Y: 20, R: 3},
+		GoalHeading:     &HeadingConstraint{Theta: math.Pi, Tolerance: 0.1},
+		GoalVelocity:    &Bounds{Min: -0.2, Max: 0.2},
+		GoalAngularRate: &Bounds{Min: -0.1, Max: 0.1},
+	}
+	obstacles := []Circle{{X: 12, Y: 12, R: 3}}
+	checker := KinodynamicChecker{FootprintChecker{Obstacles: obstacles, Space: cSpace, Robot: Robot{{X: -1}, {}, {X: 1}}}}
+
+	// The goal heading and velocity are hard to reach with the primitives
+	// alone, and a shot reaches them faster.
+	costs := map[int]float64{}
+	for _, shotEvery := range []int{0, 1} {
+		params := DefaultHybridAStarParams()
+		params.ShotEvery = shotEvery
+		solution, err := NewHybridAStar(prob, cSpace, obstacles, checker, params).Plan(context.Background())
+		ok(t, err)
+		checkTrajectory(t, prob, cSpace, checker, solution)
+		costs[shotEvery] = solution.Cost
+	}
+	assert(t, costs[1] < costs[0], "shots should shorten the trajectory of %fs, got %fs", costs[0], costs[1])
+}
+
+func TestHybridAStarNoSolution(t *testing.T) {
+	cSpace := ConfigSpace{XMin: 0, XMax: 30, YMin: 0, YMax: 30, VMin: -5, VMax: 5, WMin: -1.5, WMax: 1.5, AMin: -2, AMax: 2, GammaMin: -1.5, GammaMax: 1.5}
+	prob := Problem{Start: Point{X: 5, Y: 5}, Goal: Circle{X: 20, Y: 20, R: 2}}
+	obstacles := []Circle{{X: 20, Y: 20, R: 4}}
+	checker := KinodynamicChecker{FootprintChecker{Obstacles: obstacles, Space: cSpace, Robot: Robot{{}}}}
+
+	solution, err := NewHybridAStar(prob, cSpace, obstacles, checker, DefaultHybridAStarParams()).Plan(context.Background())
+	_, isNoSol := err.(*NoSolutionError)
+	assert(t, isNoSol, "expected a *NoSolutionError, got %v", err)
+	assert(t, solution == nil || len(solution.Path) == 0, "expected no path")
+
+	params := DefaultHybridAStarParams()
+	params.MaxIterations = 5
+	prob.Goal.X, prob.Goal.Y = 25, 25
+	_, err = NewHybridAStar(prob, cSpace, nil, checker, params).Plan(context.Background())
+	noSol, isNoSol := err.(*NoSolutionError)
+	assert(t, isNoSol, "expected a *NoSolutionError, got %v", err)
+	equals(t, 5, noSol.Iterations)
+}
+
+func TestProfile(t *testing.T) {
+	for _, distance := range []float64{-3, 0.05, 1, 40} {
+		accel, ramp, coast, found := profile(distance, 4, 1.5)
+		assert(t, found, "no profile for %f", distance)
+		m := follow(&PathPoint{}, []Control{{accel, 0, ramp}, {0, 0, coast}, {-accel, 0, ramp}})
+		end := Pose(m.To)
+		assert(t, math.Abs(end.X-distance) < 1e-9, "profile moved %f, expected %f", end.X, distance)
+		assert(t, math.Abs(end.V) < 1e-9, "profile should end at rest, got v=%f", end.V)
+		for _, s := range m.Path {
+			assert(t, math.Abs(Pose(s).V) <= 4+1e-9 && math.Abs(accel) <= 1.5, "profile exceeds the limits")
+		}
+	}
+	_, _, _, found := profile(1e6, 4, 1.5)
+	assert(t, !found, "the profile should be too long")
+}
+
+func TestMinTime(t *testing.T) {
+	cases := []struct {
+		distance, v0, vf, expected float64
+	}{
+		{0, 0, 4, 0},
+		{8, 4, 4, 2},              // cruise
+		{4, 0, 4, 2},              // speed up all the way
+		{4, 0, 0, 2 * math.Sqrt2}, // speed up and slow down
+		{16, 0, 0, 6},             // with 2s of cruise
+		{0.5, 4, 0, 0.125},        // too short to stop
+	}
+	for _, c := range cases {
+		got := minTime(c.distance, c.v0, c.vf, 4, 2)
+		assert(t, math.Abs(got-c.expected) < 1e-9, "minTime(%v, %v, %v) = %f, expected %f", c.distance, c.v0, c.vf, got, c.expected)
+	}
+}
@@ -0,0 +1,73 @@
+package planner
+
+import (
+	"math"
+	"math/rand"
+)
+
+// connectionRadius returns the radius within which vertices are connected
+// to a new vertex in a graph of n vertices, so that the shortest path in the
+// graph converges to the shortest path as n grows. It shrinks with n from a
+// constant that depends on the area of the config space, and is at most
+// epsilon if epsilon is positive.
+func connectionRadius(cSpace ConfigSpace, n int, epsilon float64) float64 {
+	area := (cSpace.XMax - cSpace.XMin) * (cSpace.YMax - cSpace.YMin)
+	gamma := 2 * math.Sqrt(1.5*area/math.Pi)
+	r := gamma * math.Sqrt(math.Log(float64(n))/float64(n))
+	if epsilon > 0 {
+		r = math.Min(r, epsilon)
+	}
+	return r
+}
+
+// costToGo is a lower bound on the length of a path from s to the goal
+// region of prob: the straight line distance to the region.
+func costToGo(prob Problem, s State) float64 {
+	q, g := Pose(s), prob.Goal
+	return math.Max(0, math.Hypot(q.X-g.X, q.Y-g.Y)-g.R)
+}
+
+// lowerBound is a lower bound on the length of a path from the start to the
+// goal region of prob through s: the straight line distance from the start
+// to s and from s to the goal region.
+func lowerBound(prob Problem, s State) float64 {
+	q, start := Pose(s), prob.Start
+	return math.Hypot(q.X-start.X, q.Y-start.Y) + costToGo(prob, s)
+}
+
+// sampleInformed returns a state uniformly distributed in the part of the
+// config space through which a path could be shorter than cost. That is an
+// ellipse with the start and the goal center as foci, where the sum of the
+// distances to them is at most cost plus the goal radius. Samples are drawn
+// from whichever of the ellipse and the config space is smaller, until one
+// lies in both.
+func sampleInformed(prob Problem, cSpace ConfigSpace, rng *rand.Rand, cost float64) State {
+	start, g, c := prob.Start, prob.Goal, cSpace
+	focal := math.Hypot(g.X-start.X, g.Y-start.Y)
+	a := (cost + g.R) / 2
+	b := math.Sqrt(math.Max(0, a*a-focal*focal/4))
+	if math.IsInf(cost, 1) || math.Pi*a*b > (c.XMax-c.XMin)*(c.YMax-c.YMin) {
+		sampler := UniformSampler{c}
+		for {
+			u := sampler.Sample(rng)
+			if q := Pose(u); math.Hypot(q.X-start.X, q.Y-start.Y)+math.Hypot(q.X-g.X, q.Y-g.Y) <= 2*a {
+				return u
+			}
+		}
+	}
+
+	sin, cos := math.Sincos(math.Atan2(g.Y-start.Y, g.X-start.X))
+	for {
+		r := math.Sqrt(rng.Float64())
+		phi := rng.Float64() * 2 * math.Pi
+		x, y := a*r*math.Cos(phi), b*r*math.Sin(phi)
+		u := Point{
+			X:     (start.X+g.X)/2 + x*cos - y*sin,
+			Y:     (start.Y+g.Y)/2 + x*sin + y*cos,
+			Theta: rng.Float64() * 2 * math.Pi,
+		}
+		if c.XMin <= u.X && u.X <= c.XMax && c.YMin <= u.Y && u.Y <= c.YMax {
+			return u
+		}
+	}
+}
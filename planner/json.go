@@ -0,0 +1,278 @@
+package planner
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Result is a planning run, as written by WriteJSON.
+type Result struct {
+	Problem    Problem
+	Problem3D  *Problem3D  // the problem of a run in 3D space, written instead of Problem if non-nil
+	ArmProblem *ArmProblem // the problem of a run of an arm, written instead of Problem if non-nil
+	Planner    string      // name of the planner, such as "rrt" or "sst"
+	Seed       int64
+	Solution   *Solution
+	Err        error // error returned by the planner, nil if it found a path
+}
+
+type jsonResult struct {
+	Problem  interface{}    `json:"problem"`
+	Planner  string         `json:"planner"`
+	Seed     int64          `json:"seed"`
+	Solved   bool           `json:"solved"`
+	Reason   string         `json:"reason,omitempty"`
+	Cost     *float64       `json:"cost"`
+	Path     []State        `json:"path"`
+	Tree     []jsonVertex   `json:"tree"`
+	Progress []jsonProgress `json:"progress,omitempty"`
+	Stats    jsonStats      `json:"stats"`
+}
+
+type jsonVertex struct {
+	ID     int     `json:"id"`
+	Parent int     `json:"parent"`
+	State  State   `json:"state"`
+	Cost   float64 `json:"cost"`
+	Path   []State `json:"path,omitempty"`
+}
+
+type jsonProgress struct {
+	Iteration int     `json:"iteration"`
+	Elapsed   float64 `json:"elapsed_seconds"`
+	Cost      float64 `json:"cost"`
+}
+
+type jsonStats struct {
+	Iterations   int     `json:"iterations"`
+	Elapsed      float64 `json:"elapsed_seconds"`
+	StateChecks  int     `json:"state_checks"`
+	MotionChecks int     `json:"motion_checks"`
+	Vertices     int     `json:"vertices"`
+}
+
+// WriteJSON writes a planning run as a JSON object with the fields
+//
+//	problem   the problem, in the format of problems.json
+//	planner   name of the planner
+//	seed      seed of the random sampling
+//	solved    true if a path to the goal was found
+//	reason    why no path was found, omitted if solved
+//	cost      cost of the path, or null if not solved
+//	path      states from the start to the goal, including the intermediate
+//	          states of simulated motions
+//	tree      vertices of the tree, each after its parent and with an "id", the
+//	          "parent" id (-1 for roots), its "state", the "cost" of the
+//	          motion from its parent, and the intermediate states of that
+//	          motion in "path" unless it is a straight line
+//	progress  "iteration", "elapsed_seconds" and "cost" of every iteration
+//	          that improved the best cost, omitted for planners that stop at
+//	          the first solution
+//	stats     "iterations", "elapsed_seconds", "state_checks" and
+//	          "motion_checks" made by the planner, and the number of
+//	          "vertices" in the tree
+//
+// States have the fields "x", "y", "theta", "v" and "w", and the states of
+// kinodynamic planners also the controls "a" and "gamma" applied to reach
+// them and the time "t" they are reached. The states of 3D planners have the
+// fields "x", "y" and "z", and those of arm planners are arrays of joint
+// angles.
+func WriteJSON(w io.Writer, r Result) error {
+	sol := r.Solution
+	if sol == nil {
+		sol = &Solution{Cost: math.Inf(1)}
+	}
+	out := jsonResult{
+		Problem: r.Problem,
+		Planner: r.Planner,
+		Seed:    r.Seed,
+		Solved:  r.Err == nil && len(sol.Path) > 0,
+		Path:    PathStates(sol.Path),
+		Tree:    jsonTree(sol.Tree),
+		Stats: jsonStats{
+			Iterations:   sol.Stats.Iterations,
+			Elapsed:      sol.Stats.Elapsed.Seconds(),
+			StateChecks:  sol.Stats.StateChecks,
+			MotionChecks: sol.Stats.MotionChecks,
+		},
+	}
+	out.Stats.Vertices = len(out.Tree)
+	if r.Problem3D != nil {
+		out.Problem = r.Problem3D
+	}
+	if r.ArmProblem != nil {
+		out.Problem = r.ArmProblem
+	}
+	if out.Solved {
+		cost := sol.Cost
+		out.Cost = &cost
+	} else if r.Err != nil {
+		out.Reason = r.Err.Error()
+	}
+	if out.Path == nil {
+		out.Path = []State{}
+	}
+
+	best := math.Inf(1)
+	for _, p := range sol.Progress {
+		if p.Cost < best {
+			out.Progress = append(out.Progress, jsonProgress{Iteration: p.Iteration, Elapsed: p.Elapsed.Seconds(), Cost: p.Cost})
+			best = p.Cost
+		}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return errors.Wrap(enc.Encode(out), "could not write result")
+}
+
+// jsonTree numbers the vertices of a tree given by its motions, every vertex
+// after its parent. Every motion ends in a vertex of its own, even where
+// several end in equal states, and leaves from the first vertex numbered with
+// its start state.
+func jsonTree(tree []*Motion) []jsonVertex {
+	leaving := map[interface{}][]*Motion{}
+	isTo := map[interface{}]bool{}
+	for _, m := range tree {
+		from := stateKey(m.From)
+		leaving[from] = append(leaving[from], m)
+		isTo[stateKey(m.To)] = true
+	}
+
+	vertices := []jsonVertex{}
+	numbered := 0
+	// grow numbers the ends of the motions leaving the vertices after the
+	// numbered ones, breadth first.
+	grow := func() {
+		for ; numbered < len(vertices); numbered++ {
+			parent := vertices[numbered]
+			key := stateKey(parent.State)
+			for _, m := range leaving[key] {
+				v := jsonVertex{ID: len(vertices), Parent: parent.ID, State: m.To, Cost: m.Cost}
+				if len(m.Path) > 0 {
+					v.Path = m.Path
+				}
+				vertices = append(vertices, v)
+			}
+			delete(leaving, key)
+		}
+	}
+	rooted := map[interface{}]bool{}
+	for _, m := range tree {
+		if from := stateKey(m.From); !isTo[from] && !rooted[from] {
+			rooted[from] = true
+			vertices = append(vertices, jsonVertex{ID: len(vertices), Parent: -1, State: m.From})
+		}
+	}
+	grow()
+
+	// Motions that are not reached from a root, such as those leaving a state
+	// equal to the end of a motion back to the start, get roots of their own.
+	for _, m := range tree {
+		if _, left := leaving[stateKey(m.From)]; left {
+			vertices = append(vertices, jsonVertex{ID: len(vertices), Parent: -1, State: m.From})
+			grow()
+		}
+	}
+	return vertices
+}
+
+// stateKey returns a map key for s: s itself, or its printed value if the
+// type of s is not comparable, such as Joints. States held by pointer, such
+// as *PathPoint, are keyed by identity.
+func stateKey(s State) interface{} {
+	if s != nil && !reflect.TypeOf(s).Comparable() {
+		return fmt.Sprint(s)
+	}
+	return s
+}
+
+// ReadJSON reads a planning run in the plane written by WriteJSON. States are
+// read as *PathPoint whatever planner wrote them, and the path is rebuilt
+// from the motions of the tree that lead to its last state.
+func ReadJSON(r io.Reader) (Result, error) {
+	var in struct {
+		Problem  Problem
+		Planner  string
+		Seed     int64
+		Solved   bool
+		Reason   string
+		Cost     *float64
+		Path     []*PathPoint
+		Progress []jsonProgress
+		Stats    jsonStats
+		Tree     []struct {
+			ID, Parent int
+			State      *PathPoint
+			Cost       float64
+			Path       []*PathPoint
+		}
+	}
+	if err := json.NewDecoder(r).Decode(&in); err != nil {
+		return Result{}, errors.Wrap(err, "could not decode JSON")
+	}
+
+	sol := &Solution{
+		Cost: math.Inf(1),
+		Stats: Stats{
+			Iterations:   in.Stats.Iterations,
+			Elapsed:      time.Duration(in.Stats.Elapsed * float64(time.Second)),
+			StateChecks:  in.Stats.StateChecks,
+			MotionChecks: in.Stats.MotionChecks,
+		},
+	}
+	if in.Cost != nil {
+		sol.Cost = *in.Cost
+	}
+	for _, p := range in.Progress {
+		sol.Progress = append(sol.Progress, Progress{Iteration: p.Iteration, Elapsed: time.Duration(p.Elapsed * float64(time.Second)), Cost: p.Cost})
+	}
+
+	vertices := map[int]*Vertex{}
+	for _, v := range in.Tree {
+		vertices[v.ID] = &Vertex{State: v.State}
+	}
+	for _, v := range in.Tree {
+		if v.Parent < 0 {
+			continue
+		}
+		parent, ok := vertices[v.Parent]
+		if !ok {
+			return Result{}, errors.Errorf("vertex %d has unknown parent %d", v.ID, v.Parent)
+		}
+		m := &Motion{From: parent.State, To: v.State, Cost: v.Cost}
+		for _, s := range v.Path {
+			m.Path = append(m.Path, s)
+		}
+		vertices[v.ID].Parent, vertices[v.ID].Motion = parent, m
+		sol.Tree = append(sol.Tree, m)
+	}
+
+	if len(in.Path) > 0 {
+		last := *in.Path[len(in.Path)-1]
+		for _, v := range in.Tree {
+			if *v.State == last {
+				sol.Path = backtrack(vertices[v.ID])
+				break
+			}
+		}
+		// Fall back to straight lines between the states of the path.
+		if sol.Path == nil {
+			for i := 1; i < len(in.Path); i++ {
+				sol.Path = append(sol.Path, &Motion{From: in.Path[i-1], To: in.Path[i]})
+			}
+		}
+	}
+
+	result := Result{Problem: in.Problem, Planner: in.Planner, Seed: in.Seed, Solution: sol}
+	if !in.Solved {
+		result.Err = errors.New(in.Reason)
+	}
+	return result, nil
+}
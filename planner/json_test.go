@@ -0,0 +1,173 @@
+package planner
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWriteJSON(t *testing.T) {
+	a, b, c := Point{X: 1, Y: 1}, Point{X: 2, Y: 1}, Point{X: 2, Y: 3}
+	first := &Motion{From: a, To: b, Cost: 1}
+	second := &Motion{From: b, To: c, Cost: 2}
+	sol := &Solution{
+		Path:  []*Motion{first, second},
+		Tree:  []*Motion{first, second},
+		Cost:  3,
+		Stats: Stats{Iterations: 7, Elapsed: time.Second, StateChecks: 1, MotionChecks: 6},
+	}
+	prob := Problem{Name: "test", Start: a, Goal: Circle{X: 2, Y: 3, R: 1}, Epsilon: 2}
+
+	var buf bytes.Buffer
+	ok(t, WriteJSON(&buf, Result{Problem: prob, Planner: "rrt", Seed: 5, Solution: sol}))
+
+	var out struct {
+		Problem Problem
+		Planner string
+		Seed    int64
+		Solved  bool
+		Cost    *float64
+		Path    []Point
+		Tree    []struct {
+			ID, Parent int
+			State      Point
+			Cost       float64
+		}
+		Stats map[string]float64
+	}
+	ok(t, json.Unmarshal(buf.Bytes(), &out))
+	equals(t, prob, out.Problem)
+	equals(t, "rrt", out.Planner)
+	equals(t, int64(5), out.Seed)
+	assert(t, out.Solved, "expected solved")
+	equals(t, 3.0, *out.Cost)
+	equals(t, []Point{a, b, c}, out.Path)
+	equals(t, 3, len(out.Tree))
+	equals(t, -1, out.Tree[0].Parent)
+	equals(t, a, out.Tree[0].State)
+	equals(t, 1, out.Tree[2].Parent)
+	equals(t, c, out.Tree[2].State)
+	equals(t, 2.0, out.Tree[2].Cost)
+	equals(t, map[string]float64{"iterations": 7, "elapsed_seconds": 1, "state_checks": 1, "motion_checks": 6, "vertices": 3}, out.Stats)
+}
+
+func TestJSONTree(t *testing.T) {
+	a, b, c := Point{X: 1, Y: 1}, Point{X: 2, Y: 1}, Point{X: 2, Y: 3}
+	tests := []struct {
+		name    string
+		tree    []*Motion
+		parents []int
+		states  []State
+	}{
+		{
+			"equal ends",
+			[]*Motion{{From: a, To: b}, {From: a, To: b}, {From: b, To: c}},
+			[]int{-1, 0, 0, 1},
+			[]State{a, b, b, c},
+		},
+		{
+			"back to the start",
+			[]*Motion{{From: a, To: b}, {From: b, To: a}, {From: a, To: c}},
+			[]int{-1, 0, 0, 1},
+			[]State{a, b, c, a},
+		},
+		{
+			"joints",
+			[]*Motion{{From: Joints{0, 0}, To: Joints{0, 1}}, {From: Joints{0, 1}, To: Joints{1, 1}}},
+			[]int{-1, 0, 1},
+			[]State{Joints{0, 0}, Joints{0, 1}, Joints{1, 1}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vertices := jsonTree(tt.tree)
+			var parents []int
+			var states []State
+			for i, v := range vertices {
+				equals(t, i, v.ID)
+				parents = append(parents, v.Parent)
+				states = append(states, v.State)
+			}
+			equals(t, tt.parents, parents)
+			equals(t, tt.states, states)
+		})
+	}
+}
+
+func TestWriteJSONArm(t *testing.T) {
+	config, obstacles, err := LoadArmConfig(filepath.Join("..", "arm", "problems.json"))
+	ok(t, err)
+	prob := config.Problems[0]
+	checker := ArmChecker{Arm: config.Arm, Obstacles: obstacles, Space: config.ConfigSpace, Resolution: 0.5}
+	sol, err := NewArmRRT(prob, config.Arm, checker, 1).Plan(context.Background())
+	ok(t, err)
+
+	var buf bytes.Buffer
+	ok(t, WriteJSON(&buf, Result{ArmProblem: &prob, Planner: "rrt", Seed: 1, Solution: sol}))
+	var out struct {
+		Problem ArmProblem
+		Solved  bool
+		Path    []Joints
+		Tree    []struct {
+			ID, Parent int
+			State      Joints
+		}
+	}
+	ok(t, json.Unmarshal(buf.Bytes(), &out))
+	equals(t, prob, out.Problem)
+	assert(t, out.Solved, "expected solved")
+	equals(t, len(PathStates(sol.Path)), len(out.Path))
+	equals(t, prob.Start, out.Path[0])
+
+	// Every motion of the tree ends in a vertex of its own, after its parent.
+	equals(t, len(sol.Tree)+1, len(out.Tree))
+	equals(t, -1, out.Tree[0].Parent)
+	equals(t, prob.Start, out.Tree[0].State)
+	for i, v := range out.Tree[1:] {
+		assert(t, v.Parent >= 0 && v.Parent <= i, "vertex %d has parent %d", v.ID, v.Parent)
+	}
+}
+
+func TestWriteJSONNoSolution(t *testing.T) {
+	sol, err := noSolution("iteration limit reached", Stats{Iterations: 3}, nil)
+
+	var buf bytes.Buffer
+	ok(t, WriteJSON(&buf, Result{Planner: "rrt", Solution: sol, Err: err}))
+
+	var out map[string]interface{}
+	ok(t, json.Unmarshal(buf.Bytes(), &out))
+	equals(t, false, out["solved"])
+	equals(t, nil, out["cost"])
+	equals(t, err.Error(), out["reason"])
+	equals(t, []interface{}{}, out["path"])
+}
+
+func TestReadJSON(t *testing.T) {
+	cSpace := ConfigSpace{
+		XMin: 0, XMax: 30,
+		YMin: 0, YMax: 30,
+		VMin: -5, VMax: 5,
+		WMin: -1.5, WMax: 1.5,
+		AMin: -2, AMax: 2,
+		GammaMin: -1.5, GammaMax: 1.5,
+	}
+	prob := Problem{Name: "test", Start: Point{X: 5, Y: 5}, Goal: Circle{X: 20, Y: 20, R: 5}, Epsilon: 5, GoalBias: 0.05}
+	checker := KinodynamicChecker{FootprintChecker{Space: cSpace, Robot: Robot{{}}}}
+	sol, err := NewKinodynamicRRT(prob, cSpace, checker, 1).Plan(context.Background())
+	ok(t, err)
+
+	var buf bytes.Buffer
+	ok(t, WriteJSON(&buf, Result{Problem: prob, Planner: "rrt", Seed: 1, Solution: sol}))
+	r, err := ReadJSON(&buf)
+	ok(t, err)
+	ok(t, r.Err)
+	equals(t, prob, r.Problem)
+	equals(t, sol.Cost, r.Solution.Cost)
+	equals(t, sol.Stats.Iterations, r.Solution.Stats.Iterations)
+	equals(t, len(sol.Tree), len(r.Solution.Tree))
+	equals(t, len(sol.Path), len(r.Solution.Path))
+	equals(t, Trajectory(sol.Path), Trajectory(r.Solution.Path))
+}
@@ -0,0 +1,104 @@
+package planner
+
+import (
+	"fmt"
+	"math"
+)
+
+// Timestep is the step length in seconds used when forward simulating the
+// dynamics.
+var Timestep = 0.1
+
+// PathPoint is a single state along a simulated trajectory, together with the
+// controls A and Gamma that were applied to reach it and the time T at which
+// it is reached.
+type PathPoint struct {
+	Point
+	A     float64 `json:"a"`
+	Gamma float64 `json:"gamma"`
+	T     float64 `json:"t"`
+}
+
+func (p *PathPoint) String() string {
+	return fmt.Sprintf("(x:%.2f, y:%.2f, θ:%.2f, v:%.2f, w:%.2f, t:%.2f)", p.X, p.Y, p.Theta, p.V, p.W, p.T)
+}
+
+// Kinodynamic steers a unicycle with acceleration a and steering acceleration
+// γ as controls, by forward simulating constant controls chosen to match the
+// velocities of the target after traveling roughly Epsilon.
+type Kinodynamic struct {
+	Space   ConfigSpace
+	Epsilon float64
+}
+
+// Steer forward simulates from the *PathPoint from toward the velocities of
+// toward, using a ½-car like model.
+func (k Kinodynamic) Steer(from, toward State) *Motion {
+	v, u := from.(*PathPoint), Pose(toward)
+
+	changeInLinVelocity := u.V - v.V
+	changeInAngVelocity := u.W - v.W
+	avgSpeed := v.V + changeInLinVelocity/2
+	timeToTravelEpsilon := clamp(k.Epsilon/avgSpeed, 1, 10)
+
+	// determine acceleration
+	a := clamp(changeInLinVelocity/timeToTravelEpsilon, k.Space.AMin, k.Space.AMax)
+	gamma := clamp(changeInAngVelocity/timeToTravelEpsilon, k.Space.GammaMin, k.Space.GammaMax)
+
+	return Propagate(v, a, gamma, timeToTravelEpsilon)
+}
+
+// Propagate forward simulates from p while applying the controls a and gamma
+// for the given duration, in steps of Timestep. The cost of the motion is its
+// duration.
+func Propagate(p *PathPoint, a, gamma, duration float64) *Motion {
+	h := Timestep
+	X := p.Point
+	t := p.T
+	var path []State
+	var next PathPoint
+	for i := 0.0; i*h < duration; i++ {
+		next = Euler(X, h, a, gamma)
+		t += h
+		next.T = t
+		step := next
+		path = append(path, &step)
+		X = next.Point
+	}
+	if len(path) == 0 {
+		return nil
+	}
+
+	return &Motion{From: p, To: path[len(path)-1], Path: path, Cost: t - p.T}
+}
+
+// Euler takes a single euler step of length h from X with the controls a and
+// gamma.
+func Euler(X Point, h, a, gamma float64) PathPoint {
+	x := X.X + h*(X.V*math.Cos(X.Theta))
+	y := X.Y + h*(X.V*math.Sin(X.Theta))
+	theta := X.Theta + h*X.W
+	v := X.V + h*a
+	w := X.W + h*gamma
+	return PathPoint{Point: Point{X: x, Y: y, Theta: theta, V: v, W: w}, A: a, Gamma: gamma}
+}
+
+// Trajectory concatenates the simulated states of kinodynamic motions.
+func Trajectory(path []*Motion) []*PathPoint {
+	var trajectory []*PathPoint
+	for _, m := range path {
+		for _, s := range m.Path {
+			trajectory = append(trajectory, s.(*PathPoint))
+		}
+	}
+	return trajectory
+}
+
+func clamp(a, min, max float64) float64 {
+	if a > max {
+		return max
+	} else if a < min {
+		return min
+	}
+	return a
+}
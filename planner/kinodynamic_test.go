@@ -0,0 +1,47 @@
+package planner
+
+import (
+	"math"
+	"testing"
+)
+
+func TestEuler(t *testing.T) {
+	next := Euler(Point{X: 1, V: 2, W: 1}, 0.01, 1, 1)
+	assert(t, math.Abs(next.X-1.02) < 1e-9, "expected x=1.02, got %f", next.X)
+	assert(t, math.Abs(next.Theta-0.01) < 1e-9, "expected θ=0.01, got %f", next.Theta)
+	assert(t, math.Abs(next.V-2.01) < 1e-9, "expected v=2.01, got %f", next.V)
+	equals(t, 1.0, next.A)
+	equals(t, 1.0, next.Gamma)
+}
+
+func TestKinodynamicSteer(t *testing.T) {
+	cSpace := ConfigSpace{
+		XMin: 0, XMax: 100,
+		YMin: 0, YMax: 100,
+		VMin: -5, VMax: 5,
+		WMin: -5, WMax: 5,
+		AMin: -1, AMax: 1,
+		GammaMin: -5, GammaMax: 5,
+	}
+	start := &PathPoint{Point: Point{X: 1}, T: 2}
+	m := Kinodynamic{Space: cSpace, Epsilon: 1}.Steer(start, Point{X: 10, V: 3})
+
+	equals(t, start, m.From.(*PathPoint))
+	equals(t, m.Path[len(m.Path)-1], m.To)
+	prev := start
+	for _, s := range m.Path {
+		p := s.(*PathPoint)
+		assert(t, p.A <= cSpace.AMax, "acceleration should be clamped, got %f", p.A)
+		assert(t, math.Abs(p.T-prev.T-Timestep) < 1e-9, "states should be one timestep apart")
+		prev = p
+	}
+	assert(t, math.Abs(m.Cost-(prev.T-start.T)) < 1e-9, "cost should be the duration")
+	equals(t, len(m.Path), len(Trajectory([]*Motion{m})))
+}
+
+func BenchmarkPropagate(b *testing.B) {
+	start := &PathPoint{Point: Point{X: 10, Y: 10, V: 1}}
+	for i := 0; i < b.N; i++ {
+		Propagate(start, 0.5, 0.2, 2)
+	}
+}
@@ -0,0 +1,173 @@
+package planner
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/hdhauk/enae788v/angle"
+	"github.com/hdhauk/enae788v/graph"
+)
+
+// StateLattice finds trajectories for the robot with dynamics of hw4 by
+// searching a state lattice with A*, expanding every lattice state with the
+// precomputed primitives of its heading and speed. The lattice is anchored
+// at the start. The trajectory is forward simulated with the controls of the
+// primitives from the start, so it follows the Euler steps exactly and
+// drifts from the lattice by at most the tolerance of the primitives per
+// primitive. Every motion is collision checked as simulated, and the state
+// it reaches decides its lattice state. The heuristic is that of Hybrid A*,
+// with the top speed of the primitives and the lattice speeds within the goal
+// velocity.
+type StateLattice struct {
+	Problem       Problem
+	Space         ConfigSpace
+	Obstacles     []Circle // for the heuristic
+	Checker       CollisionChecker
+	Primitives    *Primitives
+	MaxIterations int // stop after this many expansions if non-zero
+}
+
+// NewStateLattice returns a state lattice planner for the problem.
+func NewStateLattice(prob Problem, cSpace ConfigSpace, obstacles []Circle, checker CollisionChecker, primitives *Primitives) *StateLattice {
+	return &StateLattice{Problem: prob, Space: cSpace, Obstacles: obstacles, Checker: checker, Primitives: primitives}
+}
+
+// latticeKey is a state of the lattice.
+type latticeKey struct {
+	x, y, heading, speed int
+}
+
+// latticeNode is a lattice state in the search, with the simulated state
+// that reaches it. The vertex is the one queued in the queue of hw1.
+type latticeNode struct {
+	graph.Vertex
+	key    latticeKey
+	state  *PathPoint
+	motion *Motion
+	parent *latticeNode
+	closed bool
+}
+
+// Plan expands the lattice state with the lowest cost plus heuristic until it
+// reaches the goal region. It gives up when every reachable lattice state is
+// expanded, ctx is done or the iteration limit is reached.
+func (p *StateLattice) Plan(ctx context.Context) (*Solution, error) {
+	prob, lib := p.Problem, p.Primitives
+	goal := NewGoalRegion(prob, p.Space)
+
+	var stats Stats
+	checker := countingChecker{p.Checker, &stats}
+	started := time.Now()
+	var nodes []*latticeNode
+	tree := func() []*Motion {
+		var motions []*Motion
+		for _, n := range nodes {
+			if n.motion != nil {
+				motions = append(motions, n.motion)
+			}
+		}
+		return motions
+	}
+	fail := func(reason string) (*Solution, error) {
+		stats.Elapsed = time.Since(started)
+		return noSolution(reason, stats, tree())
+	}
+
+	if !lib.fits(p.Space) {
+		return fail("the primitives exceed the velocity or control limits of the config space")
+	}
+	root := &PathPoint{Point: prob.Start}
+	if !checker.Valid(root) {
+		return fail("start is in collision or outside the config space")
+	}
+	byStart := map[[2]int][]*Primitive{}
+	var top int
+	for i := range lib.Primitives {
+		q := &lib.Primitives[i]
+		byStart[[2]int{q.Heading, q.Speed}] = append(byStart[[2]int{q.Heading, q.Speed}], q)
+		if q.EndSpeed > top {
+			top = q.EndSpeed
+		}
+	}
+	// Only lattice speeds within the goal velocity can end the search.
+	bounded := prob
+	if b := prob.GoalVelocity; b != nil {
+		bounded.GoalVelocity = &Bounds{Min: math.Ceil(b.Min/lib.SpeedStep) * lib.SpeedStep, Max: math.Floor(b.Max/lib.SpeedStep) * lib.SpeedStep}
+	}
+	h := timeToGoal(bounded, p.Space, p.Obstacles, lib.Resolution, float64(top)*lib.SpeedStep)
+
+	// The vertices of the queue are numbered in the order they are found, and
+	// the node of a vertex is nodes[ID].
+	found := map[latticeKey]*latticeNode{}
+	add := func(s *PathPoint, parent *latticeNode, m *Motion, cost, estimate float64) *latticeNode {
+		n := &latticeNode{key: p.key(s), state: s, motion: m, parent: parent}
+		n.Vertex = graph.Vertex{ID: len(nodes), X: s.X, Y: s.Y, CostToStart: cost, Priority: cost + estimate, Finite: true}
+		nodes = append(nodes, n)
+		found[n.key] = n
+		return n
+	}
+	q := graph.NewQueue()
+	q.PushVertex(&add(root, nil, nil, 0, h(prob.Start)).Vertex)
+	for v := q.PopVertex(); v != nil; v = q.PopVertex() {
+		if err := ctx.Err(); err != nil {
+			return fail(err.Error())
+		}
+		if p.MaxIterations > 0 && stats.Iterations >= p.MaxIterations {
+			return fail("iteration limit reached")
+		}
+		n := nodes[v.ID]
+		n.closed = true
+		stats.Iterations++
+
+		// The root alone is not a path, see Solution.Path.
+		if n.parent != nil && goal.Satisfied(n.state) {
+			stats.Elapsed = time.Since(started)
+			progress := []Progress{{Cost: math.Inf(1)}, {Iteration: stats.Iterations, Elapsed: stats.Elapsed, Cost: n.CostToStart}}
+			var path []*Motion
+			for ; n.parent != nil; n = n.parent {
+				path = append([]*Motion{n.motion}, path...)
+			}
+			return &Solution{Path: path, Tree: tree(), Cost: v.CostToStart, Progress: progress, Stats: stats}, nil
+		}
+
+		for _, primitive := range byStart[[2]int{n.key.heading, n.key.speed}] {
+			m := follow(n.state, primitive.Controls)
+			to := m.To.(*PathPoint)
+			k := p.key(to)
+			w, seen := found[k]
+			cost := n.CostToStart + m.Cost
+			if seen && (w.closed || w.CostToStart <= cost) {
+				continue
+			}
+			estimate := h(to.Point)
+			if math.IsInf(estimate, 1) || !checker.MotionValid(m) {
+				continue
+			}
+			if !seen {
+				q.PushVertex(&add(to, n, m, cost, estimate).Vertex)
+				continue
+			}
+			w.state, w.motion, w.parent = to, m, n
+			w.X, w.Y, w.CostToStart, w.Priority = to.X, to.Y, cost, cost+estimate
+			q.UpdateVertex(&w.Vertex)
+		}
+	}
+	return fail("every reachable lattice state was expanded")
+}
+
+// key returns the nearest lattice state to a state.
+func (p *StateLattice) key(s *PathPoint) latticeKey {
+	lib, start := p.Primitives, p.Problem.Start
+	k := latticeKey{
+		x:     int(math.Round((s.X - start.X) / lib.Resolution)),
+		y:     int(math.Round((s.Y - start.Y) / lib.Resolution)),
+		speed: int(math.Round(s.V / lib.SpeedStep)),
+	}
+	for i, theta := range lib.Headings {
+		if angle.Distance(s.Theta, theta) < angle.Distance(s.Theta, lib.Headings[k.heading]) {
+			k.heading = i
+		}
+	}
+	return k
+}
@@ -0,0 +1,83 @@
+package planner
+
+import (
+	"context"
+	"math"
+	"path/filepath"
+	"testing"
+)
+
+func TestStateLattice(t *testing.T) {
+	config, obstacles, robot, err := LoadConfig(filepath.Join("..", "hw4", "problems.json"))
+	ok(t, err)
+	cSpace := config.ConfigSpace
+	checker := KinodynamicChecker{FootprintChecker{Obstacles: obstacles, Space: cSpace, Robot: robot}}
+	lib := GeneratePrimitives(cSpace, DefaultPrimitiveParams())
+	for _, i := range []int{0, 1} {
+		prob := config.Problems[i]
+		solution, err := NewStateLattice(prob, cSpace, obstacles, checker, lib).Plan(context.Background())
+		ok(t, err)
+		checkTrajectory(t, prob, cSpace, checker, solution)
+
+		// The search is deterministic.
+		again, err := NewStateLattice(prob, cSpace, obstacles, checker, lib).Plan(context.Background())
+		ok(t, err)
+		equals(t, solution.Cost, again.Cost)
+		equals(t, Trajectory(solution.Path), Trajectory(again.Path))
+	}
+}
+
+func TestStateLatticeDocking(t *testing.T) {
+	cSpace := ConfigSpace{
+		XMin: 0, XMax: 30,
+		YMin: 0, YMax: 30,
+		VMin: -5, VMax: 5,
+		WMin: -1.5, WMax: 1.5,
+		AMin: -2, AMax: 2,
+		GammaMin: -1.5, GammaMax: 1.5,
+	}
+	prob := Problem{
+		Start:           Point{X: 5.5, Y: 5.25, Theta: 0.01},
+		Goal:            Circle{X: 20, Y: 20, R: 3},
+		GoalHeading:     &HeadingConstraint{Theta: math.Pi, Tolerance: 0.1},
+		GoalVelocity:    &Bounds{Min: -0.2, Max: 0.2},
+		GoalAngularRate: &Bounds{Min: -0.1, Max: 0.1},
+	}
+	obstacles := []Circle{{X: 12, Y: 12, R: 3}}
+	checker := KinodynamicChecker{FootprintChecker{Obstacles: obstacles, Space: cSpace, Robot: Robot{{X: -1}, {}, {X: 1}}}}
+
+	// The start is off the lattice of the config space, and the lattice
+	// reaches the goal heading and velocity exactly.
+	lib := GeneratePrimitives(cSpace, DefaultPrimitiveParams())
+	solution, err := NewStateLattice(prob, cSpace, obstacles, checker, lib).Plan(context.Background())
+	ok(t, err)
+	checkTrajectory(t, prob, cSpace, checker, solution)
+}
+
+func TestStateLatticeNoSolution(t *testing.T) {
+	cSpace := ConfigSpace{XMin: 0, XMax: 30, YMin: 0, YMax: 30, VMin: -5, VMax: 5, WMin: -1.5, WMax: 1.5, AMin: -2, AMax: 2, GammaMin: -1.5, GammaMax: 1.5}
+	prob := Problem{Start: Point{X: 5, Y: 5}, Goal: Circle{X: 20, Y: 20, R: 2}}
+	obstacles := []Circle{{X: 20, Y: 20, R: 4}}
+	checker := KinodynamicChecker{FootprintChecker{Obstacles: obstacles, Space: cSpace, Robot: Robot{{}}}}
+	lib := GeneratePrimitives(cSpace, DefaultPrimitiveParams())
+
+	solution, err := NewStateLattice(prob, cSpace, obstacles, checker, lib).Plan(context.Background())
+	_, isNoSol := err.(*NoSolutionError)
+	assert(t, isNoSol, "expected a *NoSolutionError, got %v", err)
+	assert(t, solution == nil || len(solution.Path) == 0, "expected no path")
+
+	// Primitives generated for wider limits could break the limits.
+	narrow := cSpace
+	narrow.AMin, narrow.AMax = -1, 1
+	prob.Goal.X, prob.Goal.Y = 25, 25
+	_, err = NewStateLattice(prob, narrow, nil, checker, lib).Plan(context.Background())
+	_, isNoSol = err.(*NoSolutionError)
+	assert(t, isNoSol, "expected a *NoSolutionError, got %v", err)
+
+	lattice := NewStateLattice(prob, cSpace, nil, checker, lib)
+	lattice.MaxIterations = 5
+	_, err = lattice.Plan(context.Background())
+	noSol, isNoSol := err.(*NoSolutionError)
+	assert(t, isNoSol, "expected a *NoSolutionError, got %v", err)
+	equals(t, 5, noSol.Iterations)
+}
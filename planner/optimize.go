@@ -0,0 +1,500 @@
+package planner
+
+import (
+	"context"
+	"math"
+
+	"github.com/pkg/errors"
+
+	"github.com/hdhauk/enae788v/angle"
+)
+
+// OptimizerParams configures the trajectory optimizer.
+type OptimizerParams struct {
+	Iterations int     // iLQR iterations for every duration
+	TimeWeight float64 // cost of a second, against the integral of the squared controls
+	Shorten    float64 // fraction of the duration dropped between attempts, 0 keeps the duration
+	Clearance  float64 // distance to obstacles and walls below which the footprint is penalized
+	Margin     float64 // fraction of the velocity, control and goal bounds the penalties allow
+}
+
+// DefaultOptimizerParams returns parameters that work for the problems in
+// hw4/problems.json.
+func DefaultOptimizerParams() OptimizerParams {
+	return OptimizerParams{Iterations: 100, TimeWeight: 1, Shorten: 0.05, Clearance: 0.2, Margin: 0.95}
+}
+
+// TrajectoryOptimizer refines a trajectory of the robot with dynamics of hw4,
+// such as one found by RRT, with the iterative linear quadratic regulator.
+// It minimizes the integral of the squared controls plus TimeWeight per
+// second, subject to the Euler steps of Propagate. The velocity and control
+// limits, the clearance of every point of the footprint to the obstacles and
+// walls, and the goal constraints at the end are soft penalties that grow
+// with the square of their violation, linearized to Gauss-Newton costs. The
+// controls are clamped to their limits as the trajectory is simulated.
+//
+// The duration is fixed while iLQR runs, so the optimizer starts with the
+// duration of the given trajectory and shortens it by Shorten after every
+// attempt, rescaling the controls of the last one in time, until a few
+// attempts in a row are infeasible or no better. Every attempt is validated
+// like a delivered trajectory csv, and the best feasible one is returned.
+type TrajectoryOptimizer struct {
+	Problem   Problem
+	Space     ConfigSpace
+	Obstacles []Circle // for the penalties
+	Robot     Robot    // for the penalties
+	Checker   CollisionChecker
+	Params    OptimizerParams
+}
+
+// NewTrajectoryOptimizer returns a trajectory optimizer for the problem.
+func NewTrajectoryOptimizer(prob Problem, cSpace ConfigSpace, obstacles []Circle, robot Robot, checker CollisionChecker, params OptimizerParams) *TrajectoryOptimizer {
+	return &TrajectoryOptimizer{Problem: prob, Space: cSpace, Obstacles: obstacles, Robot: robot, Checker: checker, Params: params}
+}
+
+// Optimize returns a refined trajectory from the start of the problem that
+// is at least as good as trajectory, which it takes as the initial guess. It
+// returns trajectory itself if no attempt improves it, and the best so far if
+// ctx is done. It returns an error if trajectory is not a feasible trajectory
+// from the start to the goal.
+func (o *TrajectoryOptimizer) Optimize(ctx context.Context, trajectory []*PathPoint) ([]*PathPoint, error) {
+	if len(trajectory) == 0 {
+		return nil, errors.New("the trajectory is empty")
+	}
+	if err := o.validate(trajectory); err != nil {
+		return nil, errors.Wrap(err, "the initial trajectory is infeasible")
+	}
+	best, bestCost := trajectory, o.Objective(trajectory)
+
+	ref := trajectory
+	for misses := 0; ctx.Err() == nil && misses < maxMisses; {
+		path, controls := o.track(ref)
+		path = o.ilqr(ctx, path, controls)
+		// A shorter attempt may be feasible again, as iLQR starts from a
+		// different guess.
+		if o.validate(path) != nil {
+			misses++
+		} else if cost := o.Objective(path); cost < bestCost {
+			best, bestCost, misses = path, cost, 0
+		} else {
+			misses++
+		}
+
+		n := int(float64(len(path)) * (1 - o.Params.Shorten))
+		if o.Params.Shorten <= 0 || n < 1 || n == len(path) {
+			break
+		}
+		ref = stretch(o.Problem.Start, path, n)
+	}
+	return best, nil
+}
+
+// maxMisses is the number of attempts in a row that may be infeasible or fail
+// to improve the trajectory before the optimizer stops shortening it.
+const maxMisses = 3
+
+// Objective returns the cost the optimizer minimizes: the integral of the
+// squared controls along a trajectory from the start plus TimeWeight per
+// second.
+func (o *TrajectoryOptimizer) Objective(trajectory []*PathPoint) float64 {
+	if len(trajectory) == 0 {
+		return 0
+	}
+	m := &Motion{From: &PathPoint{Point: o.Problem.Start}, To: trajectory[len(trajectory)-1]}
+	for _, p := range trajectory {
+		m.Path = append(m.Path, p)
+	}
+	return EffortCost(m) + o.Params.TimeWeight*DurationCost(m)
+}
+
+// validate returns the first violation of trajectory, as checked for a
+// delivered trajectory csv.
+func (o *TrajectoryOptimizer) validate(trajectory []*PathPoint) error {
+	rows := TrajectoryRows(o.Problem.Start, trajectory)
+	if violations := ValidateTrajectory(rows, o.Problem, o.Space, o.Checker, 1e-9); len(violations) > 0 {
+		return violations[0]
+	}
+	return nil
+}
+
+// stretch returns the trajectory scaled in time to n Timesteps. The states
+// are interpolated along the path from start, the velocities scale with the
+// inverse of the duration and the accelerations with its square.
+func stretch(start Point, trajectory []*PathPoint, n int) []*PathPoint {
+	states := append([]*PathPoint{{Point: start}}, trajectory...)
+	s := float64(len(trajectory)) / float64(n)
+	lerp := func(a, b, f float64) float64 { return a + f*(b-a) }
+	out := make([]*PathPoint, n)
+	for j := range out {
+		t := float64(j+1) * s
+		i := int(math.Min(math.Floor(t), float64(len(trajectory)-1)))
+		f := t - float64(i)
+		from, to := states[i], states[i+1]
+		// The controls are those that reach the state after t.
+		c := states[int(math.Ceil(t-1e-9))]
+		out[j] = &PathPoint{
+			Point: Point{
+				X:     lerp(from.X, to.X, f),
+				Y:     lerp(from.Y, to.Y, f),
+				Theta: lerp(from.Theta, to.Theta, f),
+				V:     s * lerp(from.V, to.V, f),
+				W:     s * lerp(from.W, to.W, f),
+			},
+			A:     s * s * c.A,
+			Gamma: s * s * c.Gamma,
+			T:     float64(j+1) * Timestep,
+		}
+	}
+	return out
+}
+
+// track simulates following ref from the start in Timesteps, with its
+// controls corrected by the LQR feedback of SimulateTracking, and returns the
+// trajectory with the controls applied.
+func (o *TrajectoryOptimizer) track(ref []*PathPoint) ([]*PathPoint, [][2]float64) {
+	cfg := DefaultTrackingConfig()
+	gains := lqrGains(append([]*PathPoint{{Point: o.Problem.Start}}, ref...), cfg.Q, cfg.R)
+	controls := make([][2]float64, len(ref))
+	ff := make([][2]float64, len(ref))
+	fb := make([][2][5]float64, len(ref))
+	for k, p := range ref {
+		controls[k] = [2]float64{p.A, p.Gamma}
+		for i := 0; i < 2; i++ {
+			for j := 0; j < 5; j++ {
+				fb[k][i][j] = -gains[k][i][j]
+			}
+		}
+	}
+	return o.rollout(controls, ref, ff, fb, 0)
+}
+
+// ilqr improves the controls of a trajectory with iLQR until the cost
+// converges or the iteration limit is reached, and returns the trajectory.
+func (o *TrajectoryOptimizer) ilqr(ctx context.Context, path []*PathPoint, controls [][2]float64) []*PathPoint {
+	cost := o.cost(path, controls)
+	mu := 1e-6 // regularization of the control Hessian
+	for i := 0; i < o.Params.Iterations && ctx.Err() == nil; i++ {
+		ff, fb := o.backward(path, controls, mu)
+		improved := false
+		for alpha := 1.0; alpha > 1e-3; alpha /= 2 {
+			next, nextControls := o.rollout(controls, path, ff, fb, alpha)
+			if c := o.cost(next, nextControls); c < cost {
+				converged := cost-c < 1e-4*cost
+				path, controls, cost, improved = next, nextControls, c, true
+				if converged {
+					return path
+				}
+				break
+			}
+		}
+		if !improved {
+			if mu *= 10; mu > 1e6 {
+				break
+			}
+			continue
+		}
+		mu = math.Max(mu/10, 1e-9)
+	}
+	return path
+}
+
+// rollout simulates the controls from the start, corrected by the feedforward
+// terms ff scaled by alpha and the feedback gains fb on the deviation from
+// ref if they are given, and clamps them to the control limits.
+func (o *TrajectoryOptimizer) rollout(controls [][2]float64, ref []*PathPoint, ff [][2]float64, fb [][2][5]float64, alpha float64) ([]*PathPoint, [][2]float64) {
+	cSpace := o.Space
+	path := make([]*PathPoint, len(controls))
+	clamped := make([][2]float64, len(controls))
+	X, refX := o.Problem.Start, o.Problem.Start
+	for k, u := range controls {
+		if ff != nil {
+			e := stateError(X, refX)
+			for i := 0; i < 2; i++ {
+				u[i] += alpha * ff[k][i]
+				for j := 0; j < 5; j++ {
+					u[i] += fb[k][i][j] * e[j]
+				}
+			}
+			refX = ref[k].Point
+		}
+		u[0] = clamp(u[0], cSpace.AMin, cSpace.AMax)
+		u[1] = clamp(u[1], cSpace.GammaMin, cSpace.GammaMax)
+		next := Euler(X, Timestep, u[0], u[1])
+		next.T = float64(k+1) * Timestep
+		path[k], clamped[k] = &next, u
+		X = next.Point
+	}
+	return path, clamped
+}
+
+// stateError returns the difference between two states as a vector.
+func stateError(X, ref Point) [5]float64 {
+	return [5]float64{X.X - ref.X, X.Y - ref.Y, angle.Diff(ref.Theta, X.Theta), X.V - ref.V, X.W - ref.W}
+}
+
+// backward returns the feedforward terms and feedback gains of every control
+// that minimize the quadratic model of the cost around the trajectory, with
+// mu added to the Hessian of the controls.
+func (o *TrajectoryOptimizer) backward(path []*PathPoint, controls [][2]float64, mu float64) ([][2]float64, [][2][5]float64) {
+	n := len(controls)
+	ff, fb := make([][2]float64, n), make([][2][5]float64, n)
+	terminal := o.stateCost(path[n-1].Point, true)
+	Vx, Vxx := terminal.g, terminal.H
+	for k := n - 1; k >= 0; k-- {
+		X := o.Problem.Start
+		if k > 0 {
+			X = path[k-1].Point
+		}
+		A, B := linearize(&PathPoint{Point: X}, Timestep)
+		var l quadCost
+		if k > 0 {
+			l = o.stateCost(X, false)
+		}
+		_, lu, luu := o.controlCost(controls[k])
+
+		var Qx [5]float64
+		var Qu [2]float64
+		var Qxx [5][5]float64
+		var Quu [2][2]float64
+		var Qux [2][5]float64
+		var VA [5][5]float64 // Vxx A
+		var VB [5][2]float64 // Vxx B
+		for i := 0; i < 5; i++ {
+			for j := 0; j < 5; j++ {
+				for m := 0; m < 5; m++ {
+					VA[i][j] += Vxx[i][m] * A[m][j]
+				}
+			}
+			for j := 0; j < 2; j++ {
+				for m := 0; m < 5; m++ {
+					VB[i][j] += Vxx[i][m] * B[m][j]
+				}
+			}
+		}
+		for i := 0; i < 5; i++ {
+			Qx[i] = l.g[i]
+			for m := 0; m < 5; m++ {
+				Qx[i] += A[m][i] * Vx[m]
+			}
+			for j := 0; j < 5; j++ {
+				Qxx[i][j] = l.H[i][j]
+				for m := 0; m < 5; m++ {
+					Qxx[i][j] += A[m][i] * VA[m][j]
+				}
+			}
+		}
+		for i := 0; i < 2; i++ {
+			Qu[i] = lu[i]
+			for m := 0; m < 5; m++ {
+				Qu[i] += B[m][i] * Vx[m]
+			}
+			for j := 0; j < 2; j++ {
+				Quu[i][j] = luu[i][j]
+				for m := 0; m < 5; m++ {
+					Quu[i][j] += B[m][i] * VB[m][j]
+				}
+			}
+			Quu[i][i] += mu
+			for j := 0; j < 5; j++ {
+				for m := 0; m < 5; m++ {
+					Qux[i][j] += B[m][i] * VA[m][j]
+				}
+			}
+		}
+
+		// ff = -Quu⁻¹ Qu, fb = -Quu⁻¹ Qux
+		det := Quu[0][0]*Quu[1][1] - Quu[0][1]*Quu[1][0]
+		inv := [2][2]float64{{Quu[1][1] / det, -Quu[0][1] / det}, {-Quu[1][0] / det, Quu[0][0] / det}}
+		for i := 0; i < 2; i++ {
+			ff[k][i] = -(inv[i][0]*Qu[0] + inv[i][1]*Qu[1])
+			for j := 0; j < 5; j++ {
+				fb[k][i][j] = -(inv[i][0]*Qux[0][j] + inv[i][1]*Qux[1][j])
+			}
+		}
+
+		// Vx = Qx + Kᵀ Quu k + Kᵀ Qu + Quxᵀ k
+		// Vxx = Qxx + Kᵀ Quu K + Kᵀ Qux + Quxᵀ K
+		K, d := fb[k], ff[k]
+		for i := 0; i < 5; i++ {
+			Vx[i] = Qx[i]
+			for a := 0; a < 2; a++ {
+				Vx[i] += K[a][i]*Qu[a] + Qux[a][i]*d[a]
+				for b := 0; b < 2; b++ {
+					Vx[i] += K[a][i] * Quu[a][b] * d[b]
+				}
+			}
+			for j := 0; j < 5; j++ {
+				Vxx[i][j] = Qxx[i][j]
+				for a := 0; a < 2; a++ {
+					Vxx[i][j] += K[a][i]*Qux[a][j] + Qux[a][i]*K[a][j]
+					for b := 0; b < 2; b++ {
+						Vxx[i][j] += K[a][i] * Quu[a][b] * K[b][j]
+					}
+				}
+			}
+		}
+		for i := 0; i < 5; i++ {
+			for j := 0; j < i; j++ {
+				Vxx[i][j] = (Vxx[i][j] + Vxx[j][i]) / 2
+				Vxx[j][i] = Vxx[i][j]
+			}
+		}
+	}
+	return ff, fb
+}
+
+// Weights of the penalties along the trajectory and at its end.
+const (
+	penaltyWeight  = 1e3
+	terminalWeight = 1e4
+)
+
+// quadCost is a cost of a state with its gradient and Gauss-Newton Hessian.
+type quadCost struct {
+	c float64
+	g [5]float64
+	H [5][5]float64
+}
+
+// add adds the penalty w·r² if the residual r is positive, with gradient dr.
+func (q *quadCost) add(w, r float64, dr [5]float64) {
+	if r <= 0 {
+		return
+	}
+	q.c += w * r * r
+	for i := 0; i < 5; i++ {
+		q.g[i] += 2 * w * r * dr[i]
+		for j := 0; j < 5; j++ {
+			q.H[i][j] += 2 * w * dr[i] * dr[j]
+		}
+	}
+}
+
+// cost returns the total cost of a trajectory with its controls.
+func (o *TrajectoryOptimizer) cost(path []*PathPoint, controls [][2]float64) float64 {
+	cost := o.Params.TimeWeight * float64(len(controls)) * Timestep
+	for k, u := range controls {
+		c, _, _ := o.controlCost(u)
+		cost += c + o.stateCost(path[k].Point, k == len(controls)-1).c
+	}
+	return cost
+}
+
+// controlCost returns the effort of controls u over a Timestep plus the
+// penalties of u outside the margin of the control limits, with its gradient
+// and Hessian.
+func (o *TrajectoryOptimizer) controlCost(u [2]float64) (float64, [2]float64, [2][2]float64) {
+	cSpace, m := o.Space, o.Params.Margin
+	c := Timestep * (u[0]*u[0] + u[1]*u[1])
+	g := [2]float64{2 * Timestep * u[0], 2 * Timestep * u[1]}
+	H := [2][2]float64{{2 * Timestep, 0}, {0, 2 * Timestep}}
+	bounds := [2][2]float64{{m * cSpace.AMin, m * cSpace.AMax}, {m * cSpace.GammaMin, m * cSpace.GammaMax}}
+	for i, b := range bounds {
+		r, sign := u[i]-b[1], 1.0
+		if u[i] < b[0] {
+			r, sign = b[0]-u[i], -1
+		}
+		if r > 0 {
+			c += penaltyWeight * r * r
+			g[i] += 2 * penaltyWeight * r * sign
+			H[i][i] += 2 * penaltyWeight
+		}
+	}
+	return c, g, H
+}
+
+// stateCost returns the penalties of state X: velocities outside the margin
+// of the limits, and points of the footprint closer than the clearance to an
+// obstacle or a wall. At the end of the trajectory, it also penalizes states
+// outside the margin of the goal constraints.
+func (o *TrajectoryOptimizer) stateCost(X Point, terminal bool) quadCost {
+	cSpace, m := o.Space, o.Params.Margin
+	var q quadCost
+	unit := func(i int, scale float64) [5]float64 {
+		var d [5]float64
+		d[i] = scale
+		return d
+	}
+	bounded := func(w float64, i int, value, min, max float64) {
+		q.add(w, value-max, unit(i, 1))
+		q.add(w, min-value, unit(i, -1))
+	}
+	bounded(penaltyWeight, 3, X.V, m*cSpace.VMin, m*cSpace.VMax)
+	bounded(penaltyWeight, 4, X.W, m*cSpace.WMin, m*cSpace.WMax)
+
+	o.footprintCost(&q, X)
+	if !terminal {
+		return q
+	}
+
+	prob := o.Problem
+	g := prob.Goal
+	if d := math.Hypot(X.X-g.X, X.Y-g.Y); d > 0 {
+		q.add(terminalWeight, d-m*g.R, [5]float64{(X.X - g.X) / d, (X.Y - g.Y) / d})
+	}
+	if h := prob.GoalHeading; h != nil {
+		diff := angle.Diff(h.Theta, X.Theta)
+		q.add(terminalWeight, math.Abs(diff)-m*h.Tolerance, unit(2, math.Copysign(1, diff)))
+	}
+	shrink := func(b Bounds) (float64, float64) {
+		slack := (1 - m) * (b.Max - b.Min) / 2
+		return b.Min + slack, b.Max - slack
+	}
+	if b := prob.GoalVelocity; b != nil {
+		min, max := shrink(*b)
+		bounded(terminalWeight, 3, X.V, min, max)
+	}
+	if b := prob.GoalAngularRate; b != nil {
+		min, max := shrink(*b)
+		bounded(terminalWeight, 4, X.W, min, max)
+	}
+	return q
+}
+
+// footprintCost adds the penalties of the points of the footprint at X that
+// are closer than the clearance to an obstacle or a wall.
+func (o *TrajectoryOptimizer) footprintCost(q *quadCost, X Point) {
+	cSpace, clearance := o.Space, o.Params.Clearance
+	// Only the obstacles and walls within reach of the footprint matter.
+	reach := o.Robot.reach() + clearance
+	var near []Circle
+	for _, c := range o.Obstacles {
+		if math.Hypot(X.X-c.X, X.Y-c.Y) <= c.R+reach {
+			near = append(near, c)
+		}
+	}
+	walls := X.X-reach < cSpace.XMin || X.X+reach > cSpace.XMax || X.Y-reach < cSpace.YMin || X.Y+reach > cSpace.YMax
+	if len(near) == 0 && !walls {
+		return
+	}
+	sin, cos := math.Sin(X.Theta), math.Cos(X.Theta)
+	for _, offset := range o.Robot {
+		p := RobotPointGlobal(X, offset)
+		// The derivatives of the point with respect to x, y and θ.
+		dx := [5]float64{1, 0, -offset.X*sin - offset.Y*cos}
+		dy := [5]float64{0, 1, offset.X*cos - offset.Y*sin}
+		q.add(penaltyWeight, cSpace.XMin+clearance-p.X, scaled(dx, -1))
+		q.add(penaltyWeight, p.X-cSpace.XMax+clearance, dx)
+		q.add(penaltyWeight, cSpace.YMin+clearance-p.Y, scaled(dy, -1))
+		q.add(penaltyWeight, p.Y-cSpace.YMax+clearance, dy)
+		for _, c := range near {
+			d := math.Hypot(p.X-c.X, p.Y-c.Y)
+			if d == 0 || d > c.R+clearance {
+				continue
+			}
+			var dd [5]float64
+			for i := 0; i < 5; i++ {
+				dd[i] = -((p.X-c.X)*dx[i] + (p.Y-c.Y)*dy[i]) / d
+			}
+			q.add(penaltyWeight, c.R+clearance-d, dd)
+		}
+	}
+}
+
+// scaled returns v multiplied by s.
+func scaled(v [5]float64, s float64) [5]float64 {
+	for i := range v {
+		v[i] *= s
+	}
+	return v
+}
@@ -0,0 +1,95 @@
+package planner
+
+import (
+	"context"
+	"math"
+	"testing"
+)
+
+// optimizerProblem returns a problem with an obstacle in the way and goal
+// constraints, and a feasible trajectory for it found by RRT.
+func optimizerProblem(t *testing.T) (Problem, ConfigSpace, []Circle, Robot, CollisionChecker, []*PathPoint) {
+	cSpace := ConfigSpace{
+		XMin: 0, XMax: 40,
+		YMin: 0, YMax: 40,
+		VMin: -5, VMax: 5,
+		WMin: -1.5, WMax: 1.5,
+		AMin: -2, AMax: 2,
+		GammaMin: -1.5, GammaMax: 1.5,
+	}
+	prob := Problem{
+		Start:        Point{X: 5, Y: 5},
+		Goal:         Circle{X: 32, Y: 32, R: 4},
+		GoalVelocity: &Bounds{Min: -1, Max: 1},
+		Epsilon:      5,
+		GoalBias:     0.1,
+	}
+	obstacles := []Circle{{X: 18, Y: 18, R: 5}}
+	robot := Robot{{X: -0.5}, {}, {X: 0.5}}
+	checker := KinodynamicChecker{FootprintChecker{Obstacles: obstacles, Space: cSpace, Robot: robot}}
+	solution, err := NewKinodynamicRRT(prob, cSpace, checker, 1).Plan(context.Background())
+	ok(t, err)
+	return prob, cSpace, obstacles, robot, checker, Trajectory(solution.Path)
+}
+
+func TestTrajectoryOptimizer(t *testing.T) {
+	prob, cSpace, obstacles, robot, checker, trajectory := optimizerProblem(t)
+	o := NewTrajectoryOptimizer(prob, cSpace, obstacles, robot, checker, DefaultOptimizerParams())
+	refined, err := o.Optimize(context.Background(), trajectory)
+	ok(t, err)
+
+	last := refined[len(refined)-1]
+	path := []*Motion{{From: &PathPoint{Point: prob.Start}, To: last}}
+	for _, p := range refined {
+		path[0].Path = append(path[0].Path, p)
+	}
+	checkTrajectory(t, prob, cSpace, checker, &Solution{Path: path, Cost: last.T})
+	equals(t, 0, len(ValidateTrajectory(TrajectoryRows(prob.Start, refined), prob, cSpace, checker, 1e-9)))
+
+	before, after := o.Objective(trajectory), o.Objective(refined)
+	assert(t, after < 0.8*before, "the objective should drop well below %f, got %f", before, after)
+	assert(t, last.T <= trajectory[len(trajectory)-1].T+1e-9, "the duration %f should not grow from %f", last.T, trajectory[len(trajectory)-1].T)
+
+	// Without shortening, the duration is kept.
+	params := DefaultOptimizerParams()
+	params.Shorten = 0
+	o.Params = params
+	kept, err := o.Optimize(context.Background(), trajectory)
+	ok(t, err)
+	equals(t, len(trajectory), len(kept))
+	assert(t, o.Objective(kept) < o.Objective(trajectory), "the objective should drop at a fixed duration")
+}
+
+func TestTrajectoryOptimizerInfeasible(t *testing.T) {
+	prob, cSpace, obstacles, robot, checker, trajectory := optimizerProblem(t)
+	o := NewTrajectoryOptimizer(prob, cSpace, obstacles, robot, checker, DefaultOptimizerParams())
+	_, err := o.Optimize(context.Background(), nil)
+	assert(t, err != nil, "an empty trajectory should be an error")
+	_, err = o.Optimize(context.Background(), trajectory[:len(trajectory)/2])
+	assert(t, err != nil, "a trajectory that does not reach the goal should be an error")
+}
+
+func TestStretch(t *testing.T) {
+	prob, _, path := testTrajectory(t)
+	same := stretch(prob.Start, path, len(path))
+	for i, p := range same {
+		q := path[i]
+		assert(t, math.Abs(p.X-q.X)+math.Abs(p.Y-q.Y)+math.Abs(p.V-q.V)+math.Abs(p.A-q.A) < 1e-9, "state %d should be unchanged, got %v, expected %v", i, p, q)
+	}
+
+	// Half the duration doubles the velocities and quadruples the controls.
+	path = path[:len(path)/2*2]
+	half := stretch(prob.Start, path, len(path)/2)
+	equals(t, len(path)/2, len(half))
+	for j, p := range half {
+		q := path[2*j+1]
+		assert(t, math.Abs(p.X-q.X) < 1e-9 && math.Abs(p.V-2*q.V) < 1e-9 && math.Abs(p.A-4*q.A) < 1e-9, "state %d: got %v, expected %v scaled", j, p, q)
+		assert(t, math.Abs(p.T-float64(j+1)*Timestep) < 1e-9, "state %d should be at t=%f, got %f", j, float64(j+1)*Timestep, p.T)
+	}
+}
+
+func TestStateError(t *testing.T) {
+	// Headings on either side of ±π are close, not a full turn apart.
+	e := stateError(Point{Theta: math.Pi - 0.1}, Point{Theta: -math.Pi + 0.1})
+	assert(t, math.Abs(e[2]+0.2) < 1e-9, "expected a heading error of -0.2, got %f", e[2])
+}
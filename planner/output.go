@@ -0,0 +1,92 @@
+package planner
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/pkg/errors"
+)
+
+// WriteText writes a solution in the text format read by the plot.py
+// scripts. It starts with the start and goal of the problem, and for planners
+// that keep improving their solution the best cost after every iteration where
+// it improved, and after every reportEvery-th iteration without the elapsed
+// time, which is only recorded at improvements.
+// Then follows the path as line segments between START_PATH and END_PATH,
+// written from the goal to the start, and the tree as line segments between
+// START_TREE and END_TREE. Every path segment ends with the heading at its
+// first point.
+func WriteText(w io.Writer, prob Problem, sol *Solution, reportEvery int) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "start=[%.4f,%.4f] goal=[%.4f,%.4f,%.4f]\n\n", prob.Start.X, prob.Start.Y, prob.Goal.X, prob.Goal.Y, prob.Goal.R)
+
+	if len(sol.Progress) > 0 {
+		writeProgress(bw, sol, reportEvery)
+		fmt.Fprintln(bw)
+	}
+
+	fmt.Fprintln(bw, "START_PATH")
+	states := PathStates(sol.Path)
+	for i := len(states) - 1; i > 0; i-- {
+		tail, head := Pose(states[i-1]), Pose(states[i])
+		fmt.Fprintf(bw, "%.4f, %.4f, %.4f, %.4f, %.4f\n", tail.X, tail.Y, head.X, head.Y, tail.Theta)
+	}
+	fmt.Fprintln(bw, "END_PATH")
+	fmt.Fprintln(bw)
+
+	fmt.Fprintln(bw, "START_TREE")
+	for _, m := range sol.Tree {
+		tail, head := Pose(m.From), Pose(m.To)
+		fmt.Fprintf(bw, "%.4f, %.4f, %.4f, %.4f\n", head.X, head.Y, tail.X, tail.Y)
+	}
+	fmt.Fprintln(bw, "END_TREE")
+
+	return errors.Wrap(bw.Flush(), "could not write solution")
+}
+
+// writeProgress writes the best cost after every improvement recorded in the
+// progress of sol, and after every reportEvery-th iteration in between.
+func writeProgress(w io.Writer, sol *Solution, reportEvery int) {
+	best, last := math.Inf(1), -1
+	improve := func(p Progress) {
+		if p.Cost < best {
+			fmt.Fprintf(w, "iteration=%d elapsed=%.3fs best_cost=%.4f\n", p.Iteration, p.Elapsed.Seconds(), p.Cost)
+			best, last = p.Cost, p.Iteration
+		}
+	}
+	progress := sol.Progress
+	if reportEvery > 0 {
+		for i := reportEvery; i <= sol.Stats.Iterations; i += reportEvery {
+			for len(progress) > 0 && progress[0].Iteration <= i {
+				improve(progress[0])
+				progress = progress[1:]
+			}
+			if last != i {
+				fmt.Fprintf(w, "iteration=%d best_cost=%.4f\n", i, best)
+			}
+		}
+	}
+	for _, p := range progress {
+		improve(p)
+	}
+}
+
+// PathStates returns every state along a path, starting with the start state
+// and including the intermediate states of motions that are not straight
+// lines.
+func PathStates(path []*Motion) []State {
+	if len(path) == 0 {
+		return nil
+	}
+	states := []State{path[0].From}
+	for _, m := range path {
+		if len(m.Path) == 0 {
+			states = append(states, m.To)
+			continue
+		}
+		states = append(states, m.Path...)
+	}
+	return states
+}
@@ -0,0 +1,55 @@
+package planner
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteText(t *testing.T) {
+	a, b, c := Point{X: 1, Y: 1}, Point{X: 2, Y: 1, Theta: 0.5}, Point{X: 2, Y: 3}
+	first := &Motion{From: a, To: b, Cost: 1}
+	second := &Motion{From: b, To: c, Cost: 2}
+	sol := &Solution{Path: []*Motion{first, second}, Tree: []*Motion{first, second}, Cost: 3}
+	prob := Problem{Start: a, Goal: Circle{X: 2, Y: 3, R: 1}}
+
+	var buf bytes.Buffer
+	ok(t, WriteText(&buf, prob, sol, 0))
+	want := `start=[1.0000,1.0000] goal=[2.0000,3.0000,1.0000]
+
+START_PATH
+2.0000, 1.0000, 2.0000, 3.0000, 0.5000
+1.0000, 1.0000, 2.0000, 1.0000, 0.0000
+END_PATH
+
+START_TREE
+2.0000, 1.0000, 1.0000, 1.0000
+2.0000, 3.0000, 2.0000, 1.0000
+END_TREE
+`
+	equals(t, want, buf.String())
+}
+
+func TestWriteTextProgress(t *testing.T) {
+	sol := &Solution{
+		Progress: []Progress{{Cost: math.Inf(1)}, {Iteration: 3, Elapsed: time.Second, Cost: 10}, {Iteration: 4, Elapsed: 2 * time.Second, Cost: 8}},
+		Stats:    Stats{Iterations: 7},
+	}
+
+	var buf bytes.Buffer
+	ok(t, WriteText(&buf, Problem{}, sol, 2))
+	lines := strings.Split(buf.String(), "\n")[2:7]
+	equals(t, []string{
+		"iteration=2 best_cost=+Inf",
+		"iteration=3 elapsed=1.000s best_cost=10.0000",
+		"iteration=4 elapsed=2.000s best_cost=8.0000",
+		"iteration=6 best_cost=8.0000",
+		"",
+	}, lines)
+
+	buf.Reset()
+	ok(t, WriteText(&buf, Problem{}, sol, 0))
+	equals(t, 2, strings.Count(buf.String(), "iteration="))
+}
@@ -0,0 +1,179 @@
+// Package planner contains the sampling based motion planners used by the
+// homework front-ends, built from small components: a StateSpace that
+// measures distances, a Sampler that draws random states, a Steerer that
+// extends the tree toward a sample, a CollisionChecker that validates states
+// and motions, and a Goal. The point robot (hw2), the robot with a footprint
+// (hw3) and the robot with dynamics (hw4) are different combinations of these
+// components.
+package planner
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// State is a configuration of the robot. The geometric planners use Point
+// states, and the kinodynamic planners use *PathPoint states.
+type State interface{}
+
+// StateSpace measures the distance between two states.
+type StateSpace interface {
+	Distance(a, b State) float64
+}
+
+// Sampler draws random states from the state space.
+type Sampler interface {
+	Sample(rng *rand.Rand) State
+}
+
+// Steerer creates a motion from a state toward another state. The motion does
+// not have to reach the target. Steer returns nil if no motion is possible.
+type Steerer interface {
+	Steer(from, toward State) *Motion
+}
+
+// CollisionChecker validates states, and the motions between them.
+type CollisionChecker interface {
+	Valid(s State) bool
+	MotionValid(m *Motion) bool
+}
+
+// Goal decides whether a state solves the problem, and samples states that
+// do for goal biased sampling.
+type Goal interface {
+	Satisfied(s State) bool
+	Sample(rng *rand.Rand) State
+}
+
+// Planner finds a path from the start to the goal of a problem. Plan stops
+// when ctx is done, and returns a *NoSolutionError if no path was found.
+type Planner interface {
+	Plan(ctx context.Context) (*Solution, error)
+}
+
+// NoSolutionError is returned by a planner that stops before it reaches the
+// goal, together with a solution holding the partial tree.
+type NoSolutionError struct {
+	Reason     string
+	Iterations int
+	Tree       []*Motion // every motion in the tree when the planner stopped
+}
+
+func (e *NoSolutionError) Error() string {
+	return fmt.Sprintf("no solution found after %d iterations: %s", e.Iterations, e.Reason)
+}
+
+// noSolution returns a solution without a path, and the error explaining why.
+func noSolution(reason string, stats Stats, tree []*Motion) (*Solution, error) {
+	return &Solution{Tree: tree, Cost: math.Inf(1), Stats: stats}, &NoSolutionError{Reason: reason, Iterations: stats.Iterations, Tree: tree}
+}
+
+// Motion is an edge in the tree from one state to another. Path holds the
+// intermediate states of motions that are not straight lines, ending in To.
+type Motion struct {
+	From, To State
+	Path     []State
+	Cost     float64
+}
+
+func (m *Motion) String() string {
+	return fmt.Sprintf("motion(%v -> %v, len(path)=%d, cost=%.2f)", m.From, m.To, len(m.Path), m.Cost)
+}
+
+// Vertex define a node in the generated tree.
+type Vertex struct {
+	State  State
+	Parent *Vertex
+	Motion *Motion // motion from the parent, nil for the root
+	Cost   float64 // cost from the root
+}
+
+// Progress is the best solution cost after an iteration of a planner that
+// keeps improving its solution. Such planners record the cost before the first
+// iteration, which is +Inf, and after every iteration where it improved.
+type Progress struct {
+	Iteration int
+	Elapsed   time.Duration
+	Cost      float64
+}
+
+// Stats counts the work done by a planner.
+type Stats struct {
+	Iterations   int
+	Elapsed      time.Duration
+	StateChecks  int // calls to CollisionChecker.Valid
+	MotionChecks int // calls to CollisionChecker.MotionValid
+}
+
+// Solution is the result of a planner.
+type Solution struct {
+	// Path holds the motions from the start to the goal. The tree
+	// planners return at least one motion even if the start is in the goal
+	// region, only TangentGraph returns none.
+	Path     []*Motion
+	Tree     []*Motion // every motion in the final tree
+	Cost     float64
+	Progress []Progress // empty for planners that stop at the first solution
+	Stats    Stats
+}
+
+// countingChecker counts the checks made through it in stats.
+type countingChecker struct {
+	CollisionChecker
+	stats *Stats
+}
+
+func (c countingChecker) Valid(s State) bool {
+	c.stats.StateChecks++
+	return c.CollisionChecker.Valid(s)
+}
+
+func (c countingChecker) MotionValid(m *Motion) bool {
+	c.stats.MotionChecks++
+	return c.CollisionChecker.MotionValid(m)
+}
+
+// nearest naively searches for the vertex closest to state u.
+// Runtime: O(n) where n are number of vertices in list.
+func nearest(space StateSpace, vertices []*Vertex, u State) *Vertex {
+	var closest *Vertex
+	shortest := math.MaxFloat64
+	for _, v := range vertices {
+		d := space.Distance(u, v.State)
+		if d < shortest {
+			closest = v
+			shortest = d
+		}
+	}
+	return closest
+}
+
+// backtrack returns the motions from the root of the tree to vertex w.
+func backtrack(w *Vertex) []*Motion {
+	var path []*Motion
+	for current := w; current.Parent != nil; current = current.Parent {
+		path = append(path, current.Motion)
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
+
+// Pose returns the position, heading and velocities of a 2D state.
+func Pose(s State) Point {
+	switch p := s.(type) {
+	case Point:
+		return p
+	case *Point:
+		return *p
+	case PathPoint:
+		return p.Point
+	case *PathPoint:
+		return p.Point
+	}
+	panic(fmt.Sprintf("planner: %T is not a 2D state", s))
+}
@@ -0,0 +1,220 @@
+package planner
+
+import (
+	"encoding/json"
+	"io"
+	"math"
+
+	"github.com/pkg/errors"
+
+	"github.com/hdhauk/enae788v/angle"
+)
+
+// latticeVectors are the directions of the lattice headings. Straight motions
+// along them end exactly on lattice points, which the evenly spaced headings
+// between them would not.
+var latticeVectors = [][2]int{
+	{1, 0}, {2, 1}, {1, 1}, {1, 2}, {0, 1}, {-1, 2}, {-1, 1}, {-2, 1},
+	{-1, 0}, {-2, -1}, {-1, -1}, {-1, -2}, {0, -1}, {1, -2}, {1, -1}, {2, -1},
+}
+
+// PrimitiveParams configures the generation of motion primitives.
+type PrimitiveParams struct {
+	Resolution  float64 // distance between lattice points in x and y
+	SpeedStep   float64 // distance between lattice speeds
+	MaxDuration float64 // longest primitive in seconds
+	Tolerance   float64 // largest distance from the end of a primitive to its lattice point
+}
+
+// DefaultPrimitiveParams returns parameters that work for the problems in
+// hw4/problems.json.
+func DefaultPrimitiveParams() PrimitiveParams {
+	return PrimitiveParams{Resolution: 1, SpeedStep: 1, MaxDuration: 3, Tolerance: 0.1}
+}
+
+// Primitives is a library of motion primitives for a state lattice. The
+// lattice states are on a grid of Resolution in x and y, have one of the
+// Headings, a speed that is a multiple of SpeedStep, and no angular rate.
+// Every primitive starts at a lattice state at the origin and ends at a
+// lattice state, exactly in heading and speed, and within the tolerance it
+// was generated with in x and y.
+type Primitives struct {
+	Resolution float64     `json:"resolution"`
+	SpeedStep  float64     `json:"speed_step"`
+	Timestep   float64     `json:"timestep"` // the Timestep the primitives were simulated with
+	Headings   []float64   `json:"headings"`
+	Space      ConfigSpace `json:"config_space"` // the limits the primitives were generated for
+	Primitives []Primitive `json:"primitives"`
+}
+
+// Primitive is a motion primitive: controls applied from the start heading
+// and speed, and the lattice offset of its end.
+type Primitive struct {
+	Heading    int       `json:"heading"` // index in Headings
+	Speed      int       `json:"speed"`   // in SpeedSteps
+	Controls   []Control `json:"controls"`
+	DX         int       `json:"dx"` // in lattice points
+	DY         int       `json:"dy"`
+	EndHeading int       `json:"end_heading"`
+	EndSpeed   int       `json:"end_speed"`
+	Duration   float64   `json:"duration"`
+}
+
+// GeneratePrimitives forward simulates the controls of primitives from every
+// lattice heading and speed within the limits of cSpace, and keeps those that
+// end on the lattice. Every primitive changes the speed by -1, 0 or 1
+// SpeedStep with a constant acceleration, and turns by up to two headings
+// with a bang-bang angular acceleration that is exact in heading, before or
+// after a straight part. For every change of speed and heading the shortest
+// primitive that ends within the tolerance of a lattice point is kept. At
+// rest, the primitives turn in place.
+func GeneratePrimitives(cSpace ConfigSpace, params PrimitiveParams) *Primitives {
+	lib := &Primitives{
+		Resolution: params.Resolution,
+		SpeedStep:  params.SpeedStep,
+		Timestep:   Timestep,
+		Space:      cSpace,
+	}
+	for _, d := range latticeVectors {
+		lib.Headings = append(lib.Headings, math.Atan2(float64(d[1]), float64(d[0])))
+	}
+	speeds := int(math.Round(topSpeed(cSpace, params.SpeedStep) / params.SpeedStep))
+	for heading := range lib.Headings {
+		for speed := -speeds; speed <= speeds; speed++ {
+			for dv := -1; dv <= 1; dv++ {
+				if speed+dv < -speeds || speed+dv > speeds {
+					continue
+				}
+				for dk := -2; dk <= 2; dk++ {
+					if p := lib.generate(cSpace, params, heading, speed, dv, dk); p != nil {
+						lib.Primitives = append(lib.Primitives, *p)
+					}
+				}
+			}
+		}
+	}
+	return lib
+}
+
+// generate returns the shortest primitive from heading and speed that changes
+// the speed by dv and the heading by dk, or nil if there is none.
+func (lib *Primitives) generate(cSpace ConfigSpace, params PrimitiveParams, heading, speed, dv, dk int) *Primitive {
+	if speed == 0 && dv == 0 && dk == 0 {
+		return nil
+	}
+	h, res := Timestep, params.Resolution
+	aMax, gammaMax, wMax := limit(cSpace.AMin, cSpace.AMax), limit(cSpace.GammaMin, cSpace.GammaMax), limit(cSpace.WMin, cSpace.WMax)
+	n := len(lib.Headings)
+	end := ((heading+dk)%n + n) % n
+	turn := angle.Diff(lib.Headings[heading], lib.Headings[end])
+	start := &PathPoint{Point: Point{Theta: lib.Headings[heading], V: float64(speed) * params.SpeedStep}}
+	inPlace := speed == 0 && dv == 0
+
+	for steps := 1; float64(steps)*h <= params.MaxDuration+1e-9; steps++ {
+		a := float64(dv) * params.SpeedStep / (float64(steps) * h)
+		if math.Abs(a) > aMax {
+			continue
+		}
+		var best *Primitive
+		bestErr := math.Inf(1)
+		try := func(controls []Control) {
+			m := follow(start, controls)
+			to := Pose(m.To)
+			dx, dy := int(math.Round(to.X/res)), int(math.Round(to.Y/res))
+			e := math.Hypot(to.X-float64(dx)*res, to.Y-float64(dy)*res)
+			if e > params.Tolerance || e >= bestErr || (dx == 0 && dy == 0) != inPlace {
+				return
+			}
+			best, bestErr = &Primitive{
+				Heading:    heading,
+				Speed:      speed,
+				Controls:   controls,
+				DX:         dx,
+				DY:         dy,
+				EndHeading: end,
+				EndSpeed:   speed + dv,
+				Duration:   m.Cost,
+			}, e
+		}
+
+		// The heading changes by gamma·h²·half² over the two halves of the
+		// turn, whatever the speed.
+		for half := 0; 2*half <= steps; half++ {
+			straight := steps - 2*half
+			if (half == 0) != (dk == 0) || (inPlace && straight > 0) {
+				continue
+			}
+			var gamma float64
+			if half > 0 {
+				gamma = turn / (h * h * float64(half) * float64(half))
+			}
+			if math.Abs(gamma) > gammaMax || math.Abs(gamma)*float64(half)*h > wMax {
+				continue
+			}
+			turning := []Control{{a, gamma, half}, {a, -gamma, half}}
+			if half == 0 {
+				turning = nil
+			}
+			try(append(turning, Control{a, 0, straight}))
+			if half > 0 && straight > 0 {
+				try(append([]Control{{a, 0, straight}}, turning...))
+			}
+		}
+		if best != nil {
+			best.Controls = compact(best.Controls)
+			return best
+		}
+	}
+	return nil
+}
+
+// compact drops the controls that are applied for no steps.
+func compact(controls []Control) []Control {
+	var out []Control
+	for _, c := range controls {
+		if c.Steps > 0 {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// fits returns true if the primitives stay within the velocity and control
+// limits of cSpace.
+func (lib *Primitives) fits(cSpace ConfigSpace) bool {
+	s := lib.Space
+	return cSpace.VMin <= s.VMin && s.VMax <= cSpace.VMax &&
+		cSpace.WMin <= s.WMin && s.WMax <= cSpace.WMax &&
+		cSpace.AMin <= s.AMin && s.AMax <= cSpace.AMax &&
+		cSpace.GammaMin <= s.GammaMin && s.GammaMax <= cSpace.GammaMax
+}
+
+// WritePrimitives writes a primitive library as JSON.
+func WritePrimitives(w io.Writer, lib *Primitives) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return errors.Wrap(enc.Encode(lib), "could not write primitives")
+}
+
+// ReadPrimitives reads a primitive library written by WritePrimitives. It
+// returns an error if the library was simulated with another Timestep or its
+// primitives refer to headings it does not have.
+func ReadPrimitives(r io.Reader) (*Primitives, error) {
+	var lib Primitives
+	if err := json.NewDecoder(r).Decode(&lib); err != nil {
+		return nil, errors.Wrap(err, "could not decode primitives")
+	}
+	if lib.Timestep != Timestep {
+		return nil, errors.Errorf("primitives were simulated with timestep %v, not %v", lib.Timestep, Timestep)
+	}
+	if lib.Resolution <= 0 || lib.SpeedStep <= 0 || len(lib.Headings) == 0 {
+		return nil, errors.New("primitives need a positive resolution and speed step, and headings")
+	}
+	for i, p := range lib.Primitives {
+		n := len(lib.Headings)
+		if p.Heading < 0 || p.Heading >= n || p.EndHeading < 0 || p.EndHeading >= n {
+			return nil, errors.Errorf("primitive %d has an unknown heading", i)
+		}
+	}
+	return &lib, nil
+}
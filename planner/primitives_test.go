@@ -0,0 +1,68 @@
+package planner
+
+import (
+	"bytes"
+	"math"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGeneratePrimitives(t *testing.T) {
+	config, _, _, err := LoadConfig(filepath.Join("..", "hw4", "problems.json"))
+	ok(t, err)
+	cSpace, params := config.ConfigSpace, DefaultPrimitiveParams()
+	lib := GeneratePrimitives(cSpace, params)
+	equals(t, len(latticeVectors), len(lib.Headings))
+
+	groups := map[[2]int]int{}
+	for i, p := range lib.Primitives {
+		groups[[2]int{p.Heading, p.Speed}]++
+		start := &PathPoint{Point: Point{Theta: lib.Headings[p.Heading], V: float64(p.Speed) * lib.SpeedStep}}
+		m := follow(start, p.Controls)
+		for _, s := range m.Path {
+			q := s.(*PathPoint)
+			assert(t, cSpace.AMin < q.A && q.A < cSpace.AMax && cSpace.GammaMin < q.Gamma && q.Gamma < cSpace.GammaMax, "primitive %d: controls %f, %f out of limits", i, q.A, q.Gamma)
+			assert(t, cSpace.VMin < q.V && q.V < cSpace.VMax && cSpace.WMin < q.W && q.W < cSpace.WMax, "primitive %d: velocities %f, %f out of limits", i, q.V, q.W)
+		}
+		end := Pose(m.To)
+		assert(t, math.Abs(math.Remainder(end.Theta-lib.Headings[p.EndHeading], 2*math.Pi)) < 1e-9, "primitive %d: should end at heading %f, got %f", i, lib.Headings[p.EndHeading], end.Theta)
+		assert(t, math.Abs(end.V-float64(p.EndSpeed)*lib.SpeedStep) < 1e-9 && math.Abs(end.W) < 1e-9, "primitive %d: should end at speed %d and no angular rate, got %f, %f", i, p.EndSpeed, end.V, end.W)
+		miss := math.Hypot(end.X-float64(p.DX)*lib.Resolution, end.Y-float64(p.DY)*lib.Resolution)
+		assert(t, miss <= params.Tolerance, "primitive %d: ends %f from its lattice point", i, miss)
+		assert(t, math.Abs(m.Cost-p.Duration) < 1e-9 && p.Duration <= params.MaxDuration+1e-9, "primitive %d: duration %f", i, p.Duration)
+	}
+
+	// Every lattice state can speed up, slow down and turn both ways, and at
+	// rest the robot turns in place.
+	for heading := range lib.Headings {
+		for speed := -4; speed <= 4; speed++ {
+			assert(t, groups[[2]int{heading, speed}] >= 6, "heading %d, speed %d has %d primitives", heading, speed, groups[[2]int{heading, speed}])
+		}
+	}
+	equals(t, 0, groups[[2]int{0, 5}])
+	for _, p := range lib.Primitives {
+		if p.Speed == 0 && p.EndSpeed == 0 {
+			equals(t, 0, p.DX)
+			equals(t, 0, p.DY)
+		}
+	}
+}
+
+func TestPrimitivesRoundTrip(t *testing.T) {
+	cSpace := ConfigSpace{XMin: 0, XMax: 30, YMin: 0, YMax: 30, VMin: -2, VMax: 2, WMin: -1.5, WMax: 1.5, AMin: -2, AMax: 2, GammaMin: -1.5, GammaMax: 1.5}
+	lib := GeneratePrimitives(cSpace, DefaultPrimitiveParams())
+	var buf bytes.Buffer
+	ok(t, WritePrimitives(&buf, lib))
+	read, err := ReadPrimitives(bytes.NewReader(buf.Bytes()))
+	ok(t, err)
+	equals(t, lib, read)
+
+	for name, replace := range map[string][2]string{
+		"timestep": {`"timestep": 0.1`, `"timestep": 0.05`},
+		"heading":  {`"end_heading": 0`, `"end_heading": 16`},
+	} {
+		_, err := ReadPrimitives(strings.NewReader(strings.Replace(buf.String(), replace[0], replace[1], 1)))
+		assert(t, err != nil, "%s: expected an error", name)
+	}
+}
@@ -0,0 +1,51 @@
+package planner
+
+import (
+	"container/heap"
+	"math"
+)
+
+// queueItem is a vertex, or an edge between two vertices, in a queue.
+type queueItem struct {
+	key      float64
+	from, to *Vertex // to is nil for a vertex
+}
+
+// queue is a priority queue of vertices or edges ordered by increasing key.
+// It implements heap.Interface.
+type queue []queueItem
+
+func (q queue) Len() int           { return len(q) }
+func (q queue) Less(i, j int) bool { return q[i].key < q[j].key }
+func (q queue) Swap(i, j int)      { q[i], q[j] = q[j], q[i] }
+
+// Push is used by the heap package. Use push instead.
+func (q *queue) Push(x interface{}) {
+	*q = append(*q, x.(queueItem))
+}
+
+// Pop is used by the heap package. Use pop instead.
+func (q *queue) Pop() interface{} {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
+
+// push adds a vertex, or the edge from from to to if to is non-nil.
+func (q *queue) push(key float64, from, to *Vertex) {
+	heap.Push(q, queueItem{key, from, to})
+}
+
+// pop removes and returns the item with the lowest key.
+func (q *queue) pop() queueItem {
+	return heap.Pop(q).(queueItem)
+}
+
+// top returns the lowest key, which is infinite if the queue is empty.
+func (q queue) top() float64 {
+	if len(q) == 0 {
+		return math.Inf(1)
+	}
+	return q[0].key
+}
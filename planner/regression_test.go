@@ -0,0 +1,249 @@
+package planner
+
+import (
+	"context"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// golden runs a planner on every problem of a homework with fixed seeds. The
+// success rate and mean cost of every problem must stay within the recorded
+// thresholds, which leave room for changes that sample differently but
+// should catch planners that get worse.
+type golden struct {
+	name       string
+	hw         string
+	seeds      int
+	minSuccess []float64 // fraction of seeds solved of each problem
+	maxCost    []float64 // mean cost of the solved seeds of each problem
+	resolution float64   // of the footprint checker, zero if it is exact
+	solve      func(prob Problem, cSpace ConfigSpace, obstacles []Circle, robot Robot, seed int64) (*Solution, error)
+}
+
+// footprintResolution is the distance between the checks of the footprint
+// checkers.
+const footprintResolution = 0.5
+
+var goldens = []golden{
+	{
+		name: "hw2 rrt", hw: "hw2", seeds: 10,
+		minSuccess: []float64{1, 1, 1, 1, 1, 1, 1},
+		maxCost:    []float64{125, 195, 465, 470, 230, 470, 230},
+		solve: func(prob Problem, cSpace ConfigSpace, obstacles []Circle, _ Robot, seed int64) (*Solution, error) {
+			rrt := NewRRT(prob, cSpace, PointChecker{Obstacles: obstacles, Space: cSpace}, seed)
+			rrt.MaxIterations = 50000
+			return rrt.Plan(context.Background())
+		},
+	},
+	{
+		name: "hw3 rrt", hw: "hw3", seeds: 10, resolution: footprintResolution,
+		minSuccess: []float64{1, 1, 1, 1, 1, 1},
+		maxCost:    []float64{190, 13, 125, 150, 255, 200},
+		solve: func(prob Problem, cSpace ConfigSpace, obstacles []Circle, robot Robot, seed int64) (*Solution, error) {
+			checker := FootprintChecker{Obstacles: obstacles, Space: cSpace, Robot: robot, Resolution: footprintResolution}
+			rrt := NewSE2RRT(prob, cSpace, checker, seed, robot.SE2())
+			rrt.MaxIterations = 50000
+			return rrt.Plan(context.Background())
+		},
+	},
+	{
+		name: "hw3 fmtstar", hw: "hw3", seeds: 2, resolution: footprintResolution,
+		minSuccess: []float64{1, 1, 1, 1, 1, 1},
+		maxCost:    []float64{120, 2, 90, 92, 157, 120},
+		solve: func(prob Problem, cSpace ConfigSpace, obstacles []Circle, robot Robot, seed int64) (*Solution, error) {
+			checker := FootprintChecker{Obstacles: obstacles, Space: cSpace, Robot: robot, Resolution: footprintResolution}
+			return NewFMTStar(prob, cSpace, checker, seed, FMTStarParams{Samples: 2000, SE2: robot.SE2()}).Plan(context.Background())
+		},
+	},
+	{
+		name: "hw3 bitstar", hw: "hw3", seeds: 2, resolution: footprintResolution,
+		minSuccess: []float64{1, 1, 1, 1, 1, 1},
+		maxCost:    []float64{117, 1, 89, 92, 158, 121},
+		solve: func(prob Problem, cSpace ConfigSpace, obstacles []Circle, robot Robot, seed int64) (*Solution, error) {
+			checker := FootprintChecker{Obstacles: obstacles, Space: cSpace, Robot: robot, Resolution: footprintResolution}
+			params := BITStarParams{Budget: time.Minute, BatchSize: 100, MaxIterations: 5000, SE2: robot.SE2()}
+			return NewBITStar(prob, cSpace, checker, seed, params).Plan(context.Background())
+		},
+	},
+	{
+		name: "hw4 rrt", hw: "hw4", seeds: 2,
+		minSuccess: []float64{1, 1, 1, 1, 1, 1},
+		maxCost:    []float64{210, 180, 130, 150, 375, 305},
+		solve: func(prob Problem, cSpace ConfigSpace, obstacles []Circle, robot Robot, seed int64) (*Solution, error) {
+			checker := KinodynamicChecker{FootprintChecker{Obstacles: obstacles, Space: cSpace, Robot: robot}}
+			rrt := NewKinodynamicRRT(prob, cSpace, checker, seed)
+			rrt.MaxIterations = 50000
+			return rrt.Plan(context.Background())
+		},
+	},
+	{
+		name: "hw4 sst", hw: "hw4", seeds: 2,
+		minSuccess: []float64{1, 1, 1, 1, 1, 1},
+		maxCost:    []float64{95, 90, 110, 80, 130, 125},
+		solve: func(prob Problem, cSpace ConfigSpace, obstacles []Circle, robot Robot, seed int64) (*Solution, error) {
+			checker := KinodynamicChecker{FootprintChecker{Obstacles: obstacles, Space: cSpace, Robot: robot}}
+			params := SSTParams{Budget: time.Minute, DeltaBN: 2, DeltaS: 1, MaxPropTime: 2, Cost: DurationCost, MaxIterations: 20000}
+			return NewSST(prob, cSpace, checker, seed, params).Plan(context.Background())
+		},
+	},
+}
+
+func TestGoldenSeeds(t *testing.T) {
+	if testing.Short() {
+		t.Skip("planning every shipped problem takes a while")
+	}
+	for _, g := range goldens {
+		g := g
+		t.Run(g.name, func(t *testing.T) {
+			config, obstacles, robot := loadGolden(t, g.hw)
+			equals(t, len(config.Problems), len(g.minSuccess))
+			equals(t, len(config.Problems), len(g.maxCost))
+			for i, prob := range config.Problems {
+				var solved int
+				var cost float64
+				for seed := int64(1); seed <= int64(g.seeds); seed++ {
+					sol, err := g.solve(prob, config.ConfigSpace, obstacles, robot, seed)
+					if err != nil {
+						continue
+					}
+					solved++
+					cost += sol.Cost
+					sweepPath(t, prob, config.ConfigSpace, obstacles, robot, g.resolution, sol.Path)
+				}
+				success := float64(solved) / float64(g.seeds)
+				t.Logf("problem %d: solved %d/%d, mean cost %.1f", i, solved, g.seeds, cost/float64(solved))
+				assert(t, success >= g.minSuccess[i], "problem %d: success rate %.2f below %.2f", i, success, g.minSuccess[i])
+				if solved > 0 {
+					mean := cost / float64(solved)
+					assert(t, mean <= g.maxCost[i], "problem %d: mean cost %.1f above %.1f", i, mean, g.maxCost[i])
+				}
+			}
+		})
+	}
+}
+
+// loadGolden loads the problems of a homework: those of problems.json,
+// followed by the examples of problems_constraints.json if it has one, which
+// share its obstacles and robot.
+func loadGolden(t *testing.T, hw string) (*Config, []Circle, Robot) {
+	config, obstacles, robot, err := LoadConfig(filepath.Join("..", hw, "problems.json"))
+	ok(t, err)
+	path := filepath.Join("..", hw, "problems_constraints.json")
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return config, obstacles, robot
+	}
+	examples, _, _, err := LoadConfig(path)
+	ok(t, err)
+	equals(t, config.ConfigSpace, examples.ConfigSpace)
+	config.Problems = append(config.Problems, examples.Problems...)
+	return config, obstacles, robot
+}
+
+// sweepPath fails the test unless path is a connected path from the start of
+// prob to its goal region, along which the robot stays inside the config
+// space and outside every obstacle. It does not share any code with the
+// collision checkers: straight motions are swept in steps that move no point
+// of the footprint more than 0.05, while the heading turns along the shortest
+// arc, and other motions are checked at every state along them. A checker
+// that moves the footprint resolution between checks can let it cut into an
+// obstacle by the sagitta of a chord of that length, which is allowed.
+func sweepPath(t testing.TB, prob Problem, cSpace ConfigSpace, obstacles []Circle, robot Robot, resolution float64, path []*Motion) {
+	const step, tolerance = 0.05, 1e-6
+	assert(t, len(path) > 0, "expected a path")
+
+	type pose struct{ x, y, theta float64 }
+	poseOf := func(s State) pose {
+		switch s := s.(type) {
+		case Point:
+			return pose{s.X, s.Y, s.Theta}
+		case *PathPoint:
+			return pose{s.X, s.Y, s.Theta}
+		}
+		t.Fatalf("unknown state %T", s)
+		return pose{}
+	}
+	footprint := robot
+	if footprint == nil {
+		footprint = Robot{{}}
+	}
+	var reach float64
+	for _, offset := range footprint {
+		reach = math.Max(reach, math.Hypot(offset.X, offset.Y))
+	}
+	check := func(p pose) {
+		sin, cos := math.Sincos(p.theta)
+		for _, offset := range footprint {
+			x := p.x + offset.X*cos - offset.Y*sin
+			y := p.y + offset.X*sin + offset.Y*cos
+			inside := cSpace.XMin-tolerance <= x && x <= cSpace.XMax+tolerance && cSpace.YMin-tolerance <= y && y <= cSpace.YMax+tolerance
+			assert(t, inside, "robot at (%.3f, %.3f, %.3f) leaves the config space at (%.3f, %.3f)", p.x, p.y, p.theta, x, y)
+			for _, o := range obstacles {
+				d, sagitta := math.Hypot(x-o.X, y-o.Y), resolution*resolution/(8*o.R)
+				assert(t, d >= o.R-sagitta-tolerance, "robot at (%.3f, %.3f, %.3f) hits obstacle %v at (%.3f, %.3f)", p.x, p.y, p.theta, o, x, y)
+			}
+		}
+	}
+
+	start := poseOf(path[0].From)
+	assert(t, start.x == prob.Start.X && start.y == prob.Start.Y, "path starts at (%.3f, %.3f) instead of the start", start.x, start.y)
+	for i, m := range path {
+		if i > 0 {
+			assert(t, poseOf(path[i-1].To) == poseOf(m.From), "motion %d does not start where motion %d ends", i, i-1)
+		}
+		if len(m.Path) > 0 {
+			for _, s := range m.Path {
+				check(poseOf(s))
+			}
+			continue
+		}
+		a, b := poseOf(m.From), poseOf(m.To)
+		turn := math.Remainder(b.theta-a.theta, 2*math.Pi)
+		n := int(math.Max(1, math.Ceil((math.Hypot(b.x-a.x, b.y-a.y)+reach*math.Abs(turn))/step)))
+		for k := 0; k <= n; k++ {
+			f := float64(k) / float64(n)
+			check(pose{a.x + f*(b.x-a.x), a.y + f*(b.y-a.y), a.theta + f*turn})
+		}
+	}
+	end := poseOf(path[len(path)-1].To)
+	g := prob.Goal
+	assert(t, math.Hypot(end.x-g.X, end.y-g.Y) < g.R, "path ends at (%.3f, %.3f) outside the goal region", end.x, end.y)
+}
+
+// failNow stops a test where sweepPath is expected to fail.
+type failNow struct{ testing.TB }
+
+func (failNow) FailNow() { panic(failNow{}) }
+
+func (f failNow) Fatalf(format string, args ...interface{}) { f.FailNow() }
+
+// sweepFails returns true if sweepPath fails for path.
+func sweepFails(t *testing.T, prob Problem, cSpace ConfigSpace, obstacles []Circle, robot Robot, path []*Motion) (failed bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			if _, ok := r.(failNow); !ok {
+				panic(r)
+			}
+			failed = true
+		}
+	}()
+	sweepPath(failNow{t}, prob, cSpace, obstacles, robot, 0, path)
+	return false
+}
+
+func TestSweepPath(t *testing.T) {
+	cSpace := ConfigSpace{XMin: 0, XMax: 20, YMin: 0, YMax: 20}
+	bar := Robot{{X: 5}, {X: 2.5}, {X: -2.5}, {X: -5}}
+	prob := Problem{Start: Point{X: 10, Y: 10}, Goal: Circle{X: 12, Y: 10, R: 1}}
+	turn := &Motion{From: Point{X: 10, Y: 10}, To: Point{X: 10, Y: 10, Theta: math.Pi / 2}}
+	ahead := &Motion{From: turn.To, To: Point{X: 12, Y: 10, Theta: math.Pi / 2}}
+	path := []*Motion{turn, ahead}
+
+	// Both ends of the turn in place are clear of the obstacle, which is
+	// only swept halfway through it.
+	halfway := []Circle{{X: 10 + 5*math.Cos(math.Pi/4), Y: 10 + 5*math.Sin(math.Pi/4), R: 1}}
+	assert(t, sweepFails(t, prob, cSpace, halfway, bar, path), "the turn in place should hit the obstacle")
+	assert(t, !sweepFails(t, prob, cSpace, []Circle{{X: 3, Y: 3, R: 1}}, bar, path), "the path should be clear")
+}
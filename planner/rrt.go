@@ -0,0 +1,127 @@
+package planner
+
+import (
+	"context"
+	"math/rand"
+	"time"
+)
+
+// RRT builds a tree and finds a feasible path using the RRT algorithm. It
+// stops at the first vertex that satisfies the goal.
+type RRT struct {
+	Start         State
+	Space         StateSpace
+	Sampler       Sampler
+	Steerer       Steerer
+	Checker       CollisionChecker
+	Goal          Goal
+	GoalBias      float64 // probability of sampling from the goal
+	MaxIterations int     // give up after this many iterations if non-zero
+	MaxNodes      int     // give up when the tree has this many vertices if non-zero
+	Rand          *rand.Rand
+
+	// OnAdd is called with every vertex added to the tree, starting with the
+	// root, if non-nil. It runs on the planning goroutine and slows down the
+	// planner by the time it takes.
+	OnAdd func(v *Vertex)
+}
+
+// NewRRT returns an RRT for a geometric robot that moves in straight lines,
+// such as the point robot of hw2, ignoring the heading.
+func NewRRT(prob Problem, cSpace ConfigSpace, checker CollisionChecker, seed int64) *RRT {
+	return NewSE2RRT(prob, cSpace, checker, seed, SE2{})
+}
+
+// NewSE2RRT returns an RRT for a geometric robot that moves in straight lines
+// and turns, such as the robot with a footprint of hw3, with the metric and
+// turn limit of space.
+func NewSE2RRT(prob Problem, cSpace ConfigSpace, checker CollisionChecker, seed int64, space SE2) *RRT {
+	return &RRT{
+		Start:    prob.Start,
+		Space:    space,
+		Sampler:  UniformSampler{cSpace},
+		Steerer:  space.StraightLine(prob.Epsilon, prob.AllowSmallSteps),
+		Checker:  checker,
+		Goal:     NewGoalRegion(prob, cSpace),
+		GoalBias: prob.GoalBias,
+		Rand:     rand.New(rand.NewSource(seed)),
+	}
+}
+
+// NewKinodynamicRRT returns an RRT for the robot with dynamics of hw4. The
+// states of the tree are *PathPoint, and the motions are forward simulated.
+func NewKinodynamicRRT(prob Problem, cSpace ConfigSpace, checker CollisionChecker, seed int64) *RRT {
+	return &RRT{
+		Start:    &PathPoint{Point: prob.Start},
+		Space:    Plane{},
+		Sampler:  UniformSampler{cSpace},
+		Steerer:  Kinodynamic{Space: cSpace, Epsilon: prob.Epsilon},
+		Checker:  checker,
+		Goal:     NewGoalRegion(prob, cSpace),
+		GoalBias: prob.GoalBias,
+		Rand:     rand.New(rand.NewSource(seed)),
+	}
+}
+
+// Plan grows the tree until a vertex satisfies the goal. It gives up when
+// ctx is done or a limit is reached.
+func (p *RRT) Plan(ctx context.Context) (*Solution, error) {
+	root := &Vertex{State: p.Start}
+	vertices := []*Vertex{root}
+	tree := []*Motion{}
+
+	var stats Stats
+	checker := countingChecker{p.Checker, &stats}
+	started := time.Now()
+	fail := func(reason string) (*Solution, error) {
+		stats.Elapsed = time.Since(started)
+		return noSolution(reason, stats, tree)
+	}
+
+	if !checker.Valid(p.Start) {
+		return fail("start is in collision or outside the config space")
+	}
+	p.added(root)
+	for {
+		if err := ctx.Err(); err != nil {
+			return fail(err.Error())
+		}
+		if p.MaxIterations > 0 && stats.Iterations >= p.MaxIterations {
+			return fail("iteration limit reached")
+		}
+		if p.MaxNodes > 0 && len(vertices) >= p.MaxNodes {
+			return fail("node limit reached")
+		}
+		stats.Iterations++
+
+		var u State
+		if p.Rand.Float64() < p.GoalBias {
+			u = p.Goal.Sample(p.Rand)
+		} else {
+			u = p.Sampler.Sample(p.Rand)
+		}
+		v := nearest(p.Space, vertices, u)
+		m := p.Steerer.Steer(v.State, u)
+
+		// Discard the motion if it is unsafe.
+		if m == nil || !checker.MotionValid(m) {
+			continue
+		}
+
+		w := &Vertex{State: m.To, Parent: v, Motion: m, Cost: v.Cost + m.Cost}
+		vertices = append(vertices, w)
+		tree = append(tree, m)
+		p.added(w)
+
+		if p.Goal.Satisfied(w.State) {
+			stats.Elapsed = time.Since(started)
+			return &Solution{Path: backtrack(w), Tree: tree, Cost: w.Cost, Stats: stats}, nil
+		}
+	}
+}
+
+func (p *RRT) added(v *Vertex) {
+	if p.OnAdd != nil {
+		p.OnAdd(v)
+	}
+}
@@ -0,0 +1,162 @@
+package planner
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"path/filepath"
+	"testing"
+)
+
+func TestRRT(t *testing.T) {
+	cSpace := ConfigSpace{XMin: 0, XMax: 50, YMin: 0, YMax: 50}
+	obstacles := []Circle{{X: 25, Y: 25, R: 10}}
+	prob := Problem{Start: Point{X: 5, Y: 5}, Goal: Circle{X: 45, Y: 45, R: 5}, Epsilon: 2, GoalBias: 0.05}
+	checker := PointChecker{Obstacles: obstacles, Space: cSpace}
+
+	solution, err := NewRRT(prob, cSpace, checker, 1).Plan(context.Background())
+	ok(t, err)
+	assert(t, len(solution.Path) > 0, "expected a path")
+	assert(t, len(solution.Tree) >= len(solution.Path), "path should be part of the tree")
+	equals(t, prob.Start, solution.Path[0].From)
+	assert(t, Near(Pose(solution.Path[len(solution.Path)-1].To), prob.Goal), "path should end in goal")
+
+	var cost float64
+	for i, m := range solution.Path {
+		assert(t, checker.MotionValid(m), "motion %d should be safe", i)
+		if i > 0 {
+			equals(t, solution.Path[i-1].To, m.From)
+		}
+		cost += m.Cost
+	}
+	equals(t, cost, solution.Cost)
+}
+
+func TestKinodynamicRRT(t *testing.T) {
+	cSpace := ConfigSpace{
+		XMin: 0, XMax: 30,
+		YMin: 0, YMax: 30,
+		VMin: -5, VMax: 5,
+		WMin: -1.5, WMax: 1.5,
+		AMin: -2, AMax: 2,
+		GammaMin: -1.5, GammaMax: 1.5,
+	}
+	prob := Problem{Start: Point{X: 5, Y: 5}, Goal: Circle{X: 20, Y: 20, R: 5}, Epsilon: 5, GoalBias: 0.05}
+	checker := KinodynamicChecker{FootprintChecker{Space: cSpace, Robot: Robot{{}}}}
+
+	solution, err := NewKinodynamicRRT(prob, cSpace, checker, 1).Plan(context.Background())
+	ok(t, err)
+	trajectory := Trajectory(solution.Path)
+	assert(t, len(trajectory) > 0, "expected a trajectory")
+	for _, p := range trajectory {
+		assert(t, checker.Valid(p), "trajectory should be safe")
+	}
+	last := trajectory[len(trajectory)-1]
+	assert(t, Near(last.Point, prob.Goal), "trajectory should end in goal")
+	assert(t, math.Abs(last.T-solution.Cost) < 1e-9, "cost %f should equal trajectory duration %f", solution.Cost, last.T)
+}
+
+func TestRRTNoSolution(t *testing.T) {
+	cSpace := ConfigSpace{XMin: 0, XMax: 50, YMin: 0, YMax: 50}
+	prob := Problem{Start: Point{X: 5, Y: 5}, Goal: Circle{X: 45, Y: 45, R: 2}, Epsilon: 2, GoalBias: 0.05}
+
+	// The goal is enclosed by an obstacle, so the search only stops at a limit.
+	enclosed := PointChecker{Obstacles: []Circle{{X: 45, Y: 45, R: 4}}, Space: cSpace}
+	rrt := NewRRT(prob, cSpace, enclosed, 1)
+	rrt.MaxIterations = 500
+	solution, err := rrt.Plan(context.Background())
+	noSol, isNoSol := err.(*NoSolutionError)
+	assert(t, isNoSol, "expected a *NoSolutionError, got %v", err)
+	equals(t, 500, noSol.Iterations)
+	assert(t, len(noSol.Tree) > 0, "expected the partial tree")
+	equals(t, noSol.Tree, solution.Tree)
+	equals(t, 0, len(solution.Path))
+
+	rrt = NewRRT(prob, cSpace, enclosed, 1)
+	rrt.MaxNodes = 20
+	_, err = rrt.Plan(context.Background())
+	noSol, isNoSol = err.(*NoSolutionError)
+	assert(t, isNoSol, "expected a *NoSolutionError, got %v", err)
+	equals(t, 19, len(noSol.Tree))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = NewRRT(prob, cSpace, enclosed, 1).Plan(ctx)
+	noSol, isNoSol = err.(*NoSolutionError)
+	assert(t, isNoSol, "expected a *NoSolutionError, got %v", err)
+	equals(t, context.Canceled.Error(), noSol.Reason)
+
+	blocked := PointChecker{Obstacles: []Circle{{X: 5, Y: 5, R: 1}}, Space: cSpace}
+	_, err = NewRRT(prob, cSpace, blocked, 1).Plan(context.Background())
+	_, isNoSol = err.(*NoSolutionError)
+	assert(t, isNoSol, "expected a *NoSolutionError for a start in collision, got %v", err)
+}
+
+func TestRRTOnAdd(t *testing.T) {
+	cSpace := ConfigSpace{XMin: 0, XMax: 50, YMin: 0, YMax: 50}
+	prob := Problem{Start: Point{X: 5, Y: 5}, Goal: Circle{X: 45, Y: 45, R: 5}, Epsilon: 2, GoalBias: 0.05}
+	rrt := NewRRT(prob, cSpace, PointChecker{Space: cSpace}, 1)
+
+	var added []*Vertex
+	rrt.OnAdd = func(v *Vertex) {
+		if len(added) == 0 {
+			assert(t, v.Parent == nil, "expected the root first")
+		} else {
+			assert(t, v.Parent != nil, "expected a parent")
+		}
+		added = append(added, v)
+	}
+	solution, err := rrt.Plan(context.Background())
+	ok(t, err)
+	equals(t, len(solution.Tree)+1, len(added))
+	for i, m := range solution.Tree {
+		assert(t, added[i+1].Motion == m, "vertex %d should be added with its motion", i+1)
+	}
+}
+
+// loadProblem loads problem i of the config of a homework for benchmarks.
+func loadProblem(tb testing.TB, hw string, i int) (Problem, ConfigSpace, []Circle, Robot) {
+	config, obstacles, robot, err := LoadConfig(filepath.Join("..", hw, "problems.json"))
+	ok(tb, err)
+	return config.Problems[i], config.ConfigSpace, obstacles, robot
+}
+
+func BenchmarkRRT(b *testing.B) {
+	prob, cSpace, obstacles, _ := loadProblem(b, "hw2", 0)
+	checker := PointChecker{Obstacles: obstacles, Space: cSpace}
+	for i := 0; i < b.N; i++ {
+		_, err := NewRRT(prob, cSpace, checker, int64(i)).Plan(context.Background())
+		ok(b, err)
+	}
+}
+
+func BenchmarkRRTFootprint(b *testing.B) {
+	prob, cSpace, obstacles, robot := loadProblem(b, "hw3", 0)
+	checker := FootprintChecker{Obstacles: obstacles, Space: cSpace, Robot: robot, Resolution: 0.5}
+	for i := 0; i < b.N; i++ {
+		_, err := NewRRT(prob, cSpace, checker, int64(i)).Plan(context.Background())
+		ok(b, err)
+	}
+}
+
+func BenchmarkKinodynamicRRT(b *testing.B) {
+	prob, cSpace, obstacles, robot := loadProblem(b, "hw4", 1)
+	checker := KinodynamicChecker{FootprintChecker{Obstacles: obstacles, Space: cSpace, Robot: robot}}
+	for i := 0; i < b.N; i++ {
+		_, err := NewKinodynamicRRT(prob, cSpace, checker, int64(i)).Plan(context.Background())
+		ok(b, err)
+	}
+}
+
+func BenchmarkNearest(b *testing.B) {
+	rng := rand.New(rand.NewSource(1))
+	sampler := UniformSampler{ConfigSpace{XMin: 0, XMax: 100, YMin: 0, YMax: 100}}
+	vertices := make([]*Vertex, 10000)
+	for i := range vertices {
+		vertices[i] = &Vertex{State: sampler.Sample(rng)}
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		nearest(Plane{}, vertices, sampler.Sample(rng))
+	}
+}
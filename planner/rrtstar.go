@@ -0,0 +1,278 @@
+package planner
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// RRTStarParams configures the RRT* planner.
+type RRTStarParams struct {
+	Budget        time.Duration // planning continues improving until the budget is spent
+	MaxIterations int           // stop after this many iterations if non-zero
+	MaxNodes      int           // stop when the tree has this many vertices if non-zero
+	Informed      bool          // sample the informed set and prune the tree once a path is found
+	SE2           SE2           // metric and turn limit of a robot with a heading, the zero value plans in the plane
+}
+
+// RRTStar finds paths for geometric robots that move in straight lines, such
+// as the robots of hw2 and hw3, and keeps shortening the best path with the
+// RRT* algorithm: every new vertex is connected through the neighbor that
+// gives it the shortest path, and neighbors are rewired through the new vertex
+// when that shortens their paths.
+//
+// Informed RRT* only samples the states that could shorten the best path once
+// one is found. Those lie in an ellipse with the start and goal center as
+// foci, since the straight line distance from the start and to the goal
+// region bound the length of any path through a state. Vertices that cannot
+// be part of a shorter path are pruned from the tree.
+type RRTStar struct {
+	Problem Problem
+	Space   ConfigSpace
+	Checker CollisionChecker
+	Params  RRTStarParams
+	Rand    *rand.Rand
+}
+
+// NewRRTStar returns an RRT* planner for the problem.
+func NewRRTStar(prob Problem, cSpace ConfigSpace, checker CollisionChecker, seed int64, params RRTStarParams) *RRTStar {
+	return &RRTStar{
+		Problem: prob,
+		Space:   cSpace,
+		Checker: checker,
+		Params:  params,
+		Rand:    rand.New(rand.NewSource(seed)),
+	}
+}
+
+// Plan grows and rewires the tree until the budget is spent, ctx is done or
+// a limit is reached. It returns the shortest path found along with the tree
+// and the best cost at every improvement.
+func (p *RRTStar) Plan(ctx context.Context) (*Solution, error) {
+	prob, cSpace, se2 := p.Problem, p.Space, p.Params.SE2
+	return planStar(ctx, starSpace{
+		start:    prob.Start,
+		space:    se2,
+		sampler:  UniformSampler{cSpace},
+		goal:     NewGoalRegion(prob, cSpace),
+		goalBias: prob.GoalBias,
+		steer:    se2.StraightLine(prob.Epsilon, prob.AllowSmallSteps),
+		connect:  se2.connector(),
+		radius: func(n int) float64 {
+			return connectionRadius(cSpace, n, prob.Epsilon)
+		},
+		sampleInformed: func(rng *rand.Rand, cost float64) State {
+			return sampleInformed(prob, cSpace, rng, cost)
+		},
+		lowerBound: func(s State) float64 {
+			return lowerBound(prob, s)
+		},
+	}, p.Params, p.Rand, p.Checker)
+}
+
+// starSpace holds the parts of RRT* that depend on the space the robot plans
+// in.
+type starSpace struct {
+	start          State
+	space          StateSpace
+	sampler        Sampler
+	goal           Goal
+	goalBias       float64 // probability of sampling from the goal
+	steer          Steerer
+	connect        Steerer             // connects two states exactly
+	radius         func(n int) float64 // radius of the neighborhood of a new vertex in a tree of n vertices
+	sampleInformed func(rng *rand.Rand, cost float64) State
+	lowerBound     func(s State) float64 // of the length of a path from the start to the goal through s
+}
+
+// planStar runs RRT* in the space s, and informed RRT* if params.Informed is
+// set.
+func planStar(ctx context.Context, s starSpace, params RRTStarParams, rng *rand.Rand, c CollisionChecker) (*Solution, error) {
+	root := &Vertex{State: s.start}
+	vertices := []*Vertex{root}
+	children := map[*Vertex][]*Vertex{}
+	var reached []*Vertex // vertices in the goal region
+
+	solution := &Solution{Cost: math.Inf(1), Progress: []Progress{{Cost: math.Inf(1)}}}
+	checker := countingChecker{c, &solution.Stats}
+	started := time.Now()
+	var deadline time.Time
+	if params.Budget > 0 {
+		deadline = started.Add(params.Budget)
+	}
+
+	var best *Vertex
+	reason := "time budget spent"
+	if !checker.Valid(s.start) {
+		solution.Stats.Elapsed = time.Since(started)
+		_, err := noSolution("start is in collision or outside the config space", solution.Stats, nil)
+		return solution, err
+	}
+	for i := 1; ; i++ {
+		if !deadline.IsZero() && !time.Now().Before(deadline) {
+			break
+		}
+		if err := ctx.Err(); err != nil {
+			reason = err.Error()
+			break
+		}
+		if params.MaxIterations > 0 && i > params.MaxIterations {
+			reason = "iteration limit reached"
+			break
+		}
+		if params.MaxNodes > 0 && len(vertices) >= params.MaxNodes {
+			reason = "node limit reached"
+			break
+		}
+		solution.Stats.Iterations = i
+
+		var u State
+		switch {
+		case best != nil && params.Informed:
+			u = s.sampleInformed(rng, best.Cost)
+		case rng.Float64() < s.goalBias:
+			u = s.goal.Sample(rng)
+		default:
+			u = s.sampler.Sample(rng)
+		}
+
+		if w := s.extend(vertices, children, u, checker); w != nil {
+			vertices = append(vertices, w)
+			if s.goal.Satisfied(w.State) {
+				reached = append(reached, w)
+			}
+		}
+
+		// Rewiring shortens the paths to vertices already in the goal region.
+		for _, v := range reached {
+			if best == nil || v.Cost < best.Cost {
+				best = v
+			}
+		}
+		if best != nil && best.Cost < solution.Cost {
+			solution.Cost = best.Cost
+			solution.Path = backtrack(best)
+			if params.Informed {
+				var removed map[*Vertex]bool
+				vertices, removed = prune(s.lowerBound, vertices, children, best.Cost)
+				reached = keep(reached, removed)
+			}
+			solution.Progress = append(solution.Progress, Progress{Iteration: i, Elapsed: time.Since(started), Cost: solution.Cost})
+		}
+	}
+
+	for _, v := range vertices {
+		if v.Motion != nil {
+			solution.Tree = append(solution.Tree, v.Motion)
+		}
+	}
+	solution.Stats.Elapsed = time.Since(started)
+	if best == nil {
+		_, err := noSolution(reason, solution.Stats, solution.Tree)
+		return solution, err
+	}
+	return solution, nil
+}
+
+// extend steers from the nearest vertex toward u, and adds the new state to
+// the tree through the neighbor that gives it the shortest path. Neighbors
+// that get shorter paths through the new vertex are rewired. It returns the
+// new vertex, or nil if the motion toward u is not safe.
+func (s starSpace) extend(vertices []*Vertex, children map[*Vertex][]*Vertex, u State, checker CollisionChecker) *Vertex {
+	space := s.space
+	nearestVertex := nearest(space, vertices, u)
+	m := s.steer.Steer(nearestVertex.State, u)
+	if m == nil || !checker.MotionValid(m) {
+		return nil
+	}
+
+	w := &Vertex{State: m.To, Parent: nearestVertex, Motion: m, Cost: nearestVertex.Cost + m.Cost}
+	radius := s.radius(len(vertices) + 1)
+	var near []*Vertex
+	for _, v := range vertices {
+		if v != nearestVertex && space.Distance(v.State, w.State) <= radius {
+			near = append(near, v)
+		}
+	}
+
+	for _, v := range near {
+		if c := v.Cost + space.Distance(v.State, w.State); c < w.Cost {
+			if m := s.connect.Steer(v.State, w.State); m != nil && checker.MotionValid(m) {
+				w.Parent, w.Motion, w.Cost = v, m, c
+			}
+		}
+	}
+	children[w.Parent] = append(children[w.Parent], w)
+
+	for _, v := range near {
+		if c := w.Cost + space.Distance(w.State, v.State); c < v.Cost {
+			m := s.connect.Steer(w.State, v.State)
+			if m == nil || !checker.MotionValid(m) {
+				continue
+			}
+			children[v.Parent] = without(children[v.Parent], v)
+			children[w] = append(children[w], v)
+			v.Parent, v.Motion = w, m
+			updateCost(children, v, c)
+		}
+	}
+	return w
+}
+
+// prune removes the vertices through which no path to the goal region is
+// shorter than cost by the lower bound, together with their descendants. It
+// returns the vertices left and the set of removed vertices.
+func prune(bound func(State) float64, vertices []*Vertex, children map[*Vertex][]*Vertex, cost float64) ([]*Vertex, map[*Vertex]bool) {
+	const slack = 1e-9
+	removed := map[*Vertex]bool{}
+	for _, v := range vertices {
+		if removed[v] || bound(v.State) <= cost+slack {
+			continue
+		}
+		children[v.Parent] = without(children[v.Parent], v)
+		for stack := []*Vertex{v}; len(stack) > 0; {
+			u := stack[len(stack)-1]
+			stack = append(stack[:len(stack)-1], children[u]...)
+			removed[u] = true
+			delete(children, u)
+		}
+	}
+	if len(removed) == 0 {
+		return vertices, removed
+	}
+	return keep(vertices, removed), removed
+}
+
+// keep returns the vertices that are not removed.
+func keep(vertices []*Vertex, removed map[*Vertex]bool) []*Vertex {
+	var kept []*Vertex
+	for _, v := range vertices {
+		if !removed[v] {
+			kept = append(kept, v)
+		}
+	}
+	return kept
+}
+
+// updateCost sets the cost of v and updates the costs of its descendants.
+func updateCost(children map[*Vertex][]*Vertex, v *Vertex, cost float64) {
+	delta := cost - v.Cost
+	stack := []*Vertex{v}
+	for len(stack) > 0 {
+		u := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		u.Cost += delta
+		stack = append(stack, children[u]...)
+	}
+}
+
+// without returns vs without v.
+func without(vs []*Vertex, v *Vertex) []*Vertex {
+	for i, u := range vs {
+		if u == v {
+			return append(vs[:i:i], vs[i+1:]...)
+		}
+	}
+	return vs
+}
@@ -0,0 +1,91 @@
+package planner
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// aroundObstacle returns a problem where the shortest path follows the
+// tangents from the start and the goal center around an obstacle, and the
+// length of that path.
+func aroundObstacle() (Problem, ConfigSpace, PointChecker, float64) {
+	cSpace := ConfigSpace{XMin: 0, XMax: 50, YMin: 0, YMax: 50}
+	obstacles := []Circle{{X: 25, Y: 25, R: 10}}
+	prob := Problem{Start: Point{X: 5, Y: 5}, Goal: Circle{X: 45, Y: 45, R: 5}, Epsilon: 2, GoalBias: 0.05}
+	tangent := math.Sqrt(math.Pow(math.Hypot(20, 20), 2) - 100)
+	arc := 10 * (math.Pi - 2*math.Acos(10/math.Hypot(20, 20)))
+	return prob, cSpace, PointChecker{Obstacles: obstacles, Space: cSpace}, 2*tangent + arc - prob.Goal.R
+}
+
+// checkShortPath fails the test unless the solution is a safe path from the
+// start to the goal region within 10% of the shortest, and its progress
+// starts at +Inf and records only improvements.
+func checkShortPath(t *testing.T, name string, prob Problem, checker CollisionChecker, shortest float64, solution *Solution) {
+	equals(t, prob.Start, solution.Path[0].From)
+	assert(t, Near(Pose(solution.Path[len(solution.Path)-1].To), prob.Goal), "%s: path should end in goal", name)
+	var cost float64
+	for i, m := range solution.Path {
+		assert(t, checker.MotionValid(m), "%s: motion %d should be safe", name, i)
+		if i > 0 {
+			equals(t, solution.Path[i-1].To, m.From)
+		}
+		cost += m.Cost
+	}
+	assert(t, math.Abs(cost-solution.Cost) < 1e-9, "%s: cost %f should equal path length %f", name, solution.Cost, cost)
+	assert(t, solution.Cost < 1.1*shortest, "%s: cost %f should be close to the shortest %f", name, solution.Cost, shortest)
+
+	progress := solution.Progress
+	equals(t, Progress{Cost: math.Inf(1)}, progress[0])
+	for i := 1; i < len(progress); i++ {
+		assert(t, progress[i].Cost < progress[i-1].Cost, "%s: best cost did not improve at iteration %d", name, progress[i].Iteration)
+	}
+	equals(t, solution.Cost, progress[len(progress)-1].Cost)
+}
+
+func TestRRTStar(t *testing.T) {
+	prob, cSpace, checker, shortest := aroundObstacle()
+	trees := map[bool]int{}
+	for _, informed := range []bool{false, true} {
+		params := RRTStarParams{MaxIterations: 3000, Informed: informed}
+		solution, err := NewRRTStar(prob, cSpace, checker, 1, params).Plan(context.Background())
+		ok(t, err)
+		trees[informed] = len(solution.Tree)
+		checkShortPath(t, fmt.Sprintf("informed=%v", informed), prob, checker, shortest, solution)
+	}
+	assert(t, trees[true] < trees[false], "pruning should shrink the tree from %d vertices, got %d", trees[false], trees[true])
+}
+
+func TestRRTStarNoSolution(t *testing.T) {
+	cSpace := ConfigSpace{XMin: 0, XMax: 50, YMin: 0, YMax: 50}
+	prob := Problem{Start: Point{X: 5, Y: 5}, Goal: Circle{X: 45, Y: 45, R: 2}, Epsilon: 2, GoalBias: 0.05}
+	enclosed := PointChecker{Obstacles: []Circle{{X: 45, Y: 45, R: 4}}, Space: cSpace}
+
+	params := RRTStarParams{MaxIterations: 300, Informed: true}
+	solution, err := NewRRTStar(prob, cSpace, enclosed, 1, params).Plan(context.Background())
+	noSol, isNoSol := err.(*NoSolutionError)
+	assert(t, isNoSol, "expected a *NoSolutionError, got %v", err)
+	equals(t, 300, noSol.Iterations)
+	assert(t, len(noSol.Tree) > 0, "expected the partial tree")
+	equals(t, 0, len(solution.Path))
+	assert(t, math.IsInf(solution.Cost, 1), "expected infinite cost, got %f", solution.Cost)
+}
+
+func TestSampleInformed(t *testing.T) {
+	cSpace := ConfigSpace{XMin: 0, XMax: 50, YMin: 0, YMax: 50}
+	prob := Problem{Start: Point{X: 5, Y: 5}, Goal: Circle{X: 45, Y: 45, R: 5}}
+	rng := rand.New(rand.NewSource(1))
+
+	// The ellipse is smaller than the config space at the first cost, and
+	// larger at the second.
+	for _, cost := range []float64{55, 150} {
+		for i := 0; i < 1000; i++ {
+			u := Pose(sampleInformed(prob, cSpace, rng, cost))
+			foci := math.Hypot(u.X-prob.Start.X, u.Y-prob.Start.Y) + math.Hypot(u.X-prob.Goal.X, u.Y-prob.Goal.Y)
+			assert(t, foci <= cost+prob.Goal.R+1e-9, "sample %v cannot shorten a path of cost %f", u, cost)
+			assert(t, cSpace.XMin <= u.X && u.X <= cSpace.XMax && cSpace.YMin <= u.Y && u.Y <= cSpace.YMax, "sample %v outside the config space", u)
+		}
+	}
+}
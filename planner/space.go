@@ -0,0 +1,184 @@
+package planner
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/hdhauk/enae788v/angle"
+)
+
+// Plane is the state space of a robot in 2D space, where the distance between
+// two states is the cartesian distance between their positions.
+type Plane struct{}
+
+// Distance returns the cartesian distance between two states.
+func (Plane) Distance(a, b State) float64 {
+	p, q := Pose(a), Pose(b)
+	dx, dy := q.X-p.X, q.Y-p.Y
+	return math.Sqrt(dx*dx + dy*dy)
+}
+
+// UniformSampler picks random states uniformly within the configuration
+// space, with a heading in [0, 2π). Velocities are drawn within the velocity
+// limits, and are zero when there are none.
+type UniformSampler struct {
+	Space ConfigSpace
+}
+
+// Sample returns a random Point.
+func (s UniformSampler) Sample(rng *rand.Rand) State {
+	c := s.Space
+	return Point{
+		X:     c.XMin + rng.Float64()*(c.XMax-c.XMin),
+		Y:     c.YMin + rng.Float64()*(c.YMax-c.YMin),
+		Theta: rng.Float64() * 2 * math.Pi,
+		V:     c.VMin + rng.Float64()*(c.VMax-c.VMin),
+		W:     c.WMin + rng.Float64()*(c.WMax-c.WMin),
+	}
+}
+
+// GoalRegion is a circular goal region with optional constraints on the
+// heading, velocity and angular rate of the robot.
+type GoalRegion struct {
+	Circle
+	Heading     *HeadingConstraint
+	Velocity    *Bounds
+	AngularRate *Bounds
+	Space       ConfigSpace // limits the velocities of goal samples
+}
+
+// NewGoalRegion returns the goal of a problem.
+func NewGoalRegion(prob Problem, cSpace ConfigSpace) *GoalRegion {
+	return &GoalRegion{
+		Circle:      prob.Goal,
+		Heading:     prob.GoalHeading,
+		Velocity:    prob.GoalVelocity,
+		AngularRate: prob.GoalAngularRate,
+		Space:       cSpace,
+	}
+}
+
+// Satisfied returns true if s is within the goal region and satisfies the
+// goal heading, velocity and angular rate constraints.
+func (g *GoalRegion) Satisfied(s State) bool {
+	p := Pose(s)
+	if !Near(p, g.Circle) {
+		return false
+	}
+	if h := g.Heading; h != nil && angle.Distance(p.Theta, h.Theta) > h.Tolerance {
+		return false
+	}
+	if b := g.Velocity; b != nil && !b.Contains(p.V) {
+		return false
+	}
+	if b := g.AngularRate; b != nil && !b.Contains(p.W) {
+		return false
+	}
+	return true
+}
+
+// Sample picks a random Point within the goal region that satisfies the goal
+// constraints, and the velocity limits of the configuration space.
+func (g *GoalRegion) Sample(rng *rand.Rand) State {
+	r := g.R * math.Sqrt(rng.Float64())
+	phi := rng.Float64() * 2 * math.Pi
+	x := g.X + r*math.Cos(phi)
+	y := g.Y + r*math.Sin(phi)
+
+	theta := rng.Float64() * 2 * math.Pi
+	if h := g.Heading; h != nil {
+		theta = angle.Normalize(h.Theta + h.Tolerance*(2*rng.Float64()-1))
+	}
+
+	c := g.Space
+	vBounds := Bounds{c.VMin, c.VMax}
+	if b := g.Velocity; b != nil {
+		vBounds = Bounds{math.Max(b.Min, c.VMin), math.Min(b.Max, c.VMax)}
+	}
+	wBounds := Bounds{c.WMin, c.WMax}
+	if b := g.AngularRate; b != nil {
+		wBounds = Bounds{math.Max(b.Min, c.WMin), math.Min(b.Max, c.WMax)}
+	}
+	v := vBounds.Min + rng.Float64()*(vBounds.Max-vBounds.Min)
+	w := wBounds.Min + rng.Float64()*(wBounds.Max-wBounds.Min)
+
+	return Point{X: x, Y: y, Theta: theta, V: v, W: w}
+}
+
+// Near returns true if p is within circle c.
+func Near(p Point, c Circle) bool {
+	dx, dy := p.X-c.X, p.Y-c.Y
+	return math.Sqrt(dx*dx+dy*dy) < c.R
+}
+
+// SE2 is the state space of a geometric robot with a heading, such as the
+// robot with a footprint of hw3. The distance between two states adds the
+// shortest turn between their headings, weighed by TurnWeight, to the
+// distance between their positions, as the two sides of a right triangle. A
+// TurnWeight of the reach of the footprint makes a turn as far as the
+// distance its furthest point sweeps. The zero value ignores the heading,
+// like Plane.
+type SE2 struct {
+	TurnWeight float64 // distance equivalent to turning by one radian
+	MaxTurn    float64 // largest turn of a step toward a sample in radians, unlimited if zero
+}
+
+// Distance returns the weighed distance between two states.
+func (s SE2) Distance(a, b State) float64 {
+	p, q := Pose(a), Pose(b)
+	dx, dy, dtheta := q.X-p.X, q.Y-p.Y, s.TurnWeight*angle.Distance(p.Theta, q.Theta)
+	return math.Sqrt(dx*dx + dy*dy + dtheta*dtheta)
+}
+
+// StraightLine returns the steerer of the robot toward samples, which steps
+// at most epsilon and turns at most MaxTurn.
+func (s SE2) StraightLine(epsilon float64, allowSmallSteps bool) StraightLine {
+	return StraightLine{Epsilon: epsilon, AllowSmallSteps: allowSmallSteps, SE2: s}
+}
+
+// connector returns the steerer that connects two states exactly, whatever
+// MaxTurn is.
+func (s SE2) connector() StraightLine {
+	return StraightLine{Epsilon: math.Inf(1), AllowSmallSteps: true, SE2: SE2{TurnWeight: s.TurnWeight}}
+}
+
+// StraightLine steers a geometric robot along a straight line, at most
+// Epsilon toward the target, while the heading turns toward that of the
+// target along the shortest arc. Both move the same fraction of the way, so
+// the motion is a straight line in SE(2), and that fraction is cut short
+// where the turn would exceed MaxTurn. A robot that stays in place turns in
+// place. The cost of a motion is its SE(2) distance.
+type StraightLine struct {
+	Epsilon         float64
+	AllowSmallSteps bool // stop at targets closer than Epsilon instead of passing them
+	SE2
+}
+
+// Steer returns a straight line motion of length Epsilon from from toward
+// toward, or shorter where the turn is limited, or nil if the states are the
+// same. The heading never turns past that of toward, even if the position
+// passes it.
+func (s StraightLine) Steer(from, toward State) *Motion {
+	u, v := Pose(from), Pose(toward)
+	if s.Distance(u, v) == 0 {
+		return nil
+	}
+	length := math.Hypot(v.X-u.X, v.Y-u.Y)
+	turn := angle.Distance(u.Theta, v.Theta)
+
+	t := 1.0
+	if length > 0 && (!s.AllowSmallSteps || length > s.Epsilon) {
+		t = s.Epsilon / length
+	}
+	if s.MaxTurn > 0 && turn*math.Min(t, 1) > s.MaxTurn {
+		t = s.MaxTurn / turn
+	}
+	w := Point{X: v.X, Y: v.Y, Theta: v.Theta}
+	if t != 1 {
+		w.X, w.Y = u.X+t*(v.X-u.X), u.Y+t*(v.Y-u.Y)
+	}
+	if t < 1 {
+		w.Theta = angle.Lerp(u.Theta, v.Theta, t)
+	}
+	return &Motion{From: from, To: w, Cost: s.Distance(u, w)}
+}
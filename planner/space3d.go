@@ -0,0 +1,236 @@
+package planner
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+// Point3D is a position in 3D space. The 3D planners use Point3D states: the
+// robot translates without turning.
+type Point3D struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+	Z float64 `json:"z"`
+}
+
+func (p Point3D) String() string {
+	return fmt.Sprintf("(%.2f, %.2f, %.2f)", p.X, p.Y, p.Z)
+}
+
+// Pose3D returns the position of a 3D state.
+func Pose3D(s State) Point3D {
+	switch p := s.(type) {
+	case Point3D:
+		return p
+	case *Point3D:
+		return *p
+	}
+	panic(fmt.Sprintf("planner: %T is not a 3D state", s))
+}
+
+// distance3D returns the distance between p and q.
+func distance3D(p, q Point3D) float64 {
+	dx, dy, dz := q.X-p.X, q.Y-p.Y, q.Z-p.Z
+	return math.Sqrt(dx*dx + dy*dy + dz*dz)
+}
+
+// Euclidean3D is the state space of a robot translating in 3D space, where
+// the distance between two states is the distance between their positions.
+type Euclidean3D struct{}
+
+// Distance returns the distance between two 3D states.
+func (Euclidean3D) Distance(a, b State) float64 {
+	return distance3D(Pose3D(a), Pose3D(b))
+}
+
+// UniformSampler3D picks random positions uniformly within a 3D configuration
+// space.
+type UniformSampler3D struct {
+	Space ConfigSpace3D
+}
+
+// Sample returns a random Point3D.
+func (s UniformSampler3D) Sample(rng *rand.Rand) State {
+	c := s.Space
+	return Point3D{
+		X: c.XMin + rng.Float64()*(c.XMax-c.XMin),
+		Y: c.YMin + rng.Float64()*(c.YMax-c.YMin),
+		Z: c.ZMin + rng.Float64()*(c.ZMax-c.ZMin),
+	}
+}
+
+// StraightLine3D steers a robot in 3D space along a straight line, at most
+// Epsilon toward the target. The cost of a motion is its length.
+type StraightLine3D struct {
+	Epsilon         float64
+	AllowSmallSteps bool // stop at targets closer than Epsilon instead of passing them
+}
+
+// Steer returns a straight line motion of length Epsilon from from toward
+// toward, or nil if the states are the same.
+func (s StraightLine3D) Steer(from, toward State) *Motion {
+	u, v := Pose3D(from), Pose3D(toward)
+	length := distance3D(u, v)
+	if length == 0 {
+		return nil
+	}
+	t := 1.0
+	if !s.AllowSmallSteps || length > s.Epsilon {
+		t = s.Epsilon / length
+	}
+	w := v
+	if t != 1 {
+		w = Point3D{X: u.X + t*(v.X-u.X), Y: u.Y + t*(v.Y-u.Y), Z: u.Z + t*(v.Z-u.Z)}
+	}
+	return &Motion{From: from, To: w, Cost: t * length}
+}
+
+// GoalRegion3D is a spherical goal region in 3D space.
+type GoalRegion3D struct {
+	Sphere
+}
+
+// Satisfied returns true if s is within the goal region.
+func (g GoalRegion3D) Satisfied(s State) bool {
+	return g.Contains(Pose3D(s))
+}
+
+// Sample picks a random Point3D within the goal region.
+func (g GoalRegion3D) Sample(rng *rand.Rand) State {
+	r := g.R * math.Cbrt(rng.Float64())
+	z := 2*rng.Float64() - 1
+	sin, cos := math.Sincos(rng.Float64() * 2 * math.Pi)
+	xy := math.Sqrt(1 - z*z)
+	return Point3D{X: g.X + r*xy*cos, Y: g.Y + r*xy*sin, Z: g.Z + r*z}
+}
+
+// NewRRT3D returns an RRT for a robot translating in straight lines in 3D
+// space.
+func NewRRT3D(prob Problem3D, cSpace ConfigSpace3D, checker CollisionChecker, seed int64) *RRT {
+	return &RRT{
+		Start:    prob.Start,
+		Space:    Euclidean3D{},
+		Sampler:  UniformSampler3D{cSpace},
+		Steerer:  StraightLine3D{Epsilon: prob.Epsilon, AllowSmallSteps: prob.AllowSmallSteps},
+		Checker:  checker,
+		Goal:     GoalRegion3D{prob.Goal},
+		GoalBias: prob.GoalBias,
+		Rand:     rand.New(rand.NewSource(seed)),
+	}
+}
+
+// RRTStar3D is RRT*, or informed RRT*, for a robot translating in straight
+// lines in 3D space. The SE2 of the params is not used.
+type RRTStar3D struct {
+	Problem Problem3D
+	Space   ConfigSpace3D
+	Checker CollisionChecker
+	Params  RRTStarParams
+	Rand    *rand.Rand
+}
+
+// NewRRTStar3D returns an RRT* planner for the 3D problem.
+func NewRRTStar3D(prob Problem3D, cSpace ConfigSpace3D, checker CollisionChecker, seed int64, params RRTStarParams) *RRTStar3D {
+	return &RRTStar3D{
+		Problem: prob,
+		Space:   cSpace,
+		Checker: checker,
+		Params:  params,
+		Rand:    rand.New(rand.NewSource(seed)),
+	}
+}
+
+// Plan grows and rewires the tree as RRTStar does.
+func (p *RRTStar3D) Plan(ctx context.Context) (*Solution, error) {
+	prob, cSpace := p.Problem, p.Space
+	return planStar(ctx, starSpace{
+		start:    prob.Start,
+		space:    Euclidean3D{},
+		sampler:  UniformSampler3D{cSpace},
+		goal:     GoalRegion3D{prob.Goal},
+		goalBias: prob.GoalBias,
+		steer:    StraightLine3D{Epsilon: prob.Epsilon, AllowSmallSteps: prob.AllowSmallSteps},
+		connect:  StraightLine3D{Epsilon: math.Inf(1), AllowSmallSteps: true},
+		radius: func(n int) float64 {
+			return connectionRadius3D(cSpace, n, prob.Epsilon)
+		},
+		sampleInformed: func(rng *rand.Rand, cost float64) State {
+			return sampleInformed3D(prob, cSpace, rng, cost)
+		},
+		lowerBound: func(s State) float64 {
+			q := Pose3D(s)
+			return distance3D(prob.Start, q) + math.Max(0, distance3D(q, prob.Goal.Center())-prob.Goal.R)
+		},
+	}, p.Params, p.Rand, p.Checker)
+}
+
+// connectionRadius3D is connectionRadius for a 3D configuration space, where
+// the radius shrinks with the cube root of log(n)/n.
+func connectionRadius3D(cSpace ConfigSpace3D, n int, epsilon float64) float64 {
+	gamma := 2 * math.Cbrt(cSpace.volume()/math.Pi)
+	r := gamma * math.Cbrt(math.Log(float64(n))/float64(n))
+	if epsilon > 0 {
+		r = math.Min(r, epsilon)
+	}
+	return r
+}
+
+// sampleInformed3D is sampleInformed in 3D space: it returns a position
+// uniformly distributed in the part of the config space within the prolate
+// spheroid with the start and goal center as foci.
+func sampleInformed3D(prob Problem3D, cSpace ConfigSpace3D, rng *rand.Rand, cost float64) State {
+	start, g, c := prob.Start, prob.Goal, cSpace
+	focal := distance3D(start, g.Center())
+	a := (cost + g.R) / 2
+	b := math.Sqrt(math.Max(0, a*a-focal*focal/4))
+	if math.IsInf(cost, 1) || 4*math.Pi*a*b*b/3 > c.volume() {
+		sampler := UniformSampler3D{c}
+		for {
+			u := sampler.Sample(rng)
+			if q := Pose3D(u); distance3D(start, q)+distance3D(q, g.Center()) <= 2*a {
+				return u
+			}
+		}
+	}
+
+	// The axes of the spheroid: the first from the start to the goal, and
+	// two more perpendicular to it and each other.
+	e1 := Point3D{X: 1}
+	if focal > 0 {
+		e1 = Point3D{X: (g.X - start.X) / focal, Y: (g.Y - start.Y) / focal, Z: (g.Z - start.Z) / focal}
+	}
+	e2 := Point3D{Z: 1}
+	if math.Abs(e1.Z) > 0.9 {
+		e2 = Point3D{X: 1}
+	}
+	e2 = normalize3D(cross3D(e1, e2))
+	e3 := cross3D(e1, e2)
+	for {
+		x, y, z := 2*rng.Float64()-1, 2*rng.Float64()-1, 2*rng.Float64()-1
+		if x*x+y*y+z*z > 1 {
+			continue
+		}
+		x, y, z = a*x, b*y, b*z
+		u := Point3D{
+			X: (start.X+g.X)/2 + x*e1.X + y*e2.X + z*e3.X,
+			Y: (start.Y+g.Y)/2 + x*e1.Y + y*e2.Y + z*e3.Y,
+			Z: (start.Z+g.Z)/2 + x*e1.Z + y*e2.Z + z*e3.Z,
+		}
+		if c.XMin <= u.X && u.X <= c.XMax && c.YMin <= u.Y && u.Y <= c.YMax && c.ZMin <= u.Z && u.Z <= c.ZMax {
+			return u
+		}
+	}
+}
+
+// cross3D returns the cross product of a and b.
+func cross3D(a, b Point3D) Point3D {
+	return Point3D{X: a.Y*b.Z - a.Z*b.Y, Y: a.Z*b.X - a.X*b.Z, Z: a.X*b.Y - a.Y*b.X}
+}
+
+// normalize3D returns p scaled to unit length.
+func normalize3D(p Point3D) Point3D {
+	l := distance3D(Point3D{}, p)
+	return Point3D{X: p.X / l, Y: p.Y / l, Z: p.Z / l}
+}
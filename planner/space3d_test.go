@@ -0,0 +1,94 @@
+package planner
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestStraightLine3D(t *testing.T) {
+	from, to := Point3D{}, Point3D{X: 2, Y: 3, Z: 6}
+	m := StraightLine3D{Epsilon: 3.5}.Steer(from, to)
+	equals(t, 3.5, m.Cost)
+	equals(t, Point3D{X: 1, Y: 1.5, Z: 3}, m.To)
+
+	// A short step is taken to the target, or past it unless allowed.
+	m = StraightLine3D{Epsilon: 14, AllowSmallSteps: true}.Steer(from, to)
+	equals(t, to, m.To)
+	m = StraightLine3D{Epsilon: 14}.Steer(from, to)
+	equals(t, Point3D{X: 4, Y: 6, Z: 12}, m.To)
+	assert(t, StraightLine3D{Epsilon: 1}.Steer(to, to) == nil, "expected no motion to the same state")
+}
+
+func TestGoalRegion3D(t *testing.T) {
+	g := GoalRegion3D{Sphere{X: 5, Y: -5, Z: 20, R: 2}}
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		s := g.Sample(rng)
+		assert(t, g.Satisfied(s), "sample %v outside the goal region", s)
+	}
+}
+
+func TestSampleInformed3D(t *testing.T) {
+	prob := Problem3D{Start: Point3D{X: 10, Y: 10, Z: 10}, Goal: Sphere{X: 40, Y: 50, Z: 10, R: 2}}
+	cSpace := ConfigSpace3D{XMin: 0, XMax: 100, YMin: 0, YMax: 100, ZMin: 0, ZMax: 20}
+	rng := rand.New(rand.NewSource(1))
+	for _, cost := range []float64{55, 80, math.Inf(1)} {
+		for i := 0; i < 500; i++ {
+			q := Pose3D(sampleInformed3D(prob, cSpace, rng, cost))
+			assert(t, distance3D(prob.Start, q)+distance3D(q, prob.Goal.Center()) <= cost+prob.Goal.R+1e-9,
+				"sample %v outside the spheroid of cost %.0f", q, cost)
+			assert(t, cSpace.XMin <= q.X && q.X <= cSpace.XMax && cSpace.ZMin <= q.Z && q.Z <= cSpace.ZMax,
+				"sample %v outside the config space", q)
+		}
+	}
+}
+
+func TestLoadConfig3D(t *testing.T) {
+	config, err := LoadConfig3D(filepath.Join("..", "drone", "problems.json"))
+	ok(t, err)
+	assert(t, len(config.Spheres) > 0 && len(config.Boxes) > 0, "no obstacles")
+
+	config.Problems[0].Start = config.Spheres[0].Center()
+	config.Problems[1].Goal.Z = 60
+	config.Problems[1].Epsilon = 0
+	issues, isInfeasible := config.Check().(FeasibilityError)
+	assert(t, isInfeasible, "expected a FeasibilityError")
+	equals(t, 3, len(issues))
+	equals(t, "start", issues[0].Field)
+	equals(t, "goal_region", issues[1].Field)
+	equals(t, "epsilon", issues[2].Field)
+}
+
+// TestPlan3D plans every 3D problem with RRT and informed RRT*, and checks
+// that the path is continuous and collision free and ends in the goal region.
+func TestPlan3D(t *testing.T) {
+	config, err := LoadConfig3D(filepath.Join("..", "drone", "problems.json"))
+	ok(t, err)
+	checker := config.Checker()
+	for i, prob := range config.Problems {
+		planners := map[string]Planner{
+			"rrt":      NewRRT3D(prob, config.ConfigSpace, checker, int64(i+1)),
+			"informed": NewRRTStar3D(prob, config.ConfigSpace, checker, int64(i+1), RRTStarParams{Budget: 300 * time.Millisecond, Informed: true}),
+		}
+		for name, p := range planners {
+			t.Run(prob.Name+"/"+name, func(t *testing.T) {
+				sol, err := p.Plan(context.Background())
+				ok(t, err)
+				equals(t, State(prob.Start), sol.Path[0].From)
+				for j, m := range sol.Path {
+					assert(t, checker.MotionValid(m), "motion %d is in collision", j)
+					if j > 0 {
+						equals(t, sol.Path[j-1].To, m.From)
+					}
+				}
+				end := Pose3D(sol.Path[len(sol.Path)-1].To)
+				assert(t, prob.Goal.Contains(end), "path ends at %v outside the goal region", end)
+				assert(t, sol.Cost >= distance3D(prob.Start, prob.Goal.Center())-prob.Goal.R, "cost %.2f below the lower bound", sol.Cost)
+			})
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package planner
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/hdhauk/enae788v/angle"
+)
+
+func TestUniformSample(t *testing.T) {
+	rng := rand.New(rand.NewSource(69))
+	sampler := UniformSampler{ConfigSpace{XMin: 0, XMax: 100, YMin: 0, YMax: 100, VMin: -1, VMax: 2}}
+
+	for i := 0; i < 1000; i++ {
+		p := sampler.Sample(rng).(Point)
+		assert(t, p.X > 0.0 && p.X < 100.0, "point outside of range")
+		assert(t, p.Y > 0.0 && p.Y < 100.0, "point outside of range")
+		assert(t, p.Theta >= 0 && p.Theta < 2*math.Pi, "heading outside [0, 2π)")
+		assert(t, p.V >= -1 && p.V <= 2, "velocity outside of range")
+		equals(t, 0.0, p.W)
+	}
+}
+
+func TestStraightLine(t *testing.T) {
+	// Pure y-direction
+	m := StraightLine{Epsilon: 1}.Steer(Point{}, Point{X: 0, Y: 10})
+	equals(t, Point{X: 0, Y: 1}, m.To)
+	equals(t, 1.0, m.Cost)
+
+	// Pure x-direction
+	m = StraightLine{Epsilon: 50}.Steer(Point{}, Point{X: 100, Y: 0})
+	equals(t, Point{X: 50, Y: 0}, m.To)
+
+	// Pure 45-direction, turning the same fraction of the way.
+	m = StraightLine{Epsilon: 50}.Steer(Point{}, Point{X: 100, Y: 100, Theta: 1})
+	c := m.To.(Point)
+	assert(t, math.Abs(50.0-math.Sqrt(c.X*c.X+c.Y*c.Y)) < 1e-9, "should be epsilon long")
+	assert(t, c.X == c.Y, "should be same length")
+	assert(t, math.Abs(c.Theta-50/math.Hypot(100, 100)) < 1e-9, "unexpected heading %v", c.Theta)
+
+	// Steps past the target stop turning at its heading.
+	m = StraightLine{Epsilon: 10}.Steer(Point{}, Point{X: 5, Theta: 1})
+	equals(t, Point{X: 10, Theta: 1}, m.To)
+
+	// Small steps stop at the target.
+	m = StraightLine{Epsilon: 10, AllowSmallSteps: true}.Steer(Point{}, Point{X: 3, Y: 4})
+	equals(t, Point{X: 3, Y: 4}, m.To)
+	equals(t, 5.0, m.Cost)
+
+	assert(t, StraightLine{Epsilon: 1}.Steer(Point{X: 1}, Point{X: 1}) == nil, "no motion between equal states")
+	assert(t, StraightLine{Epsilon: 1}.Steer(Point{X: 1}, Point{X: 1, Theta: 1}) == nil, "no motion that only turns in the plane")
+}
+
+func TestSE2(t *testing.T) {
+	space := SE2{TurnWeight: 2, MaxTurn: 0.5}
+	equals(t, 5.0, space.Distance(Point{}, Point{X: 3, Y: 4}))
+	assert(t, math.Abs(space.Distance(Point{Theta: 0.1}, Point{X: 3, Theta: 2*math.Pi - 0.1})-math.Hypot(3, 0.4)) < 1e-9, "turns along the shortest arc")
+
+	// The turn limit cuts the step short, along the straight line in SE(2).
+	steer := space.StraightLine(10, true)
+	m := steer.Steer(Point{}, Point{X: 4, Theta: 2})
+	p := m.To.(Point)
+	assert(t, math.Abs(p.X-1) < 1e-9 && math.Abs(p.Theta-0.5) < 1e-9, "unexpected step %v", p)
+	assert(t, math.Abs(m.Cost-math.Hypot(1, 1)) < 1e-9, "unexpected cost %v", m.Cost)
+
+	// Turning in place is a motion of its own.
+	m = steer.Steer(Point{X: 1, Theta: 6}, Point{X: 1, Theta: 1})
+	p = m.To.(Point)
+	assert(t, p.X == 1 && math.Abs(p.Theta-angle.Normalize(6+0.5)) < 1e-9, "unexpected turn %v", p)
+	m = steer.Steer(Point{X: 1, Theta: 0.1}, Point{X: 1, Theta: 0.2})
+	equals(t, Point{X: 1, Theta: 0.2}, m.To)
+
+	// Connections reach the target whatever the turn limit.
+	equals(t, Point{X: 4, Theta: 2}, space.connector().Steer(Point{}, Point{X: 4, Theta: 2}).To)
+}
+
+func TestNear(t *testing.T) {
+	p := Point{X: 10, Y: 10}
+
+	c := Circle{10, 15, 6}
+	assert(t, Near(p, c), "should be near")
+
+	c = Circle{10, 15, 4.9999}
+	assert(t, !Near(p, c), "should not be near")
+}
+
+func TestGoalRegion(t *testing.T) {
+	g := NewGoalRegion(Problem{Goal: Circle{10, 10, 5}}, ConfigSpace{})
+	assert(t, g.Satisfied(Point{X: 12, Y: 10, Theta: 2, V: 3, W: 1}), "no constraints, should be in goal")
+	assert(t, !g.Satisfied(Point{X: 20, Y: 10}), "outside goal region")
+
+	g.Heading = &HeadingConstraint{Theta: math.Pi, Tolerance: 0.1}
+	g.Velocity = &Bounds{Min: -0.5, Max: 0.5}
+	g.AngularRate = &Bounds{Min: -0.1, Max: 0.1}
+	assert(t, g.Satisfied(Point{X: 12, Y: 10, Theta: -math.Pi + 0.05, V: 0.2}), "should satisfy all constraints")
+	assert(t, g.Satisfied(&PathPoint{Point: Point{X: 12, Y: 10, Theta: math.Pi - 0.05}}), "path points should satisfy all constraints")
+	assert(t, !g.Satisfied(Point{X: 12, Y: 10, Theta: math.Pi / 2, V: 0.2}), "heading outside tolerance")
+	assert(t, !g.Satisfied(Point{X: 12, Y: 10, Theta: math.Pi, V: 1}), "velocity outside bounds")
+	assert(t, !g.Satisfied(Point{X: 12, Y: 10, Theta: math.Pi, W: 0.5}), "angular rate outside bounds")
+}
+
+func TestGoalSample(t *testing.T) {
+	rng := rand.New(rand.NewSource(69))
+	cSpace := ConfigSpace{VMin: -5, VMax: 5, WMin: -1, WMax: 1}
+	g := NewGoalRegion(Problem{
+		Goal:         Circle{10, 10, 5},
+		GoalHeading:  &HeadingConstraint{Theta: -math.Pi / 2, Tolerance: 0.2},
+		GoalVelocity: &Bounds{Min: -0.5, Max: 0.5},
+	}, cSpace)
+
+	for i := 0; i < 1000; i++ {
+		u := g.Sample(rng).(Point)
+		assert(t, g.Satisfied(u), "goal sample %v should be in goal", u)
+		assert(t, cSpace.WMin <= u.W && u.W <= cSpace.WMax, "goal sample angular rate outside config space")
+	}
+}
@@ -0,0 +1,231 @@
+package planner
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/hdhauk/enae788v/angle"
+)
+
+// CostFunc returns the cost of a kinodynamic motion.
+type CostFunc func(m *Motion) float64
+
+// DurationCost is the time it takes to complete the motion.
+func DurationCost(m *Motion) float64 {
+	return m.To.(*PathPoint).T - m.From.(*PathPoint).T
+}
+
+// EffortCost is the integral of the squared controls along the motion.
+func EffortCost(m *Motion) float64 {
+	var cost float64
+	t := m.From.(*PathPoint).T
+	for _, s := range m.Path {
+		p := s.(*PathPoint)
+		cost += (p.A*p.A + p.Gamma*p.Gamma) * (p.T - t)
+		t = p.T
+	}
+	return cost
+}
+
+// SSTParams configures the Stable Sparse RRT planner.
+type SSTParams struct {
+	Budget        time.Duration // planning continues improving until the budget is spent, if non-zero
+	DeltaBN       float64       // radius used when selecting the best node near a sample
+	DeltaS        float64       // radius of the witness regions used for sparsification
+	MaxPropTime   float64       // upper bound on the duration of a random propagation
+	MaxIterations int           // stop after this many iterations if non-zero
+	MaxNodes      int           // stop when the tree has this many vertices if non-zero
+	Cost          CostFunc      // DurationCost if nil
+}
+
+// witness represents a region of radius DeltaS in the state space, and the
+// lowest cost vertex that has been found in it.
+type witness struct {
+	Point
+	rep *Vertex
+}
+
+// SST builds a sparse tree of trajectories for the robot with dynamics using
+// the Stable Sparse RRT algorithm, which is asymptotically near-optimal with
+// respect to the cost function.
+type SST struct {
+	Problem Problem
+	Space   ConfigSpace
+	Checker CollisionChecker
+	Params  SSTParams
+	Rand    *rand.Rand
+}
+
+// NewSST returns a Stable Sparse RRT planner for the problem.
+func NewSST(prob Problem, cSpace ConfigSpace, checker CollisionChecker, seed int64, params SSTParams) *SST {
+	return &SST{
+		Problem: prob,
+		Space:   cSpace,
+		Checker: checker,
+		Params:  params,
+		Rand:    rand.New(rand.NewSource(seed)),
+	}
+}
+
+// Plan propagates random controls from the lowest cost vertex near a random
+// sample every iteration, and only keeps the result if it is the lowest cost
+// vertex in its witness region. The best path found when the budget is spent,
+// ctx is done or a limit is reached is returned, along with the remaining tree
+// and the best cost at every improvement.
+func (p *SST) Plan(ctx context.Context) (*Solution, error) {
+	params, cSpace, rng := p.Params, p.Space, p.Rand
+	if params.Cost == nil {
+		params.Cost = DurationCost
+	}
+	goal := NewGoalRegion(p.Problem, cSpace)
+	sampler := UniformSampler{cSpace}
+
+	root := &Vertex{State: &PathPoint{Point: p.Problem.Start}}
+	active := map[*Vertex]bool{root: true}
+	inactive := map[*Vertex]bool{}
+	children := map[*Vertex]int{}
+	witnesses := []*witness{{Point: p.Problem.Start, rep: root}}
+
+	var best *Vertex
+	solution := &Solution{Cost: math.Inf(1), Progress: []Progress{{Cost: math.Inf(1)}}}
+	checker := countingChecker{p.Checker, &solution.Stats}
+	started := time.Now()
+	var deadline time.Time
+	if params.Budget > 0 {
+		deadline = started.Add(params.Budget)
+	}
+	reason := "time budget spent"
+	for i := 1; ; i++ {
+		if !deadline.IsZero() && !time.Now().Before(deadline) {
+			break
+		}
+		if err := ctx.Err(); err != nil {
+			reason = err.Error()
+			break
+		}
+		if params.MaxIterations > 0 && i > params.MaxIterations {
+			reason = "iteration limit reached"
+			break
+		}
+		if params.MaxNodes > 0 && len(active)+len(inactive) >= params.MaxNodes {
+			reason = "node limit reached"
+			break
+		}
+		solution.Stats.Iterations = i
+
+		var u State
+		if rng.Float64() < p.Problem.GoalBias {
+			u = goal.Sample(rng)
+		} else {
+			u = sampler.Sample(rng)
+		}
+		v := bestNear(active, Pose(u), params.DeltaBN)
+
+		// Monte-Carlo propagation with random controls and duration.
+		a := cSpace.AMin + rng.Float64()*(cSpace.AMax-cSpace.AMin)
+		gamma := cSpace.GammaMin + rng.Float64()*(cSpace.GammaMax-cSpace.GammaMin)
+		duration := Timestep + rng.Float64()*(params.MaxPropTime-Timestep)
+		m := Propagate(v.State.(*PathPoint), a, gamma, duration)
+		if m != nil && checker.MotionValid(m) {
+			w := &Vertex{State: m.To, Parent: v, Motion: m, Cost: v.Cost + params.Cost(m)}
+			wPose := Pose(w.State)
+
+			s := closestWitness(witnesses, wPose)
+			if stateDistance(s.Point, wPose) > params.DeltaS {
+				s = &witness{Point: wPose}
+				witnesses = append(witnesses, s)
+			}
+
+			if s.rep == nil || w.Cost < s.rep.Cost {
+				active[w] = true
+				children[v]++
+
+				// The previous representative is dominated, and is removed
+				// together with every ancestor that only existed to reach it.
+				peer := s.rep
+				s.rep = w
+				if peer != nil {
+					delete(active, peer)
+					inactive[peer] = true
+				}
+				for peer != nil && children[peer] == 0 && inactive[peer] {
+					delete(inactive, peer)
+					parent := peer.Parent
+					children[parent]--
+					peer = parent
+				}
+
+				if goal.Satisfied(w.State) && w.Cost < solution.Cost {
+					best = w
+					solution.Cost = w.Cost
+					solution.Path = backtrack(best)
+					solution.Progress = append(solution.Progress, Progress{Iteration: i, Elapsed: time.Since(started), Cost: solution.Cost})
+				}
+			}
+		}
+	}
+
+	for v := range active {
+		if v.Motion != nil {
+			solution.Tree = append(solution.Tree, v.Motion)
+		}
+	}
+	for v := range inactive {
+		if v.Motion != nil {
+			solution.Tree = append(solution.Tree, v.Motion)
+		}
+	}
+
+	solution.Stats.Elapsed = time.Since(started)
+	if best == nil {
+		_, err := noSolution(reason, solution.Stats, solution.Tree)
+		return solution, err
+	}
+	return solution, nil
+}
+
+// bestNear returns the lowest cost active vertex within radius of u, or the
+// closest active vertex if none are within the radius.
+func bestNear(active map[*Vertex]bool, u Point, radius float64) *Vertex {
+	var best, closest *Vertex
+	shortest := math.MaxFloat64
+	for v := range active {
+		d := stateDistance(u, Pose(v.State))
+		if d < radius && (best == nil || v.Cost < best.Cost) {
+			best = v
+		}
+		if d < shortest {
+			closest, shortest = v, d
+		}
+	}
+	if best != nil {
+		return best
+	}
+	return closest
+}
+
+// closestWitness naively searches for the witness closest to u.
+func closestWitness(witnesses []*witness, u Point) *witness {
+	var closest *witness
+	shortest := math.MaxFloat64
+	for _, s := range witnesses {
+		d := stateDistance(s.Point, u)
+		if d < shortest {
+			closest, shortest = s, d
+		}
+	}
+	return closest
+}
+
+// stateDistance is a weighted distance between two full states, so that
+// states at the same position but with different heading or velocities are
+// kept apart.
+func stateDistance(a, b Point) float64 {
+	dx, dy := a.X-b.X, a.Y-b.Y
+	dTheta := angle.Distance(a.Theta, b.Theta)
+	dV := (a.V - b.V) / 2
+	dW := a.W - b.W
+	return math.Sqrt(dx*dx + dy*dy + dTheta*dTheta + dV*dV + dW*dW)
+}
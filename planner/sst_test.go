@@ -0,0 +1,97 @@
+package planner
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestSST(t *testing.T) {
+	cSpace := ConfigSpace{
+		XMin: 0, XMax: 30,
+		YMin: 0, YMax: 30,
+		VMin: -5, VMax: 5,
+		WMin: -1.5, WMax: 1.5,
+		AMin: -2, AMax: 2,
+		GammaMin: -1.5, GammaMax: 1.5,
+	}
+	prob := Problem{Start: Point{X: 5, Y: 5}, Goal: Circle{X: 20, Y: 20, R: 5}}
+	checker := KinodynamicChecker{FootprintChecker{Space: cSpace, Robot: Robot{{}}}}
+	params := SSTParams{Budget: time.Minute, DeltaBN: 2, DeltaS: 1, MaxPropTime: 2, MaxIterations: 5000, Cost: DurationCost}
+
+	solution, err := NewSST(prob, cSpace, checker, 1, params).Plan(context.Background())
+	ok(t, err)
+	progress := solution.Progress
+	equals(t, Progress{Cost: math.Inf(1)}, progress[0])
+
+	// Only improvements of the best cost are recorded, and the last matches
+	// the returned path.
+	for i := 1; i < len(progress); i++ {
+		assert(t, progress[i].Cost < progress[i-1].Cost, "best cost did not improve at iteration %d", progress[i].Iteration)
+	}
+	path := Trajectory(solution.Path)
+	final := progress[len(progress)-1].Cost
+	assert(t, math.Abs(final-path[len(path)-1].T) < 1e-9, "cost %f should equal path duration %f", final, path[len(path)-1].T)
+	equals(t, final, solution.Cost)
+
+	last := path[len(path)-1]
+	assert(t, Near(last.Point, prob.Goal), "path should end in goal")
+	for _, p := range path {
+		assert(t, checker.Valid(p), "path should be safe")
+	}
+
+	// Pruning never leaves a motion whose start has been removed from the tree.
+	inTree := map[State]bool{}
+	for _, m := range solution.Tree {
+		inTree[m.To] = true
+	}
+	for _, m := range solution.Tree {
+		assert(t, m.From.(*PathPoint).T == 0 || inTree[m.From], "motion start was pruned from the tree")
+	}
+}
+
+func TestSSTNoSolution(t *testing.T) {
+	cSpace := ConfigSpace{
+		XMin: 0, XMax: 30,
+		YMin: 0, YMax: 30,
+		VMin: -5, VMax: 5,
+		WMin: -1.5, WMax: 1.5,
+		AMin: -2, AMax: 2,
+		GammaMin: -1.5, GammaMax: 1.5,
+	}
+	prob := Problem{Start: Point{X: 5, Y: 5}, Goal: Circle{X: 20, Y: 20, R: 2}}
+	checker := KinodynamicChecker{FootprintChecker{Obstacles: []Circle{{X: 20, Y: 20, R: 4}}, Space: cSpace, Robot: Robot{{}}}}
+	// Without a budget SST runs until a limit, and minimizes the duration by
+	// default.
+	params := SSTParams{DeltaBN: 2, DeltaS: 1, MaxPropTime: 2, MaxIterations: 200}
+
+	solution, err := NewSST(prob, cSpace, checker, 1, params).Plan(context.Background())
+	noSol, isNoSol := err.(*NoSolutionError)
+	assert(t, isNoSol, "expected a *NoSolutionError, got %v", err)
+	equals(t, "iteration limit reached", noSol.Reason)
+	equals(t, 200, noSol.Iterations)
+	equals(t, solution.Tree, noSol.Tree)
+}
+
+func TestEffortCost(t *testing.T) {
+	m := &Motion{
+		From: &PathPoint{T: 1},
+		To:   &PathPoint{T: 1.2},
+		Path: []State{
+			&PathPoint{A: 1, Gamma: 1, T: 1.1},
+			&PathPoint{A: 2, Gamma: 0, T: 1.2},
+		},
+	}
+	assert(t, math.Abs(EffortCost(m)-0.6) < 1e-9, "expected effort 0.6, got %f", EffortCost(m))
+	assert(t, math.Abs(DurationCost(m)-0.2) < 1e-9, "expected duration 0.2, got %f", DurationCost(m))
+}
+
+func BenchmarkSST(b *testing.B) {
+	prob, cSpace, obstacles, robot := loadProblem(b, "hw4", 1)
+	checker := KinodynamicChecker{FootprintChecker{Obstacles: obstacles, Space: cSpace, Robot: robot}}
+	params := SSTParams{Budget: time.Minute, DeltaBN: 2, DeltaS: 1, MaxPropTime: 2, Cost: DurationCost, MaxIterations: 2000}
+	for i := 0; i < b.N; i++ {
+		NewSST(prob, cSpace, checker, int64(i), params).Plan(context.Background())
+	}
+}
@@ -0,0 +1,431 @@
+package planner
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/hdhauk/enae788v/angle"
+	"github.com/hdhauk/enae788v/graph"
+)
+
+// TangentGraph finds the exact shortest path for a point robot among circular
+// obstacles, as in hw2, to use as a baseline for the paths of the sampling
+// planners. The shortest path consists of straight segments tangent to the
+// obstacles and arcs along their perimeters, so it lies in the graph of the
+// start, the goal and the tangent points between them and every obstacle,
+// where consecutive tangent points on an obstacle are joined by arcs. Where
+// obstacles overlap, the arcs along them meet at the points where their
+// perimeters intersect, which are vertices of both. The graph is searched
+// with the A* of hw1.
+//
+// The goal region is reached by the shortest segment toward it, which points
+// at the goal center. Paths do not follow the walls of the config space, so
+// the path found can be longer than the shortest where that would.
+type TangentGraph struct {
+	Problem   Problem
+	Space     ConfigSpace
+	Obstacles []Circle
+}
+
+// NewTangentGraph returns a tangent graph planner for the problem.
+func NewTangentGraph(prob Problem, cSpace ConfigSpace, obstacles []Circle) *TangentGraph {
+	return &TangentGraph{Problem: prob, Space: cSpace, Obstacles: obstacles}
+}
+
+// tangentTolerance is how far a tangent segment or point may reach into an
+// obstacle due to rounding.
+const tangentTolerance = 1e-9
+
+// tangentBuilder builds the tangent graph. Vertex 0 is the start and vertex 1
+// the goal region.
+type tangentBuilder struct {
+	*TangentGraph
+	stats    *Stats
+	vertices map[int]*graph.Vertex
+	motions  map[[2]int]*Motion
+	onCircle [][]tangentPoint // the tangent points on every obstacle
+}
+
+// tangentPoint is a vertex on the perimeter of an obstacle.
+type tangentPoint struct {
+	id    int
+	angle float64 // from the center of the obstacle
+}
+
+const (
+	tangentStart = 0
+	tangentGoal  = 1
+)
+
+// Plan builds the graph and searches it for the shortest path. The path is
+// empty if the start is in the goal region.
+func (p *TangentGraph) Plan(ctx context.Context) (*Solution, error) {
+	var stats Stats
+	started := time.Now()
+	fail := func(reason string, tree []*Motion) (*Solution, error) {
+		stats.Elapsed = time.Since(started)
+		return noSolution(reason, stats, tree)
+	}
+	start := Point{X: p.Problem.Start.X, Y: p.Problem.Start.Y}
+	if !p.free(start) {
+		return fail("start is in collision or outside the config space", nil)
+	}
+	if Near(start, p.Problem.Goal) {
+		stats.Elapsed = time.Since(started)
+		return &Solution{Cost: 0, Stats: stats}, nil
+	}
+
+	b := &tangentBuilder{
+		TangentGraph: p,
+		stats:        &stats,
+		vertices:     map[int]*graph.Vertex{},
+		motions:      map[[2]int]*Motion{},
+		onCircle:     make([][]tangentPoint, len(p.Obstacles)),
+	}
+	b.build()
+	if err := ctx.Err(); err != nil {
+		return fail(err.Error(), nil)
+	}
+
+	goal := p.Problem.Goal
+	toGoal := func(u, g *graph.Vertex) float64 {
+		return math.Max(0, math.Hypot(u.X-goal.X, u.Y-goal.Y)-goal.R)
+	}
+	result, err := graph.AStar(b.vertices, tangentStart, tangentGoal, toGoal)
+	stats.Iterations = len(result.SearchTree)
+
+	// The edges into and out of a tangent point give it slightly different
+	// headings, so the motion to every vertex of the tree starts where the
+	// motion to its parent ends. Parents are expanded before their children.
+	arrival := map[int]State{tangentStart: p.Problem.Start}
+	motions := map[int]*Motion{}
+	var tree []*Motion
+	for _, v := range result.SearchTree {
+		if v.ID == tangentStart {
+			continue
+		}
+		m := *b.motions[[2]int{v.Parent.ID, v.ID}]
+		m.From = arrival[v.Parent.ID]
+		arrival[v.ID], motions[v.ID] = m.To, &m
+		tree = append(tree, &m)
+	}
+	if err != nil {
+		return fail("the goal region cannot be reached", tree)
+	}
+
+	var path []*Motion
+	for _, v := range result.Path[1:] {
+		path = append(path, motions[v.ID])
+	}
+	stats.Elapsed = time.Since(started)
+	return &Solution{Path: path, Tree: tree, Cost: result.PathCost, Stats: stats}, nil
+}
+
+// build adds the start, the goal region, every tangent segment between them
+// and the obstacles that does not pass through an obstacle and the points
+// where obstacles intersect, and then joins the points on every obstacle by
+// arcs.
+func (b *tangentBuilder) build() {
+	start, goal := b.Problem.Start, b.Problem.Goal
+	b.vertices[tangentStart] = &graph.Vertex{ID: tangentStart, X: start.X, Y: start.Y, Neighbors: map[int]float64{}}
+	b.vertices[tangentGoal] = &graph.Vertex{ID: tangentGoal, X: goal.X, Y: goal.Y, Neighbors: map[int]float64{}}
+	b.toGoal(tangentStart)
+
+	for i, c := range b.Obstacles {
+		for _, t := range pointTangents(start.X, start.Y, c) {
+			b.segment(tangentStart, -1, Point{X: start.X, Y: start.Y}, i, t)
+		}
+		// The segment that leaves an obstacle toward the goal region points
+		// at the goal center, so it is tangent to the obstacle from there.
+		for _, t := range pointTangents(goal.X, goal.Y, c) {
+			if id, ok := b.tangentPoint(i, t); ok {
+				b.toGoal(id)
+			}
+		}
+		for j := i + 1; j < len(b.Obstacles); j++ {
+			for _, t := range circleTangents(c, b.Obstacles[j]) {
+				b.segment(-1, i, t[0], j, t[1])
+			}
+			for _, q := range circleIntersections(c, b.Obstacles[j]) {
+				b.corner(i, j, q)
+			}
+		}
+	}
+	for i := range b.Obstacles {
+		b.arcs(i)
+	}
+}
+
+// tangentPoint returns the vertex at q on obstacle i, adding it if needed.
+// It returns false if q is in collision or outside the config space.
+func (b *tangentBuilder) tangentPoint(i int, q Point) (int, bool) {
+	if !b.free(q) {
+		return 0, false
+	}
+	c := b.Obstacles[i]
+	a := angle.Normalize(math.Atan2(q.Y-c.Y, q.X-c.X))
+	for _, t := range b.onCircle[i] {
+		if angle.Distance(t.angle, a)*c.R < tangentTolerance {
+			return t.id, true
+		}
+	}
+	id := len(b.vertices)
+	b.vertices[id] = &graph.Vertex{ID: id, X: q.X, Y: q.Y, Neighbors: map[int]float64{}}
+	b.onCircle[i] = append(b.onCircle[i], tangentPoint{id, a})
+	return id, true
+}
+
+// corner adds the point q where obstacles i and j intersect as a vertex on
+// both, unless it is inside another obstacle or outside the config space.
+func (b *tangentBuilder) corner(i, j int, q Point) {
+	id, ok := b.tangentPoint(i, q)
+	if !ok {
+		return
+	}
+	c := b.Obstacles[j]
+	a := angle.Normalize(math.Atan2(q.Y-c.Y, q.X-c.X))
+	for _, t := range b.onCircle[j] {
+		if angle.Distance(t.angle, a)*c.R < tangentTolerance {
+			return
+		}
+	}
+	b.onCircle[j] = append(b.onCircle[j], tangentPoint{id, a})
+}
+
+// segment adds the straight segment from p to q if it does not pass through
+// an obstacle. Each end is the vertex given by fromID or toID if it is not
+// negative, and otherwise a tangent point on obstacle fromCircle or toCircle.
+func (b *tangentBuilder) segment(fromID, fromCircle int, p Point, toCircle int, q Point) {
+	b.stats.MotionChecks++
+	if !b.segmentFree(p, q) {
+		return
+	}
+	if fromID < 0 {
+		var ok bool
+		if fromID, ok = b.tangentPoint(fromCircle, p); !ok {
+			return
+		}
+	}
+	toID, ok := b.tangentPoint(toCircle, q)
+	if !ok {
+		return
+	}
+	heading := math.Atan2(q.Y-p.Y, q.X-p.X)
+	p.Theta, q.Theta = heading, heading
+	b.connect(fromID, toID, &Motion{From: p, To: q, Cost: math.Hypot(q.X-p.X, q.Y-p.Y)})
+}
+
+// toGoal adds the shortest segment from vertex id to the goal region if it
+// does not pass through an obstacle.
+func (b *tangentBuilder) toGoal(id int) {
+	v, g := b.vertices[id], b.Problem.Goal
+	d := math.Hypot(g.X-v.X, g.Y-v.Y)
+	heading := math.Atan2(g.Y-v.Y, g.X-v.X)
+	p := Point{X: v.X, Y: v.Y, Theta: heading}
+	q := Point{X: v.X + (d-g.R)*math.Cos(heading), Y: v.Y + (d-g.R)*math.Sin(heading), Theta: heading}
+	if d <= g.R {
+		q = p
+	}
+	b.stats.MotionChecks++
+	if b.segmentFree(p, q) {
+		b.connect(id, tangentGoal, &Motion{From: p, To: q, Cost: math.Max(0, d-g.R)})
+	}
+}
+
+// arcs joins every pair of neighboring tangent points on obstacle i by the
+// arc between them, unless it passes through another obstacle or leaves the
+// config space.
+func (b *tangentBuilder) arcs(i int) {
+	c, points := b.Obstacles[i], b.onCircle[i]
+	sort.Slice(points, func(j, k int) bool { return points[j].angle < points[k].angle })
+	if len(points) < 2 {
+		return
+	}
+	for j, from := range points {
+		to := points[(j+1)%len(points)]
+		span := angle.Normalize(to.angle - from.angle)
+		b.stats.MotionChecks++
+		if m := b.arc(i, c, from, span); m != nil {
+			b.connect(from.id, to.id, m)
+		}
+	}
+}
+
+// arc returns the counterclockwise arc of span radians on obstacle i from the
+// tangent point from, or nil if it passes through another obstacle or leaves
+// the config space. The states along it are at most 0.5 apart.
+func (b *tangentBuilder) arc(i int, c Circle, from tangentPoint, span float64) *Motion {
+	for k, o := range b.Obstacles {
+		if k != i && arcBlocked(c, from.angle, span, o) {
+			return nil
+		}
+	}
+	n := int(math.Ceil(span * c.R / 0.5))
+	states := make([]State, n)
+	for k := 1; k <= n; k++ {
+		a := from.angle + span*float64(k)/float64(n)
+		q := Point{X: c.X + c.R*math.Cos(a), Y: c.Y + c.R*math.Sin(a), Theta: angle.Normalize(a + math.Pi/2)}
+		if !b.Space.Contains(q.X, q.Y) {
+			return nil
+		}
+		states[k-1] = q
+	}
+	start := b.vertices[from.id]
+	return &Motion{
+		From: Point{X: start.X, Y: start.Y, Theta: angle.Normalize(from.angle + math.Pi/2)},
+		To:   states[n-1],
+		Path: states,
+		Cost: span * c.R,
+	}
+}
+
+// connect adds m as the edge from one vertex to another, and its reverse as
+// the edge back.
+func (b *tangentBuilder) connect(from, to int, m *Motion) {
+	if cost, ok := b.vertices[from].Neighbors[to]; ok && cost <= m.Cost {
+		return
+	}
+	b.vertices[from].Neighbors[to] = m.Cost
+	b.vertices[to].Neighbors[from] = m.Cost
+	b.motions[[2]int{from, to}] = m
+	b.motions[[2]int{to, from}] = reverse(m)
+}
+
+// reverse returns the motion along m in the opposite direction.
+func reverse(m *Motion) *Motion {
+	turn := func(s State) State {
+		p := Pose(s)
+		p.Theta = angle.Normalize(p.Theta + math.Pi)
+		return p
+	}
+	r := &Motion{From: turn(m.To), To: turn(m.From), Cost: m.Cost}
+	if len(m.Path) > 0 {
+		r.Path = append(r.Path, m.Path[:len(m.Path)-1]...)
+		for i, j := 0, len(r.Path)-1; i < j; i, j = i+1, j-1 {
+			r.Path[i], r.Path[j] = r.Path[j], r.Path[i]
+		}
+		for i, s := range r.Path {
+			r.Path[i] = turn(s)
+		}
+		r.Path = append(r.Path, r.To)
+	}
+	return r
+}
+
+// free returns true if q is inside the config space and not inside an
+// obstacle.
+func (p *TangentGraph) free(q Point) bool {
+	if !p.Space.Contains(q.X, q.Y) {
+		return false
+	}
+	for _, o := range p.Obstacles {
+		if math.Hypot(q.X-o.X, q.Y-o.Y) < o.R-tangentTolerance {
+			return false
+		}
+	}
+	return true
+}
+
+// segmentFree returns true if the segment from p to q does not pass through
+// an obstacle. It may touch their perimeters.
+func (p *TangentGraph) segmentFree(a, b Point) bool {
+	dx, dy := b.X-a.X, b.Y-a.Y
+	length2 := dx*dx + dy*dy
+	for _, o := range p.Obstacles {
+		t := 0.0
+		if length2 > 0 {
+			t = math.Max(0, math.Min(1, ((o.X-a.X)*dx+(o.Y-a.Y)*dy)/length2))
+		}
+		if math.Hypot(a.X+t*dx-o.X, a.Y+t*dy-o.Y) < o.R-tangentTolerance {
+			return false
+		}
+	}
+	return true
+}
+
+// pointTangents returns the points on c where the lines through (x, y) that
+// are tangent to c touch it. There are none if (x, y) is inside c.
+func pointTangents(x, y float64, c Circle) []Point {
+	d := math.Hypot(x-c.X, y-c.Y)
+	if d < c.R {
+		return nil
+	}
+	toPoint := math.Atan2(y-c.Y, x-c.X)
+	half := math.Acos(math.Min(1, c.R/d))
+	var points []Point
+	for _, a := range []float64{toPoint - half, toPoint + half} {
+		points = append(points, Point{X: c.X + c.R*math.Cos(a), Y: c.Y + c.R*math.Sin(a)})
+	}
+	return points
+}
+
+// circleTangents returns the pairs of points where the lines tangent to both
+// circles touch them: two outer tangents, and two inner tangents unless the
+// circles overlap.
+func circleTangents(a, b Circle) [][2]Point {
+	dx, dy := b.X-a.X, b.Y-a.Y
+	d := math.Hypot(dx, dy)
+	if d == 0 {
+		return nil
+	}
+	ux, uy := dx/d, dy/d
+	var pairs [][2]Point
+	// The normal n of a tangent line satisfies n·(b-a) = rA - rB for outer
+	// tangents and rA + rB for inner ones, where b touches on the other side.
+	for _, rb := range []float64{b.R, -b.R} {
+		c := (a.R - rb) / d
+		if c*c > 1 {
+			continue
+		}
+		h := math.Sqrt(1 - c*c)
+		for _, sign := range []float64{-1, 1} {
+			nx, ny := ux*c-sign*h*uy, uy*c+sign*h*ux
+			pairs = append(pairs, [2]Point{
+				{X: a.X + a.R*nx, Y: a.Y + a.R*ny},
+				{X: b.X + rb*nx, Y: b.Y + rb*ny},
+			})
+		}
+	}
+	return pairs
+}
+
+// circleIntersections returns the points where the perimeters of the circles
+// cross. There are none unless they overlap without one containing the
+// other.
+func circleIntersections(a, b Circle) []Point {
+	dx, dy := b.X-a.X, b.Y-a.Y
+	d := math.Hypot(dx, dy)
+	if d >= a.R+b.R || d <= math.Abs(a.R-b.R) {
+		return nil
+	}
+	// The chord between the points is at distance along from the center of a.
+	along := (d*d + a.R*a.R - b.R*b.R) / (2 * d)
+	h := math.Sqrt(math.Max(0, a.R*a.R-along*along))
+	ux, uy := dx/d, dy/d
+	mx, my := a.X+along*ux, a.Y+along*uy
+	return []Point{{X: mx - h*uy, Y: my + h*ux}, {X: mx + h*uy, Y: my - h*ux}}
+}
+
+// arcBlocked returns true if the counterclockwise arc of span radians on c
+// from angle from passes inside o.
+func arcBlocked(c Circle, from, span float64, o Circle) bool {
+	d := math.Hypot(o.X-c.X, o.Y-c.Y)
+	switch {
+	case d >= c.R+o.R-tangentTolerance:
+		return false
+	case d+c.R <= o.R:
+		return true
+	case d+o.R <= c.R:
+		return false
+	}
+	// The perimeter of c is inside o within half of the center direction.
+	center := math.Atan2(o.Y-c.Y, o.X-c.X)
+	half := math.Acos(math.Max(-1, math.Min(1, (c.R*c.R+d*d-o.R*o.R)/(2*c.R*d))))
+	if half*c.R < tangentTolerance {
+		return false
+	}
+	enter := angle.Normalize(center - half - from)
+	return enter < span-tangentTolerance || enter+2*half > 2*math.Pi+tangentTolerance
+}
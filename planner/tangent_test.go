@@ -0,0 +1,125 @@
+package planner
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"testing"
+)
+
+// checkExactPath fails the test unless the solution is a connected path from
+// the start to the goal region whose cost is its length.
+func checkExactPath(t *testing.T, name string, prob Problem, obstacles []Circle, solution *Solution) {
+	equals(t, prob.Start, solution.Path[0].From)
+	end := Pose(solution.Path[len(solution.Path)-1].To)
+	assert(t, math.Hypot(end.X-prob.Goal.X, end.Y-prob.Goal.Y) <= prob.Goal.R+1e-9, "%s: path should end in goal", name)
+	var cost float64
+	for i, m := range solution.Path {
+		if i > 0 {
+			equals(t, solution.Path[i-1].To, m.From)
+		}
+		from := Pose(m.From)
+		for _, s := range append([]State{m.From}, m.Path...) {
+			q := Pose(s)
+			for _, o := range obstacles {
+				assert(t, math.Hypot(q.X-o.X, q.Y-o.Y) > o.R-1e-6, "%s: motion %d passes through %v", name, i, o)
+			}
+			if len(m.Path) > 0 {
+				cost += math.Hypot(q.X-from.X, q.Y-from.Y)
+				from = q
+			}
+		}
+		if len(m.Path) == 0 {
+			to := Pose(m.To)
+			cost += math.Hypot(to.X-from.X, to.Y-from.Y)
+		}
+	}
+	// The arcs are discretized, so the path through the states is slightly
+	// shorter than the cost.
+	assert(t, cost <= solution.Cost+1e-9 && cost > 0.999*solution.Cost, "%s: cost %f should be the path length %f", name, solution.Cost, cost)
+}
+
+func TestTangentGraph(t *testing.T) {
+	prob, cSpace, checker, shortest := aroundObstacle()
+	solution, err := NewTangentGraph(prob, cSpace, checker.Obstacles).Plan(context.Background())
+	ok(t, err)
+	checkExactPath(t, "around", prob, checker.Obstacles, solution)
+	assert(t, math.Abs(solution.Cost-shortest) < 1e-9, "cost %f should be the shortest %f", solution.Cost, shortest)
+	equals(t, 3, len(solution.Path))
+	assert(t, len(solution.Tree) > 0, "expected the search tree")
+}
+
+func TestTangentGraphBaseline(t *testing.T) {
+	config, obstacles, _, err := LoadConfig("../hw2/problems.json")
+	ok(t, err)
+	for i, prob := range config.Problems {
+		name := fmt.Sprintf("hw2 problem %d", i)
+		exact, err := NewTangentGraph(prob, config.ConfigSpace, obstacles).Plan(context.Background())
+		ok(t, err)
+		checkExactPath(t, name, prob, obstacles, exact)
+
+		// No sampled path can be shorter than the exact one, and BIT* should
+		// come close.
+		checker := PointChecker{Obstacles: obstacles, Space: config.ConfigSpace}
+		params := BITStarParams{BatchSize: 100, MaxIterations: 20000}
+		solution, err := NewBITStar(prob, config.ConfigSpace, checker, 1, params).Plan(context.Background())
+		ok(t, err)
+		assert(t, exact.Cost <= solution.Cost, "%s: exact cost %f should be at most the bitstar cost %f", name, exact.Cost, solution.Cost)
+		assert(t, solution.Cost < 1.05*exact.Cost, "%s: bitstar cost %f should be close to the exact cost %f", name, solution.Cost, exact.Cost)
+	}
+}
+
+func TestTangentGraphOverlap(t *testing.T) {
+	cSpace := ConfigSpace{XMin: 0, XMax: 60, YMin: 0, YMax: 60}
+	prob := Problem{Start: Point{X: 30, Y: 5}, Goal: Circle{X: 30, Y: 55, R: 2}, Epsilon: 2, GoalBias: 0.05}
+	cases := map[string][]Circle{
+		"pair":  {{X: 24, Y: 30, R: 8}, {X: 36, Y: 30, R: 8}},
+		"notch": {{X: 20, Y: 34, R: 7}, {X: 30, Y: 28, R: 7}, {X: 40, Y: 34, R: 7}},
+		"chain": {{X: 12, Y: 30, R: 6}, {X: 21, Y: 30, R: 6}, {X: 30, Y: 30, R: 6}, {X: 39, Y: 30, R: 6}, {X: 44, Y: 36, R: 5}},
+	}
+	for name, obstacles := range cases {
+		exact, err := NewTangentGraph(prob, cSpace, obstacles).Plan(context.Background())
+		ok(t, err)
+		checkExactPath(t, name, prob, obstacles, exact)
+
+		checker := PointChecker{Obstacles: obstacles, Space: cSpace}
+		params := BITStarParams{BatchSize: 100, MaxIterations: 20000}
+		solution, err := NewBITStar(prob, cSpace, checker, 1, params).Plan(context.Background())
+		ok(t, err)
+		assert(t, exact.Cost <= solution.Cost, "%s: exact cost %f should be at most the bitstar cost %f", name, exact.Cost, solution.Cost)
+		assert(t, solution.Cost < 1.05*exact.Cost, "%s: bitstar cost %f should be close to the exact cost %f", name, solution.Cost, exact.Cost)
+	}
+}
+
+func TestCircleIntersections(t *testing.T) {
+	a := Circle{X: 0, Y: 0, R: 5}
+	points := circleIntersections(a, Circle{X: 8, Y: 0, R: 5})
+	equals(t, 2, len(points))
+	for _, q := range points {
+		assert(t, math.Abs(q.X-4) < 1e-9 && math.Abs(math.Abs(q.Y)-3) < 1e-9, "expected (4, ±3), got %v", q)
+	}
+	equals(t, 0, len(circleIntersections(a, Circle{X: 10, Y: 0, R: 5})))
+	equals(t, 0, len(circleIntersections(a, Circle{X: 1, Y: 0, R: 2})))
+}
+
+func TestTangentGraphNoSolution(t *testing.T) {
+	cSpace := ConfigSpace{XMin: 0, XMax: 50, YMin: 0, YMax: 50}
+	prob := Problem{Start: Point{X: 5, Y: 5}, Goal: Circle{X: 45, Y: 45, R: 2}}
+	cases := map[string][]Circle{
+		"enclosed goal":  {{X: 45, Y: 45, R: 4}},
+		"enclosed start": {{X: 5, Y: 5, R: 3}},
+		"wall":           {{X: 10, Y: 25, R: 12}, {X: 30, Y: 25, R: 12}, {X: 50, Y: 25, R: 12}, {X: -10, Y: 25, R: 12}},
+	}
+	for name, obstacles := range cases {
+		solution, err := NewTangentGraph(prob, cSpace, obstacles).Plan(context.Background())
+		_, isNoSol := err.(*NoSolutionError)
+		assert(t, isNoSol, "%s: expected a *NoSolutionError, got %v", name, err)
+		assert(t, solution == nil || len(solution.Path) == 0, "%s: expected no path", name)
+	}
+
+	prob.Start = Point{X: 44, Y: 44}
+	solution, err := NewTangentGraph(prob, cSpace, nil).Plan(context.Background())
+	ok(t, err)
+	equals(t, 0, len(solution.Path))
+	equals(t, 0.0, solution.Cost)
+}
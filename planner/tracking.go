@@ -0,0 +1,272 @@
+package planner
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/hdhauk/enae788v/angle"
+)
+
+// crossTrackWindow is the number of reference steps before and after the
+// current time searched when computing cross-track error.
+const crossTrackWindow = 50
+
+// TrackingConfig configures a closed-loop simulation of a planned trajectory.
+type TrackingConfig struct {
+	Q             [5]float64 // LQR state weights on x, y, θ, v, w errors
+	R             [2]float64 // LQR control weights on a, γ corrections
+	PositionNoise float64    // standard deviation of position noise added per step
+	HeadingNoise  float64    // standard deviation of heading noise added per step
+	VelocityNoise float64    // standard deviation of v and w noise added per step
+	Seed          int64
+}
+
+// DefaultTrackingConfig returns a tracking configuration that works for the
+// problems in hw4/problems.json.
+func DefaultTrackingConfig() TrackingConfig {
+	return TrackingConfig{
+		Q:             [5]float64{10, 10, 5, 1, 1},
+		R:             [2]float64{1, 1},
+		PositionNoise: 0.01,
+		HeadingNoise:  0.005,
+		VelocityNoise: 0.01,
+		Seed:          1,
+	}
+}
+
+// Weights is a flag.Value that sets a fixed number of weights from a comma
+// separated list, such as Weights(cfg.Q[:]) for the LQR state weights.
+type Weights []float64
+
+func (w Weights) String() string {
+	parts := make([]string, len(w))
+	for i, x := range w {
+		parts[i] = strconv.FormatFloat(x, 'g', -1, 64)
+	}
+	return strings.Join(parts, ",")
+}
+
+// Set parses s into w. It needs exactly len(w) weights, none of them
+// negative.
+func (w Weights) Set(s string) error {
+	parts := strings.Split(s, ",")
+	if len(parts) != len(w) {
+		return errors.Errorf("expected %d comma separated weights, got %d", len(w), len(parts))
+	}
+	parsed := make([]float64, len(w))
+	for i, part := range parts {
+		x, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			return errors.Wrapf(err, "invalid weight %q", part)
+		}
+		if x < 0 {
+			return errors.Errorf("weight %v is negative", x)
+		}
+		parsed[i] = x
+	}
+	copy(w, parsed)
+	return nil
+}
+
+// TrackingReport summarizes how well a closed-loop simulation followed the
+// planned trajectory.
+type TrackingReport struct {
+	Steps          int
+	RMSError       float64 // root mean square distance to the planned position at the same time
+	MaxError       float64
+	MaxCrossTrack  float64 // largest distance to the closest point on the planned path
+	RMSHeading     float64 // root mean square heading error in radians
+	FinalError     float64 // distance between final and planned final position
+	Collisions     int     // number of simulated states in collision or outside the config space
+	FirstCollision float64 // time of first collision, or -1 if none
+	ReachedGoal    bool
+	Trajectory     []*PathPoint
+}
+
+func (r TrackingReport) String() string {
+	collision := "none"
+	if r.Collisions > 0 {
+		collision = fmt.Sprintf("%d states, first at t=%.2f", r.Collisions, r.FirstCollision)
+	}
+	return fmt.Sprintf("tracking: steps=%d error rms=%.4f max=%.4f cross-track max=%.4f heading rms=%.4f final error=%.4f collisions=%s reached goal=%t",
+		r.Steps, r.RMSError, r.MaxError, r.MaxCrossTrack, r.RMSHeading, r.FinalError, collision, r.ReachedGoal)
+}
+
+// SimulateTracking replays the planned trajectory through the unicycle
+// dynamics in closed loop. The planned controls are applied as feedforward,
+// corrected by a time-varying LQR controller computed on the dynamics
+// linearized around the plan. Gaussian process noise is added after every
+// step, and every simulated state is checked for collisions with the obstacles
+// and the walls of the config space.
+func SimulateTracking(start Point, path []*PathPoint, prob Problem, cSpace ConfigSpace, obstacles []Circle, bot Robot, cfg TrackingConfig) TrackingReport {
+	report := TrackingReport{FirstCollision: -1}
+	if len(path) == 0 {
+		return report
+	}
+
+	rng := rand.New(rand.NewSource(cfg.Seed))
+	ref := append([]*PathPoint{{Point: start}}, path...)
+	gains := lqrGains(ref, cfg.Q, cfg.R)
+
+	checker := FootprintChecker{Obstacles: obstacles, Space: cSpace, Robot: bot}
+	X := start
+	var sumErr, sumHeading float64
+	for k := 0; k < len(ref)-1; k++ {
+		// Feedforward from the plan, with feedback on the deviation from it.
+		r := ref[k]
+		h := ref[k+1].T - r.T
+		e := [5]float64{X.X - r.X, X.Y - r.Y, angle.Diff(r.Theta, X.Theta), X.V - r.V, X.W - r.W}
+		a, gamma := ref[k+1].A, ref[k+1].Gamma
+		for j := 0; j < 5; j++ {
+			a -= gains[k][0][j] * e[j]
+			gamma -= gains[k][1][j] * e[j]
+		}
+		a = clamp(a, cSpace.AMin, cSpace.AMax)
+		gamma = clamp(gamma, cSpace.GammaMin, cSpace.GammaMax)
+
+		next := Euler(X, h, a, gamma)
+		next.X += cfg.PositionNoise * rng.NormFloat64()
+		next.Y += cfg.PositionNoise * rng.NormFloat64()
+		next.Theta += cfg.HeadingNoise * rng.NormFloat64()
+		next.V = clamp(next.V+cfg.VelocityNoise*rng.NormFloat64(), cSpace.VMin, cSpace.VMax)
+		next.W = clamp(next.W+cfg.VelocityNoise*rng.NormFloat64(), cSpace.WMin, cSpace.WMax)
+		next.T = ref[k+1].T
+		report.Trajectory = append(report.Trajectory, &next)
+		X = next.Point
+
+		// Metrics.
+		planned := ref[k+1]
+		err := math.Hypot(planned.X-X.X, planned.Y-X.Y)
+		sumErr += err * err
+		report.MaxError = math.Max(report.MaxError, err)
+		report.MaxCrossTrack = math.Max(report.MaxCrossTrack, crossTrackError(ref, k+1, crossTrackWindow, X.X, X.Y))
+		headingErr := angle.Distance(X.Theta, planned.Theta)
+		sumHeading += headingErr * headingErr
+
+		if !checker.Valid(X) {
+			if report.Collisions == 0 {
+				report.FirstCollision = next.T
+			}
+			report.Collisions++
+		}
+		report.Steps++
+	}
+
+	report.RMSError = math.Sqrt(sumErr / float64(report.Steps))
+	report.RMSHeading = math.Sqrt(sumHeading / float64(report.Steps))
+	last := ref[len(ref)-1]
+	report.FinalError = math.Hypot(last.X-X.X, last.Y-X.Y)
+	report.ReachedGoal = NewGoalRegion(prob, cSpace).Satisfied(X)
+	return report
+}
+
+// lqrGains computes the finite horizon LQR feedback gains for following ref.
+// The gain for step k maps the state error at ref[k] to a correction of the
+// controls applied from ref[k] to ref[k+1].
+func lqrGains(ref []*PathPoint, q [5]float64, r [2]float64) [][2][5]float64 {
+	n := len(ref) - 1
+	gains := make([][2][5]float64, n)
+
+	var P [5][5]float64
+	for i := range q {
+		P[i][i] = q[i]
+	}
+	for k := n - 1; k >= 0; k-- {
+		A, B := linearize(ref[k], ref[k+1].T-ref[k].T)
+
+		// K = (R + BᵀPB)⁻¹ BᵀPA
+		var PA [5][5]float64
+		var PB [5][2]float64
+		for i := 0; i < 5; i++ {
+			for j := 0; j < 5; j++ {
+				for l := 0; l < 5; l++ {
+					PA[i][j] += P[i][l] * A[l][j]
+				}
+			}
+			for j := 0; j < 2; j++ {
+				for l := 0; l < 5; l++ {
+					PB[i][j] += P[i][l] * B[l][j]
+				}
+			}
+		}
+		var S [2][2]float64
+		var BtPA [2][5]float64
+		for i := 0; i < 2; i++ {
+			for j := 0; j < 2; j++ {
+				for l := 0; l < 5; l++ {
+					S[i][j] += B[l][i] * PB[l][j]
+				}
+			}
+			S[i][i] += r[i]
+			for j := 0; j < 5; j++ {
+				for l := 0; l < 5; l++ {
+					BtPA[i][j] += B[l][i] * PA[l][j]
+				}
+			}
+		}
+		det := S[0][0]*S[1][1] - S[0][1]*S[1][0]
+		Sinv := [2][2]float64{{S[1][1] / det, -S[0][1] / det}, {-S[1][0] / det, S[0][0] / det}}
+		var K [2][5]float64
+		for i := 0; i < 2; i++ {
+			for j := 0; j < 5; j++ {
+				K[i][j] = Sinv[i][0]*BtPA[0][j] + Sinv[i][1]*BtPA[1][j]
+			}
+		}
+		gains[k] = K
+
+		// P = Q + Aᵀ P (A - BK)
+		var ABK [5][5]float64
+		for i := 0; i < 5; i++ {
+			for j := 0; j < 5; j++ {
+				ABK[i][j] = A[i][j] - B[i][0]*K[0][j] - B[i][1]*K[1][j]
+			}
+		}
+		var next [5][5]float64
+		for i := 0; i < 5; i++ {
+			for j := 0; j < 5; j++ {
+				for l := 0; l < 5; l++ {
+					for m := 0; m < 5; m++ {
+						next[i][j] += A[l][i] * P[l][m] * ABK[m][j]
+					}
+				}
+			}
+			next[i][i] += q[i]
+		}
+		P = next
+	}
+	return gains
+}
+
+// linearize returns the Jacobians of the euler step with respect to the state
+// (x, y, θ, v, w) and the controls (a, γ) at p.
+func linearize(p *PathPoint, h float64) (A [5][5]float64, B [5][2]float64) {
+	for i := 0; i < 5; i++ {
+		A[i][i] = 1
+	}
+	sin, cos := math.Sin(p.Theta), math.Cos(p.Theta)
+	A[0][2], A[0][3] = -h*p.V*sin, h*cos
+	A[1][2], A[1][3] = h*p.V*cos, h*sin
+	A[2][4] = h
+	B[3][0] = h
+	B[4][1] = h
+	return A, B
+}
+
+// crossTrackError returns the distance from (x, y) to the closest reference
+// point at most window steps before or after index k. Limiting the search to
+// nearby times keeps a path that crosses itself from hiding tracking errors.
+func crossTrackError(ref []*PathPoint, k, window int, x, y float64) float64 {
+	shortest := math.MaxFloat64
+	for i := k - window; i <= k+window; i++ {
+		if i < 0 || i >= len(ref) {
+			continue
+		}
+		shortest = math.Min(shortest, math.Hypot(ref[i].X-x, ref[i].Y-y))
+	}
+	return shortest
+}
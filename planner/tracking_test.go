@@ -0,0 +1,76 @@
+package planner
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSimulateTracking(t *testing.T) {
+	prob, cSpace, path := testTrajectory(t)
+	bot := Robot{{X: 0, Y: 0}, {X: 0.5, Y: 0}}
+
+	cfg := DefaultTrackingConfig()
+	cfg.PositionNoise, cfg.HeadingNoise, cfg.VelocityNoise = 0, 0, 0
+	report := SimulateTracking(prob.Start, path, prob, cSpace, nil, bot, cfg)
+	equals(t, len(path), report.Steps)
+	assert(t, report.MaxError < 1e-9, "noise free tracking should follow the plan exactly, max error %f", report.MaxError)
+	assert(t, report.ReachedGoal, "should reach goal")
+
+	cfg = DefaultTrackingConfig()
+	cfg.PositionNoise = 0.05
+	report = SimulateTracking(prob.Start, path, prob, cSpace, nil, bot, cfg)
+	assert(t, report.MaxError > 0, "noise should cause tracking error")
+	assert(t, report.MaxError < 0.5, "tracking error too large: %f", report.MaxError)
+	assert(t, report.MaxCrossTrack <= report.MaxError, "cross-track error should not exceed tracking error")
+	equals(t, 0, report.Collisions)
+	equals(t, -1.0, report.FirstCollision)
+}
+
+func TestSimulateTrackingRecovers(t *testing.T) {
+	prob, cSpace, path := testTrajectory(t)
+	cfg := DefaultTrackingConfig()
+	cfg.PositionNoise, cfg.HeadingNoise, cfg.VelocityNoise = 0, 0, 0
+
+	// Start off the planned trajectory and let the controller pull it back.
+	offset := prob.Start
+	offset.Y += 0.3
+	offset.Theta += 0.1
+	report := SimulateTracking(offset, path, prob, cSpace, nil, nil, cfg)
+	assert(t, report.FinalError < 0.1, "controller should converge to the plan, final error %f", report.FinalError)
+}
+
+func TestSimulateTrackingCollision(t *testing.T) {
+	prob, cSpace, path := testTrajectory(t)
+	last := path[len(path)-1]
+	obstacles := []Circle{{X: last.X, Y: last.Y, R: 0.5}}
+
+	report := SimulateTracking(prob.Start, path, prob, cSpace, obstacles, Robot{{}}, DefaultTrackingConfig())
+	assert(t, report.Collisions > 0, "should collide with obstacle on the path")
+	assert(t, report.FirstCollision > 0 && report.FirstCollision <= last.T, "unexpected first collision time %f", report.FirstCollision)
+}
+
+func TestSimulateTrackingWalls(t *testing.T) {
+	prob, cSpace, path := testTrajectory(t)
+	cfg := DefaultTrackingConfig()
+	cfg.PositionNoise, cfg.HeadingNoise, cfg.VelocityNoise = 0, 0, 0
+
+	// A wall just short of the furthest point of the trajectory.
+	cSpace.XMax = 0
+	for _, p := range path {
+		cSpace.XMax = math.Max(cSpace.XMax, p.X-0.1)
+	}
+	report := SimulateTracking(prob.Start, path, prob, cSpace, nil, Robot{{}}, cfg)
+	assert(t, report.Collisions > 0, "should leave the config space")
+}
+
+func TestWeights(t *testing.T) {
+	cfg := DefaultTrackingConfig()
+	ok(t, Weights(cfg.Q[:]).Set("1, 2,3,4,5"))
+	equals(t, [5]float64{1, 2, 3, 4, 5}, cfg.Q)
+	equals(t, "1,2,3,4,5", Weights(cfg.Q[:]).String())
+
+	assert(t, Weights(cfg.R[:]).Set("1") != nil, "expected an error for too few weights")
+	assert(t, Weights(cfg.R[:]).Set("1,x") != nil, "expected an error for a weight that is not a number")
+	assert(t, Weights(cfg.R[:]).Set("1,-1") != nil, "expected an error for a negative weight")
+	equals(t, [2]float64{1, 1}, cfg.R)
+}
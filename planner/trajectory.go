@@ -0,0 +1,178 @@
+package planner
+
+import (
+	"encoding/csv"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/hdhauk/enae788v/angle"
+)
+
+// trajectoryHeader is the first row of every delivery csv.
+var trajectoryHeader = []string{"t", "x", "y", "theta", "v", "w", "a", "gamma"}
+
+// TrajectoryRow is one line of the delivery csv. The state is the state at time
+// T, and A and Gamma are the controls applied from T until the time of the next row.
+type TrajectoryRow struct {
+	T, X, Y, Theta, V, W, A, Gamma float64
+}
+
+// TrajectoryRows converts a planned path into delivery rows. The first row is
+// the start state, and the controls of each row are the ones used to reach the
+// state in the following row. The last row has no controls applied.
+func TrajectoryRows(start Point, path []*PathPoint) []TrajectoryRow {
+	rows := []TrajectoryRow{{X: start.X, Y: start.Y, Theta: start.Theta, V: start.V, W: start.W}}
+	for _, p := range path {
+		rows[len(rows)-1].A, rows[len(rows)-1].Gamma = p.A, p.Gamma
+		rows = append(rows, TrajectoryRow{T: p.T, X: p.X, Y: p.Y, Theta: p.Theta, V: p.V, W: p.W})
+	}
+	return rows
+}
+
+// WriteTrajectory writes the path as a delivery csv, with a header row and
+// every value formatted with the given number of decimals.
+func WriteTrajectory(w io.Writer, start Point, path []*PathPoint, precision int) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(trajectoryHeader); err != nil {
+		return errors.Wrap(err, "could not write header")
+	}
+
+	format := func(f float64) string {
+		return strconv.FormatFloat(f, 'f', precision, 64)
+	}
+	for _, r := range TrajectoryRows(start, path) {
+		record := []string{
+			format(r.T), format(r.X), format(r.Y), format(r.Theta),
+			format(r.V), format(r.W), format(r.A), format(r.Gamma),
+		}
+		if err := cw.Write(record); err != nil {
+			return errors.Wrap(err, "could not write row")
+		}
+	}
+	cw.Flush()
+	return errors.Wrap(cw.Error(), "could not flush csv")
+}
+
+// ReadTrajectory parses a delivery csv. The header row is optional.
+func ReadTrajectory(reader io.Reader) ([]TrajectoryRow, error) {
+	r := csv.NewReader(reader)
+	r.FieldsPerRecord = len(trajectoryHeader)
+	r.TrimLeadingSpace = true
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read csv")
+	}
+	if len(records) > 0 && strings.TrimSpace(records[0][0]) == trajectoryHeader[0] {
+		records = records[1:]
+	}
+
+	var rows []TrajectoryRow
+	for i, record := range records {
+		var values [8]float64
+		for j, field := range record {
+			f, err := strconv.ParseFloat(strings.TrimSpace(field), 64)
+			if err != nil {
+				return nil, errors.Wrapf(err, "row %d: non-float value in column %s", i, trajectoryHeader[j])
+			}
+			values[j] = f
+		}
+		rows = append(rows, TrajectoryRow{values[0], values[1], values[2], values[3],
+			values[4], values[5], values[6], values[7]})
+	}
+	if len(rows) == 0 {
+		return nil, errors.New("trajectory has no rows")
+	}
+	return rows, nil
+}
+
+// ValidateTrajectory re-simulates the rows through the dynamics and returns
+// every violation found: a start that differs from the problem or is not at
+// time 0, time that does not increase, controls outside the config space,
+// states that does not match the simulation, collisions and a final state
+// that is not in the goal. The tolerance is used when comparing recorded and
+// simulated states.
+func ValidateTrajectory(rows []TrajectoryRow, prob Problem, cSpace ConfigSpace, checker CollisionChecker, tolerance float64) []error {
+	var violations []error
+	violation := func(row int, format string, args ...interface{}) {
+		violations = append(violations, errors.Errorf("row %d: "+format, append([]interface{}{row}, args...)...))
+	}
+
+	first := rows[0]
+	start := prob.Start
+	if math.Abs(first.X-start.X) > tolerance || math.Abs(first.Y-start.Y) > tolerance ||
+		math.Abs(angle.Diff(start.Theta, first.Theta)) > tolerance ||
+		math.Abs(first.V-start.V) > tolerance || math.Abs(first.W-start.W) > tolerance {
+		violation(0, "state (%.4f, %.4f, θ=%.4f, v=%.4f, w=%.4f) is not the problem start (%.4f, %.4f, θ=%.4f, v=%.4f, w=%.4f)",
+			first.X, first.Y, first.Theta, first.V, first.W, start.X, start.Y, start.Theta, start.V, start.W)
+	}
+	if first.T != 0 {
+		violation(0, "time %.4f is not 0", first.T)
+	}
+
+	for i, r := range rows {
+		if !checker.Valid(r.pathPoint()) {
+			violation(i, "state (%.4f, %.4f, θ=%.4f, v=%.4f, w=%.4f) is in collision or outside the config space",
+				r.X, r.Y, r.Theta, r.V, r.W)
+		}
+		if i == len(rows)-1 {
+			break
+		}
+
+		if r.A < cSpace.AMin-tolerance || r.A > cSpace.AMax+tolerance {
+			violation(i, "acceleration a=%.4f outside [%.4f, %.4f]", r.A, cSpace.AMin, cSpace.AMax)
+		}
+		if r.Gamma < cSpace.GammaMin-tolerance || r.Gamma > cSpace.GammaMax+tolerance {
+			violation(i, "steering acceleration γ=%.4f outside [%.4f, %.4f]", r.Gamma, cSpace.GammaMin, cSpace.GammaMax)
+		}
+
+		next := rows[i+1]
+		dt := next.T - r.T
+		if dt <= 0 {
+			violation(i+1, "time %.4f does not increase from %.4f", next.T, r.T)
+			continue
+		}
+
+		// Simulate with steps no longer than the planner timestep, collision
+		// checking every intermediate state.
+		steps := math.Ceil(dt/Timestep - 1e-9)
+		h := dt / steps
+		X := Point{X: r.X, Y: r.Y, Theta: r.Theta, V: r.V, W: r.W}
+		for s := 0.0; s < steps; s++ {
+			p := Euler(X, h, r.A, r.Gamma)
+			if s < steps-1 && !checker.Valid(&p) {
+				violation(i, "simulated state (%.4f, %.4f) at t=%.4f is in collision or outside the config space",
+					p.X, p.Y, r.T+(s+1)*h)
+			}
+			X = p.Point
+		}
+
+		if math.Abs(X.X-next.X) > tolerance || math.Abs(X.Y-next.Y) > tolerance ||
+			math.Abs(angle.Diff(next.Theta, X.Theta)) > tolerance || math.Abs(X.V-next.V) > tolerance ||
+			math.Abs(X.W-next.W) > tolerance {
+			violation(i+1, "recorded state (%.4f, %.4f, θ=%.4f, v=%.4f, w=%.4f) differs from simulated (%.4f, %.4f, θ=%.4f, v=%.4f, w=%.4f)",
+				next.X, next.Y, next.Theta, next.V, next.W, X.X, X.Y, X.Theta, X.V, X.W)
+		}
+	}
+
+	last := rows[len(rows)-1]
+	if !NewGoalRegion(prob, cSpace).Satisfied(last.pathPoint()) {
+		violation(len(rows)-1, "final state (%.4f, %.4f, θ=%.4f, v=%.4f, w=%.4f) does not satisfy the goal constraints",
+			last.X, last.Y, last.Theta, last.V, last.W)
+	}
+
+	return violations
+}
+
+func (r TrajectoryRow) pathPoint() *PathPoint {
+	return &PathPoint{Point: Point{X: r.X, Y: r.Y, Theta: r.Theta, V: r.V, W: r.W}, A: r.A, Gamma: r.Gamma, T: r.T}
+}
+
+// PrecisionTolerance returns the tolerance needed to compare states that have
+// been rounded to the given number of decimals.
+func PrecisionTolerance(precision int) float64 {
+	return 3 * math.Pow(10, -float64(precision))
+}
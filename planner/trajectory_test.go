@@ -0,0 +1,105 @@
+package planner
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+)
+
+func testTrajectory(t *testing.T) (Problem, ConfigSpace, []*PathPoint) {
+	cSpace := ConfigSpace{
+		XMin: 0, XMax: 100,
+		YMin: 0, YMax: 100,
+		VMin: -5, VMax: 5,
+		WMin: -5, WMax: 5,
+		AMin: -2, AMax: 2,
+		GammaMin: -1, GammaMax: 1,
+	}
+	prob := Problem{Start: Point{X: 10, Y: 10}, Goal: Circle{X: 12, Y: 10, R: 5}}
+	steer := Kinodynamic{Space: cSpace, Epsilon: 1}
+
+	first := steer.Steer(&PathPoint{Point: prob.Start}, Point{X: 20, Y: 10, V: 2})
+	second := steer.Steer(first.To, Point{X: 30, Y: 20, V: 1, W: 0.5})
+
+	return prob, cSpace, Trajectory([]*Motion{first, second})
+}
+
+func TestTrajectoryRows(t *testing.T) {
+	prob, _, path := testTrajectory(t)
+	rows := TrajectoryRows(prob.Start, path)
+
+	equals(t, len(path)+1, len(rows))
+	equals(t, 0.0, rows[0].T)
+	equals(t, prob.Start.X, rows[0].X)
+	for i, p := range path {
+		equals(t, p.T, rows[i+1].T)
+		equals(t, p.A, rows[i].A)
+		equals(t, p.Gamma, rows[i].Gamma)
+		equals(t, p.V, rows[i+1].V)
+		equals(t, p.W, rows[i+1].W)
+	}
+	last := rows[len(rows)-1]
+	equals(t, 0.0, last.A)
+	equals(t, 0.0, last.Gamma)
+}
+
+func TestWriteReadTrajectory(t *testing.T) {
+	prob, cSpace, path := testTrajectory(t)
+	safe := CheckerFunc(func(s State) bool { return true })
+
+	var buf bytes.Buffer
+	ok(t, WriteTrajectory(&buf, prob.Start, path, 4))
+	assert(t, strings.HasPrefix(buf.String(), "t,x,y,theta,v,w,a,gamma\n"), "expected header row")
+
+	rows, err := ReadTrajectory(&buf)
+	ok(t, err)
+	equals(t, len(path)+1, len(rows))
+	equals(t, 0, len(ValidateTrajectory(rows, prob, cSpace, safe, PrecisionTolerance(4))))
+
+	// A shorter rewrite must not leave any stale rows behind.
+	buf.Reset()
+	ok(t, WriteTrajectory(&buf, prob.Start, path[:3], 2))
+	rows, err = ReadTrajectory(&buf)
+	ok(t, err)
+	equals(t, 4, len(rows))
+}
+
+func TestValidateTrajectory(t *testing.T) {
+	prob, cSpace, path := testTrajectory(t)
+	safe := CheckerFunc(func(s State) bool { return true })
+	tol := PrecisionTolerance(4)
+
+	tampered := TrajectoryRows(prob.Start, path)
+	tampered[5].X += 0.1
+	equals(t, 2, len(ValidateTrajectory(tampered, prob, cSpace, safe, tol)))
+
+	tampered = TrajectoryRows(prob.Start, path)
+	tampered[0].Theta += 0.1
+	// The bad start also breaks the simulation of the first motion.
+	equals(t, 2, len(ValidateTrajectory(tampered, prob, cSpace, safe, tol)))
+
+	tampered = TrajectoryRows(prob.Start, path)
+	tampered[0].Theta += 2 * math.Pi
+	equals(t, 0, len(ValidateTrajectory(tampered, prob, cSpace, safe, tol)))
+
+	tampered = TrajectoryRows(prob.Start, path)
+	tampered[0].T = -0.1
+	equals(t, 2, len(ValidateTrajectory(tampered, prob, cSpace, safe, tol)))
+
+	tampered = TrajectoryRows(prob.Start, path)
+	tampered[3].A = cSpace.AMax + 1
+	assert(t, len(ValidateTrajectory(tampered, prob, cSpace, safe, tol)) > 0, "expected acceleration violation")
+
+	tampered = TrajectoryRows(prob.Start, path)
+	tampered[4].T = tampered[3].T
+	assert(t, len(ValidateTrajectory(tampered, prob, cSpace, safe, tol)) > 0, "expected time violation")
+
+	farGoal := prob
+	farGoal.Goal = Circle{X: 90, Y: 90, R: 1}
+	equals(t, 1, len(ValidateTrajectory(TrajectoryRows(prob.Start, path), farGoal, cSpace, safe, tol)))
+
+	unsafe := CheckerFunc(func(s State) bool { return s.(*PathPoint).X < 11 })
+	assert(t, len(ValidateTrajectory(TrajectoryRows(prob.Start, path), prob, cSpace, unsafe, tol)) > 0,
+		"expected collision violation")
+}
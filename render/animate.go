@@ -0,0 +1,261 @@
+package render
+
+import (
+	"image"
+	"image/color"
+	"image/gif"
+	"io"
+	"math"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/hdhauk/enae788v/angle"
+	"github.com/hdhauk/enae788v/planner"
+)
+
+// AnimationOptions configures an animation of the robot moving along the path
+// of a solution.
+type AnimationOptions struct {
+	Options
+	Frames     int           // frames of the robot moving along the path
+	TreeFrames int           // frames of the tree growing before the robot moves, 0 to draw the whole tree throughout
+	Delay      time.Duration // time between frames of a GIF
+}
+
+// DefaultAnimationOptions moves the robot in 100 frames at 25 frames per
+// second, with the whole tree drawn throughout.
+var DefaultAnimationOptions = AnimationOptions{Options: DefaultOptions, Frames: 100, Delay: 40 * time.Millisecond}
+
+// frame is a single image of an animation: the scene drawn with the first
+// tree motions of the solution, and the robot at pose if moving.
+type frame struct {
+	tree   int
+	moving bool
+	pose   planner.Point
+}
+
+// frames returns the frames of an animation. The robot moves at the real
+// timestamps of trajectories, and at a uniform speed along other paths.
+func frames(s Scene, opts AnimationOptions) []frame {
+	sol := s.Solution
+	if sol == nil {
+		return nil
+	}
+	var fs []frame
+	for i := 1; i <= opts.TreeFrames; i++ {
+		fs = append(fs, frame{tree: (len(sol.Tree)*i + opts.TreeFrames - 1) / opts.TreeFrames})
+	}
+	poses := sweep(planner.PathStates(sol.Path), opts.Frames)
+	for _, p := range poses {
+		fs = append(fs, frame{tree: len(sol.Tree), moving: true, pose: p})
+	}
+	return fs
+}
+
+// sweep returns n poses evenly spaced along states, in time if every state
+// is a *planner.PathPoint and in distance otherwise.
+func sweep(states []planner.State, n int) []planner.Point {
+	if len(states) == 0 || n <= 0 {
+		return nil
+	}
+	params := make([]float64, len(states))
+	timed := true
+	for _, s := range states {
+		if _, ok := s.(*planner.PathPoint); !ok {
+			timed = false
+		}
+	}
+	for i := 1; i < len(states); i++ {
+		if timed {
+			params[i] = states[i].(*planner.PathPoint).T - states[0].(*planner.PathPoint).T
+			continue
+		}
+		a, b := planner.Pose(states[i-1]), planner.Pose(states[i])
+		params[i] = params[i-1] + math.Hypot(b.X-a.X, b.Y-a.Y)
+	}
+
+	total := params[len(params)-1]
+	poses := make([]planner.Point, 0, n)
+	j := 0
+	for k := 0; k < n; k++ {
+		at := total
+		if n > 1 {
+			at = total * float64(k) / float64(n-1)
+		}
+		for j < len(states)-2 && params[j+1] < at {
+			j++
+		}
+		if len(states) == 1 {
+			poses = append(poses, planner.Pose(states[0]))
+			continue
+		}
+		a, b := planner.Pose(states[j]), planner.Pose(states[j+1])
+		t := 1.0
+		if span := params[j+1] - params[j]; span > 0 {
+			t = math.Max(0, math.Min(1, (at-params[j])/span))
+		}
+		poses = append(poses, planner.Point{
+			X:     a.X + t*(b.X-a.X),
+			Y:     a.Y + t*(b.Y-a.Y),
+			Theta: angle.Lerp(a.Theta, b.Theta, t),
+		})
+	}
+	return poses
+}
+
+// drawStill draws everything in a frame of an animation but the robot. The
+// footprint along the path is left out, since the robot moves along it.
+func drawStill(c canvas, t transform, s Scene, opts Options, f frame) {
+	sol := *s.Solution
+	sol.Tree = sol.Tree[:f.tree]
+	if !f.moving {
+		sol.Path = nil
+	}
+	s.Solution, s.Robot = &sol, nil
+	draw(c, t, s, opts)
+}
+
+// drawRobot draws the footprint of robot at pose, or a dot for a point robot.
+func drawRobot(c canvas, t transform, robot planner.Robot, pose planner.Point) {
+	if robot == nil {
+		c.circle(t.pt(pose.X, pose.Y), 4, footprintColor)
+		return
+	}
+	for _, offset := range robot {
+		q := planner.RobotPointGlobal(pose, offset)
+		c.circle(t.pt(q.X, q.Y), 1.8, footprintColor)
+	}
+}
+
+// SVGFrames draws an animation as a sequence of SVG images, each written to
+// the writer returned by create for its frame index. The writers are closed
+// after each frame.
+func SVGFrames(s Scene, opts AnimationOptions, create func(frame int) (io.WriteCloser, error)) error {
+	t := newTransform(s.Space, opts.Width)
+	for i, f := range frames(s, opts) {
+		w, err := create(i)
+		if err != nil {
+			return err
+		}
+		c := newSVGCanvas(t.size())
+		drawStill(c, t, s, opts.Options, f)
+		if f.moving {
+			drawRobot(c, t, s.Robot, f.pose)
+		}
+		if err := c.writeTo(w); err != nil {
+			w.Close()
+			return err
+		}
+		if err := w.Close(); err != nil {
+			return errors.Wrapf(err, "could not close frame %d", i)
+		}
+	}
+	return nil
+}
+
+// GIF draws an animation as an animated GIF that loops forever.
+func GIF(w io.Writer, s Scene, opts AnimationOptions) error {
+	fs := frames(s, opts)
+	if len(fs) == 0 {
+		return errors.New("nothing to animate without a solution")
+	}
+	t := newTransform(s.Space, opts.Width)
+	width, height := t.size()
+	p := newPalette()
+	anim := &gif.GIF{}
+	delay := int(opts.Delay / (10 * time.Millisecond))
+
+	// The scene behind the moving robot is the same in every frame, so it is
+	// only drawn once.
+	var still *rasterCanvas
+	var prev *image.Paletted
+	for _, f := range fs {
+		c := newRasterCanvas(width, height)
+		if f.moving {
+			if still == nil {
+				still = newRasterCanvas(width, height)
+				drawStill(still, t, s, opts.Options, f)
+			}
+			copy(c.img.Pix, still.img.Pix)
+			drawRobot(c, t, s.Robot, f.pose)
+		} else {
+			drawStill(c, t, s, opts.Options, f)
+		}
+		img := p.convert(c.img)
+		anim.Image = append(anim.Image, changed(prev, img))
+		anim.Delay = append(anim.Delay, delay)
+		prev = img
+	}
+	return errors.Wrap(gif.EncodeAll(w, anim), "could not write gif")
+}
+
+// changed returns the part of img that differs from the previous frame, which
+// is drawn over the previous frame when the GIF is played.
+func changed(prev, img *image.Paletted) *image.Paletted {
+	if prev == nil {
+		return img
+	}
+	r := image.Rectangle{}
+	for y := img.Rect.Min.Y; y < img.Rect.Max.Y; y++ {
+		row := img.PixOffset(img.Rect.Min.X, y)
+		for x := 0; x < img.Rect.Dx(); x++ {
+			if img.Pix[row+x] != prev.Pix[row+x] {
+				r = r.Union(image.Rect(x, y, x+1, y+1))
+			}
+		}
+	}
+	if r.Empty() {
+		r = image.Rect(0, 0, 1, 1)
+	}
+	return img.SubImage(r).(*image.Paletted)
+}
+
+// palette maps the colors of a figure to the 256 colors of a GIF frame. It
+// holds every color of a figure blended over the background and over
+// obstacles, which covers the anti-aliased edges.
+type palette struct {
+	colors color.Palette
+	index  map[color.RGBA]uint8
+}
+
+func newPalette() *palette {
+	bg := color.RGBA{backgroundColor.R, backgroundColor.G, backgroundColor.B, 255}
+	obstacle := opaque(obstacleColor, bg)
+	p := &palette{colors: color.Palette{bg, obstacle}, index: map[color.RGBA]uint8{}}
+	for _, c := range []color.NRGBA{boundsColor, obstacleColor, goalColor, startColor, treeColor, pathColor, footprintColor} {
+		for _, under := range []color.RGBA{bg, obstacle} {
+			fg := opaque(c, under)
+			for i := 1; i <= 16; i++ {
+				p.colors = append(p.colors, mix(under, fg, float64(i)/16))
+			}
+		}
+	}
+	return p
+}
+
+// convert returns img with every pixel replaced by the closest palette color.
+func (p *palette) convert(img *image.RGBA) *image.Paletted {
+	out := image.NewPaletted(img.Rect, p.colors)
+	for i, j := 0, 0; i < len(img.Pix); i, j = i+4, j+1 {
+		c := color.RGBA{img.Pix[i], img.Pix[i+1], img.Pix[i+2], 255}
+		idx, ok := p.index[c]
+		if !ok {
+			idx = uint8(p.colors.Index(c))
+			p.index[c] = idx
+		}
+		out.Pix[j] = idx
+	}
+	return out
+}
+
+// opaque returns c drawn over the opaque color under.
+func opaque(c color.NRGBA, under color.RGBA) color.RGBA {
+	return mix(under, color.RGBA{c.R, c.G, c.B, 255}, float64(c.A)/255)
+}
+
+// mix mixes a fraction t of b into a.
+func mix(a, b color.RGBA, t float64) color.RGBA {
+	channel := func(x, y uint8) uint8 { return uint8(float64(x)*(1-t) + float64(y)*t + 0.5) }
+	return color.RGBA{channel(a.R, b.R), channel(a.G, b.G), channel(a.B, b.B), 255}
+}
@@ -0,0 +1,201 @@
+package render
+
+import (
+	"image"
+	"image/color"
+	"image/gif"
+	"io"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/hdhauk/enae788v/planner"
+)
+
+// ArmScene is everything drawn in a figure of a planar arm. Solution is nil
+// to only draw the problem. The tree and path of a solution are in joint
+// space, so they are drawn by the positions of the end effector.
+type ArmScene struct {
+	Space     planner.ConfigSpace
+	Obstacles []planner.Circle
+	Arm       planner.Arm
+	Problem   planner.ArmProblem
+	Solution  *planner.Solution
+}
+
+// Colors of the arm, which is drawn faded along the path.
+var (
+	armColor      = footprintColor
+	armTraceColor = color.NRGBA{240, 150, 20, 70}
+)
+
+// armTraceSteps is the number of segments the path of the end effector is
+// drawn with along every motion, which is curved in the workspace.
+const armTraceSteps = 8
+
+// drawArmScene draws an arm scene on c. If sweep is true, the arm is drawn
+// faded at the end of every motion of the path, or at every
+// opts.FootprintStep-th state, and solid at the start and the end of the
+// path. Otherwise the arm is only drawn without a path, at the start.
+func drawArmScene(c canvas, t transform, s ArmScene, opts Options, sweep bool) {
+	cs := s.Space
+	c.polyline([]pt{t.pt(cs.XMin, cs.YMin), t.pt(cs.XMax, cs.YMin), t.pt(cs.XMax, cs.YMax), t.pt(cs.XMin, cs.YMax), t.pt(cs.XMin, cs.YMin)}, boundsColor, 1.5)
+	for _, o := range s.Obstacles {
+		c.circle(t.pt(o.X, o.Y), o.R*t.scale, obstacleColor)
+	}
+	g := s.Problem.Goal
+	c.circle(t.pt(g.X, g.Y), g.R*t.scale, goalColor)
+
+	sol := s.Solution
+	if sol == nil || len(sol.Path) == 0 {
+		if len(s.Problem.Start) == len(s.Arm.Links) {
+			drawArm(c, t, s.Arm, s.Problem.Start, armColor)
+		}
+		return
+	}
+	if !opts.HideTree {
+		for _, m := range sol.Tree {
+			from, to := s.Arm.EndEffector(m.From.(planner.Joints)), s.Arm.EndEffector(m.To.(planner.Joints))
+			c.polyline([]pt{t.pt(from.X, from.Y), t.pt(to.X, to.Y)}, treeColor, 0.8)
+		}
+	}
+	if sweep {
+		for _, q := range armPoses(sol.Path, opts.FootprintStep) {
+			drawArm(c, t, s.Arm, q, armTraceColor)
+		}
+	}
+	var trace []pt
+	for _, q := range jointSweep(planner.PathStates(sol.Path), armTraceSteps*len(sol.Path)+1) {
+		end := s.Arm.EndEffector(q)
+		trace = append(trace, t.pt(end.X, end.Y))
+	}
+	c.polyline(trace, pathColor, 2.5)
+	if sweep {
+		drawArm(c, t, s.Arm, sol.Path[len(sol.Path)-1].To.(planner.Joints), armColor)
+		drawArm(c, t, s.Arm, sol.Path[0].From.(planner.Joints), armColor)
+	}
+}
+
+// drawArm draws the links of the arm at q, with a dot at the base and every
+// joint.
+func drawArm(c canvas, t transform, arm planner.Arm, q planner.Joints, col color.NRGBA) {
+	var pts []pt
+	for _, p := range arm.Points(q) {
+		pts = append(pts, t.pt(p.X, p.Y))
+	}
+	c.polyline(pts, col, 4)
+	for _, p := range pts[:len(pts)-1] {
+		c.circle(p, 3, col)
+	}
+	c.circle(pts[0], 5, startColor)
+}
+
+// armPoses returns the configurations along a path where the arm is drawn:
+// every step-th state, or the end of every motion if step is 0.
+func armPoses(path []*planner.Motion, step int) []planner.Joints {
+	var poses []planner.Joints
+	if step <= 0 {
+		for _, m := range path {
+			poses = append(poses, m.To.(planner.Joints))
+		}
+		return poses
+	}
+	states := planner.PathStates(path)
+	for i := 0; i < len(states); i += step {
+		poses = append(poses, states[i].(planner.Joints))
+	}
+	return poses
+}
+
+// jointSweep returns n configurations evenly spaced in joint space along
+// states.
+func jointSweep(states []planner.State, n int) []planner.Joints {
+	if len(states) == 0 || n <= 0 {
+		return nil
+	}
+	params := make([]float64, len(states))
+	for i := 1; i < len(states); i++ {
+		params[i] = params[i-1] + planner.JointSpace{}.Distance(states[i-1], states[i])
+	}
+
+	total := params[len(params)-1]
+	poses := make([]planner.Joints, 0, n)
+	j := 0
+	for k := 0; k < n; k++ {
+		at := total
+		if n > 1 {
+			at = total * float64(k) / float64(n-1)
+		}
+		for j < len(states)-2 && params[j+1] < at {
+			j++
+		}
+		if len(states) == 1 {
+			poses = append(poses, states[0].(planner.Joints))
+			continue
+		}
+		a, b := states[j].(planner.Joints), states[j+1].(planner.Joints)
+		t := 1.0
+		if span := params[j+1] - params[j]; span > 0 {
+			t = (at - params[j]) / span
+			if t < 0 {
+				t = 0
+			} else if t > 1 {
+				t = 1
+			}
+		}
+		q := make(planner.Joints, len(a))
+		for i := range q {
+			q[i] = a[i] + t*(b[i]-a[i])
+		}
+		poses = append(poses, q)
+	}
+	return poses
+}
+
+// ArmSVG draws an arm scene as an SVG image, with the arm sweeping along the
+// path.
+func ArmSVG(w io.Writer, s ArmScene, opts Options) error {
+	t := newTransform(s.Space, opts.Width)
+	c := newSVGCanvas(t.size())
+	drawArmScene(c, t, s, opts, true)
+	return c.writeTo(w)
+}
+
+// ArmPNG draws an arm scene as a PNG image, with the arm sweeping along the
+// path.
+func ArmPNG(w io.Writer, s ArmScene, opts Options) error {
+	t := newTransform(s.Space, opts.Width)
+	c := newRasterCanvas(t.size())
+	drawArmScene(c, t, s, opts, true)
+	return c.writeTo(w)
+}
+
+// ArmGIF draws an animation of the arm moving along the path at a uniform
+// speed in joint space, as an animated GIF that loops forever. Tree frames
+// are not drawn.
+func ArmGIF(w io.Writer, s ArmScene, opts AnimationOptions) error {
+	if s.Solution == nil || len(s.Solution.Path) == 0 {
+		return errors.New("nothing to animate without a solution")
+	}
+	t := newTransform(s.Space, opts.Width)
+	width, height := t.size()
+	p := newPalette()
+	anim := &gif.GIF{}
+	delay := int(opts.Delay / (10 * time.Millisecond))
+
+	// The scene behind the moving arm is the same in every frame, so it is
+	// only drawn once.
+	still := newRasterCanvas(width, height)
+	drawArmScene(still, t, s, opts.Options, false)
+	var prev *image.Paletted
+	for _, q := range jointSweep(planner.PathStates(s.Solution.Path), opts.Frames) {
+		c := newRasterCanvas(width, height)
+		copy(c.img.Pix, still.img.Pix)
+		drawArm(c, t, s.Arm, q, armColor)
+		img := p.convert(c.img)
+		anim.Image = append(anim.Image, changed(prev, img))
+		anim.Delay = append(anim.Delay, delay)
+		prev = img
+	}
+	return errors.Wrap(gif.EncodeAll(w, anim), "could not write gif")
+}
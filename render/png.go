@@ -0,0 +1,116 @@
+package render
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math"
+
+	"github.com/pkg/errors"
+)
+
+// rasterCanvas draws anti-aliased shapes on an opaque image.
+type rasterCanvas struct {
+	img *image.RGBA
+}
+
+func newRasterCanvas(width, height int) *rasterCanvas {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	bg := color.RGBA{backgroundColor.R, backgroundColor.G, backgroundColor.B, 255}
+	for i := 0; i < len(img.Pix); i += 4 {
+		img.Pix[i], img.Pix[i+1], img.Pix[i+2], img.Pix[i+3] = bg.R, bg.G, bg.B, bg.A
+	}
+	return &rasterCanvas{img: img}
+}
+
+// blend mixes c into the pixel at (x, y), where coverage is the fraction of
+// the pixel covered by the shape.
+func (c *rasterCanvas) blend(x, y int, col color.NRGBA, coverage float64) {
+	if !(image.Point{x, y}.In(c.img.Rect)) || coverage <= 0 {
+		return
+	}
+	a := math.Min(coverage, 1) * float64(col.A) / 255
+	i := c.img.PixOffset(x, y)
+	pix := c.img.Pix[i : i+3]
+	for j, v := range [3]uint8{col.R, col.G, col.B} {
+		pix[j] = uint8(float64(pix[j])*(1-a) + float64(v)*a + 0.5)
+	}
+}
+
+// bounds returns the pixels within pad of the box spanned by a and b,
+// clipped to the image.
+func (c *rasterCanvas) bounds(a, b pt, pad float64) image.Rectangle {
+	r := image.Rect(
+		int(math.Floor(math.Min(a.X, b.X)-pad)), int(math.Floor(math.Min(a.Y, b.Y)-pad)),
+		int(math.Ceil(math.Max(a.X, b.X)+pad))+1, int(math.Ceil(math.Max(a.Y, b.Y)+pad))+1,
+	)
+	return r.Intersect(c.img.Rect)
+}
+
+func (c *rasterCanvas) polyline(pts []pt, col color.NRGBA, width float64) {
+	half := width / 2
+	for i := 1; i < len(pts); i++ {
+		a, b := pts[i-1], pts[i]
+		r := c.bounds(a, b, half+1)
+		for y := r.Min.Y; y < r.Max.Y; y++ {
+			for x := r.Min.X; x < r.Max.X; x++ {
+				d := segmentDistance(pt{float64(x) + 0.5, float64(y) + 0.5}, a, b)
+				c.blend(x, y, col, half+0.5-d)
+			}
+		}
+	}
+}
+
+func (c *rasterCanvas) circle(center pt, r float64, fill color.NRGBA) {
+	box := c.bounds(center, center, r+1)
+	for y := box.Min.Y; y < box.Max.Y; y++ {
+		for x := box.Min.X; x < box.Max.X; x++ {
+			d := math.Hypot(float64(x)+0.5-center.X, float64(y)+0.5-center.Y)
+			c.blend(x, y, fill, r+0.5-d)
+		}
+	}
+}
+
+func (c *rasterCanvas) polygon(pts []pt, fill color.NRGBA) {
+	if len(pts) < 3 {
+		return
+	}
+	lo, hi := pts[0], pts[0]
+	for _, p := range pts {
+		lo.X, lo.Y = math.Min(lo.X, p.X), math.Min(lo.Y, p.Y)
+		hi.X, hi.Y = math.Max(hi.X, p.X), math.Max(hi.Y, p.Y)
+	}
+	box := c.bounds(lo, hi, 1)
+	for y := box.Min.Y; y < box.Max.Y; y++ {
+		for x := box.Min.X; x < box.Max.X; x++ {
+			p := pt{float64(x) + 0.5, float64(y) + 0.5}
+			d, inside := math.Inf(1), false
+			for i := range pts {
+				a, b := pts[i], pts[(i+1)%len(pts)]
+				d = math.Min(d, segmentDistance(p, a, b))
+				if (a.Y > p.Y) != (b.Y > p.Y) && p.X < a.X+(p.Y-a.Y)*(b.X-a.X)/(b.Y-a.Y) {
+					inside = !inside
+				}
+			}
+			if inside {
+				d = -d
+			}
+			c.blend(x, y, fill, 0.5-d)
+		}
+	}
+}
+
+func (c *rasterCanvas) writeTo(w io.Writer) error {
+	return errors.Wrap(png.Encode(w, c.img), "could not write png")
+}
+
+// segmentDistance returns the distance from p to the line segment from a to b.
+func segmentDistance(p, a, b pt) float64 {
+	dx, dy := b.X-a.X, b.Y-a.Y
+	t := 0.0
+	if l := dx*dx + dy*dy; l > 0 {
+		t = math.Max(0, math.Min(1, ((p.X-a.X)*dx+(p.Y-a.Y)*dy)/l))
+	}
+	return math.Hypot(p.X-a.X-t*dx, p.Y-a.Y-t*dy)
+}
@@ -0,0 +1,216 @@
+// Package render draws planning problems and their solutions to SVG and PNG
+// without any dependencies outside the standard library. A figure shows the
+// config space bounds, the obstacles, the start and goal region, the tree,
+// the path, and the robot footprint at the poses along the path. Motions of
+// kinodynamic planners are drawn through their intermediate states, so
+// trajectories show up as curves.
+package render
+
+import (
+	"image/color"
+	"io"
+	"math"
+
+	"github.com/hdhauk/enae788v/planner"
+)
+
+// Scene is everything drawn in a figure. Robot is nil for a point robot,
+// Solution is nil to only draw the problem, and Slice is nil to not draw
+// C-obstacles.
+type Scene struct {
+	Space     planner.ConfigSpace
+	Obstacles []planner.Circle
+	Polygons  []planner.Polygon
+	Problem   planner.Problem
+	Robot     planner.Robot
+	Solution  *planner.Solution
+	Slice     *planner.CSlice
+}
+
+// Options configures the size of a figure and what is drawn.
+type Options struct {
+	Width         int  // width of the figure in pixels, the height follows the config space
+	HideTree      bool // only draw the path of the solution
+	FootprintStep int  // draw the footprint at every n-th state along the path, 0 at the end of every motion
+}
+
+// DefaultOptions draws everything in a figure 800 pixels wide.
+var DefaultOptions = Options{Width: 800}
+
+// Colors of the elements of a figure.
+var (
+	backgroundColor = color.NRGBA{255, 255, 255, 255}
+	boundsColor     = color.NRGBA{0, 0, 0, 255}
+	obstacleColor   = color.NRGBA{70, 110, 180, 255}
+	goalColor       = color.NRGBA{40, 170, 60, 110}
+	startColor      = color.NRGBA{40, 170, 60, 255}
+	treeColor       = color.NRGBA{90, 90, 90, 160}
+	pathColor       = color.NRGBA{220, 40, 40, 255}
+	footprintColor  = color.NRGBA{240, 150, 20, 255}
+	cObstacleColor  = color.NRGBA{200, 215, 240, 255}
+)
+
+// pt is a point in pixel coordinates, with y pointing down.
+type pt struct {
+	X, Y float64
+}
+
+// canvas is a drawing surface in pixel coordinates.
+type canvas interface {
+	polyline(pts []pt, c color.NRGBA, width float64)
+	circle(center pt, r float64, fill color.NRGBA)
+	polygon(pts []pt, fill color.NRGBA)
+}
+
+// transform maps the config space to pixels, with a margin around it.
+type transform struct {
+	cSpace        planner.ConfigSpace
+	scale, margin float64
+}
+
+func newTransform(cSpace planner.ConfigSpace, width int) transform {
+	const margin = 10
+	return transform{cSpace: cSpace, scale: (float64(width) - 2*margin) / (cSpace.XMax - cSpace.XMin), margin: margin}
+}
+
+func (t transform) size() (int, int) {
+	w := (t.cSpace.XMax-t.cSpace.XMin)*t.scale + 2*t.margin
+	h := (t.cSpace.YMax-t.cSpace.YMin)*t.scale + 2*t.margin
+	return int(w + 0.5), int(h + 0.5)
+}
+
+func (t transform) pt(x, y float64) pt {
+	return pt{(x-t.cSpace.XMin)*t.scale + t.margin, (t.cSpace.YMax-y)*t.scale + t.margin}
+}
+
+// draw draws a scene on c.
+func draw(c canvas, t transform, s Scene, opts Options) {
+	cs := s.Space
+	if s.Slice != nil {
+		drawSlice(c, t, cs, s.Slice)
+	}
+	c.polyline([]pt{t.pt(cs.XMin, cs.YMin), t.pt(cs.XMax, cs.YMin), t.pt(cs.XMax, cs.YMax), t.pt(cs.XMin, cs.YMax), t.pt(cs.XMin, cs.YMin)}, boundsColor, 1.5)
+	for _, o := range s.Obstacles {
+		c.circle(t.pt(o.X, o.Y), o.R*t.scale, obstacleColor)
+	}
+	for _, o := range s.Polygons {
+		var pts []pt
+		for _, v := range o {
+			pts = append(pts, t.pt(v.X, v.Y))
+		}
+		c.polygon(pts, obstacleColor)
+	}
+	g := s.Problem.Goal
+	c.circle(t.pt(g.X, g.Y), g.R*t.scale, goalColor)
+
+	if sol := s.Solution; sol != nil {
+		if !opts.HideTree {
+			for _, m := range sol.Tree {
+				c.polyline(motionPts(t, m), treeColor, 0.8)
+			}
+		}
+		var path []pt
+		for _, st := range planner.PathStates(sol.Path) {
+			p := planner.Pose(st)
+			path = append(path, t.pt(p.X, p.Y))
+		}
+		c.polyline(path, pathColor, 2.5)
+		if s.Robot != nil {
+			for _, p := range footprintPoses(sol.Path, opts.FootprintStep) {
+				for _, offset := range s.Robot {
+					q := planner.RobotPointGlobal(p, offset)
+					c.circle(t.pt(q.X, q.Y), 1.2, footprintColor)
+				}
+			}
+		}
+	}
+
+	start := s.Problem.Start
+	c.circle(t.pt(start.X, start.Y), 4, startColor)
+}
+
+// drawSlice fills the C-obstacles of a slice, and the margin between the
+// config space and the bounds of the robot center.
+func drawSlice(c canvas, t transform, cs planner.ConfigSpace, s *planner.CSlice) {
+	xMin, xMax := math.Max(cs.XMin, s.XMin), math.Min(cs.XMax, s.XMax)
+	yMin, yMax := math.Max(cs.YMin, s.YMin), math.Min(cs.YMax, s.YMax)
+	for _, r := range [][4]float64{
+		{cs.XMin, cs.YMin, xMin, cs.YMax}, {xMax, cs.YMin, cs.XMax, cs.YMax},
+		{cs.XMin, cs.YMin, cs.XMax, yMin}, {cs.XMin, yMax, cs.XMax, cs.YMax},
+	} {
+		if r[0] < r[2] && r[1] < r[3] {
+			c.polygon([]pt{t.pt(r[0], r[1]), t.pt(r[2], r[1]), t.pt(r[2], r[3]), t.pt(r[0], r[3])}, cObstacleColor)
+		}
+	}
+
+	// A rounded polygon is the polygon with its outline drawn as wide as
+	// the radius on either side.
+	for _, o := range s.Obstacles {
+		var pts []pt
+		for _, v := range o.Vertices {
+			pts = append(pts, t.pt(v.X, v.Y))
+		}
+		switch {
+		case len(pts) == 1:
+			c.circle(pts[0], o.R*t.scale, cObstacleColor)
+			continue
+		case len(pts) > 2:
+			c.polygon(pts, cObstacleColor)
+		}
+		if o.R > 0 {
+			c.polyline(append(pts, pts[0]), cObstacleColor, 2*o.R*t.scale)
+		}
+	}
+}
+
+// motionPts returns the pixel coordinates along a motion.
+func motionPts(t transform, m *planner.Motion) []pt {
+	from := planner.Pose(m.From)
+	pts := []pt{t.pt(from.X, from.Y)}
+	if len(m.Path) == 0 {
+		to := planner.Pose(m.To)
+		return append(pts, t.pt(to.X, to.Y))
+	}
+	for _, s := range m.Path {
+		p := planner.Pose(s)
+		pts = append(pts, t.pt(p.X, p.Y))
+	}
+	return pts
+}
+
+// footprintPoses returns the poses along a path where the footprint is drawn:
+// every step-th state, or the start and the end of every motion if step is 0.
+func footprintPoses(path []*planner.Motion, step int) []planner.Point {
+	if len(path) == 0 {
+		return nil
+	}
+	var poses []planner.Point
+	if step <= 0 {
+		poses = append(poses, planner.Pose(path[0].From))
+		for _, m := range path {
+			poses = append(poses, planner.Pose(m.To))
+		}
+		return poses
+	}
+	states := planner.PathStates(path)
+	for i := 0; i < len(states); i += step {
+		poses = append(poses, planner.Pose(states[i]))
+	}
+	return poses
+}
+
+// SVG draws a scene as an SVG image.
+func SVG(w io.Writer, s Scene, opts Options) error {
+	t := newTransform(s.Space, opts.Width)
+	c := newSVGCanvas(t.size())
+	draw(c, t, s, opts)
+	return c.writeTo(w)
+}
+
+// PNG draws a scene as a PNG image.
+func PNG(w io.Writer, s Scene, opts Options) error {
+	t := newTransform(s.Space, opts.Width)
+	c := newRasterCanvas(t.size())
+	draw(c, t, s, opts)
+	return c.writeTo(w)
+}
@@ -0,0 +1,246 @@
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/png"
+	"io"
+	"math"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hdhauk/enae788v/angle"
+	"github.com/hdhauk/enae788v/planner"
+)
+
+func assert(tb testing.TB, condition bool, msg string, v ...interface{}) {
+	if !condition {
+		_, file, line, _ := runtime.Caller(1)
+		fmt.Printf("\033[31m%s:%d: "+msg+"\033[39m\n\n", append([]interface{}{filepath.Base(file), line}, v...)...)
+		tb.FailNow()
+	}
+}
+
+// ok fails the test if an err is not nil.
+func ok(tb testing.TB, err error) {
+	if err != nil {
+		_, file, line, _ := runtime.Caller(1)
+		fmt.Printf("\033[31m%s:%d: unexpected error: %s\033[39m\n\n", filepath.Base(file), line, err.Error())
+		tb.FailNow()
+	}
+}
+
+// equals fails the test if exp is not equal to act.
+func equals(tb testing.TB, exp, act interface{}) {
+	if !reflect.DeepEqual(exp, act) {
+		_, file, line, _ := runtime.Caller(1)
+		fmt.Printf("\033[31m%s:%d:\n\n\texp: %#v\n\n\tgot: %#v\033[39m\n\n", filepath.Base(file), line, exp, act)
+		tb.FailNow()
+	}
+}
+
+func testScene() Scene {
+	a, b, c := planner.Point{X: 10, Y: 10}, planner.Point{X: 50, Y: 10}, planner.Point{X: 90, Y: 90}
+	first := &planner.Motion{From: a, To: b, Cost: 40}
+	second := &planner.Motion{From: b, To: c, Cost: 89}
+	return Scene{
+		Space:     planner.ConfigSpace{XMin: 0, XMax: 100, YMin: 0, YMax: 50},
+		Obstacles: []planner.Circle{{X: 30, Y: 30, R: 10}, {X: 70, Y: 20, R: 5}},
+		Problem:   planner.Problem{Start: a, Goal: planner.Circle{X: 90, Y: 40, R: 8}},
+		Robot:     planner.Robot{{X: -1}, {X: 1}},
+		Solution:  &planner.Solution{Path: []*planner.Motion{first, second}, Tree: []*planner.Motion{first, second}, Cost: 129},
+	}
+}
+
+func TestSVG(t *testing.T) {
+	var buf bytes.Buffer
+	ok(t, SVG(&buf, testScene(), Options{Width: 420}))
+	svg := buf.String()
+	assert(t, strings.HasPrefix(svg, `<svg xmlns="http://www.w3.org/2000/svg" width="420" height="220"`), "unexpected header: %.80s", svg)
+
+	// Bounds, two tree edges and the path.
+	equals(t, 4, strings.Count(svg, "<polyline"))
+	// Obstacles, goal, start and two footprint points at three poses.
+	equals(t, 2+1+1+2*3, strings.Count(svg, "<circle"))
+
+	buf.Reset()
+	ok(t, SVG(&buf, testScene(), Options{Width: 420, HideTree: true}))
+	equals(t, 2, strings.Count(buf.String(), "<polyline"))
+}
+
+func TestPNG(t *testing.T) {
+	var buf bytes.Buffer
+	ok(t, PNG(&buf, testScene(), Options{Width: 420}))
+	img, err := png.Decode(&buf)
+	ok(t, err)
+	equals(t, 420, img.Bounds().Dx())
+	equals(t, 220, img.Bounds().Dy())
+
+	rgba := func(x, y int) color.RGBA {
+		r, g, b, a := img.At(x, y).RGBA()
+		return color.RGBA{uint8(r >> 8), uint8(g >> 8), uint8(b >> 8), uint8(a >> 8)}
+	}
+	// The config space is scaled by 4 with a margin of 10 pixels.
+	equals(t, color.RGBA{255, 255, 255, 255}, rgba(3, 3))
+	o := obstacleColor
+	equals(t, color.RGBA{o.R, o.G, o.B, 255}, rgba(10+30*4, 10+20*4))
+	p := pathColor
+	equals(t, color.RGBA{p.R, p.G, p.B, 255}, rgba(10+30*4, 10+40*4))
+}
+
+func TestSlice(t *testing.T) {
+	scene := testScene()
+	cObstacles := planner.NewCObstacles(scene.Space, scene.Obstacles, nil, planner.HullFootprint(scene.Robot), 8)
+	scene.Slice = cObstacles.Slice(0)
+
+	var buf bytes.Buffer
+	ok(t, SVG(&buf, scene, Options{Width: 420}))
+	// The margins on every side, and a capsule around every obstacle.
+	equals(t, 4, strings.Count(buf.String(), "<polygon"))
+	equals(t, 4+2, strings.Count(buf.String(), "<polyline"))
+
+	buf.Reset()
+	ok(t, PNG(&buf, scene, Options{Width: 420, HideTree: true}))
+	img, err := png.Decode(&buf)
+	ok(t, err)
+	r, g, b, _ := img.At(10+40.5*4, 10+20*4).RGBA()
+	c := cObstacleColor
+	equals(t, [3]uint8{c.R, c.G, c.B}, [3]uint8{uint8(r >> 8), uint8(g >> 8), uint8(b >> 8)})
+}
+
+func TestSweep(t *testing.T) {
+	// Along straight lines the robot moves at a uniform speed and turns
+	// along the shortest arc.
+	states := []planner.State{planner.Point{X: 0, Theta: 0.2}, planner.Point{X: 1, Theta: 0.2}, planner.Point{X: 4, Theta: -0.2}}
+	poses := sweep(states, 5)
+	equals(t, 5, len(poses))
+	for i, x := range []float64{0, 1, 2, 3, 4} {
+		assert(t, math.Abs(poses[i].X-x) < 1e-9, "pose %d: expected x %v, got %v", i, x, poses[i].X)
+	}
+	assert(t, math.Abs(poses[2].Theta-angle.Normalize(0.2-0.4/3)) < 1e-9, "unexpected heading %v", poses[2].Theta)
+
+	// Along trajectories the robot moves at the timestamps of the states.
+	states = []planner.State{
+		&planner.PathPoint{Point: planner.Point{X: 0}, T: 0},
+		&planner.PathPoint{Point: planner.Point{X: 3}, T: 1},
+		&planner.PathPoint{Point: planner.Point{X: 4}, T: 2},
+	}
+	poses = sweep(states, 5)
+	for i, x := range []float64{0, 1.5, 3, 3.5, 4} {
+		assert(t, math.Abs(poses[i].X-x) < 1e-9, "pose %d: expected x %v, got %v", i, x, poses[i].X)
+	}
+}
+
+func TestGIF(t *testing.T) {
+	opts := AnimationOptions{Options: Options{Width: 420}, Frames: 6, TreeFrames: 2, Delay: 50 * time.Millisecond}
+	var buf bytes.Buffer
+	ok(t, GIF(&buf, testScene(), opts))
+	anim, err := gif.DecodeAll(&buf)
+	ok(t, err)
+	equals(t, 8, len(anim.Image))
+	equals(t, []int{5, 5, 5, 5, 5, 5, 5, 5}, anim.Delay)
+	equals(t, image.Rect(0, 0, 420, 220), anim.Image[0].Bounds())
+
+	// The first frame only has the first tree edge.
+	fs := frames(testScene(), opts)
+	equals(t, frame{tree: 1}, fs[0])
+	equals(t, 2, fs[1].tree)
+	assert(t, fs[2].moving, "expected the robot to move after the tree")
+	equals(t, planner.Point{X: 10, Y: 10}, fs[2].pose)
+
+	var svgs []*bytes.Buffer
+	ok(t, SVGFrames(testScene(), opts, func(int) (io.WriteCloser, error) {
+		svgs = append(svgs, &bytes.Buffer{})
+		return nopCloser{svgs[len(svgs)-1]}, nil
+	}))
+	equals(t, 8, len(svgs))
+	// Obstacles, goal, start and the two footprint points of the robot.
+	equals(t, 2+1+1+2, strings.Count(svgs[2].String(), "<circle"))
+
+	assert(t, GIF(&buf, Scene{Space: testScene().Space}, opts) != nil, "expected an error without a solution")
+}
+
+func TestArm(t *testing.T) {
+	arm := planner.Arm{Base: planner.Point{X: 50, Y: 5}, Links: []planner.Link{{Length: 20, Min: 0, Max: math.Pi}, {Length: 15, Min: -3, Max: 3}}}
+	a, b, c := planner.Joints{0.2, 0.5}, planner.Joints{1, 0.5}, planner.Joints{2, 1}
+	first, second := &planner.Motion{From: a, To: b}, &planner.Motion{From: b, To: c}
+	scene := ArmScene{
+		Space:     planner.ConfigSpace{XMin: 0, XMax: 100, YMin: 0, YMax: 50},
+		Obstacles: []planner.Circle{{X: 30, Y: 30, R: 5}, {X: 70, Y: 30, R: 5}},
+		Arm:       arm,
+		Problem:   planner.ArmProblem{Start: a, Goal: planner.Circle{X: 20, Y: 30, R: 5}},
+		Solution:  &planner.Solution{Path: []*planner.Motion{first, second}, Tree: []*planner.Motion{first, second}},
+	}
+
+	var buf bytes.Buffer
+	ok(t, ArmSVG(&buf, scene, Options{Width: 420}))
+	// Bounds, two tree edges, the faded arm at the end of both motions, the
+	// path of the end effector and the arm at the start and the end.
+	equals(t, 1+2+2+1+2, strings.Count(buf.String(), "<polyline"))
+	// Obstacles, goal, and the base, the elbow and the base again of four
+	// arms.
+	equals(t, 2+1+3*4, strings.Count(buf.String(), "<circle"))
+
+	// The arm moves at a uniform speed in joint space.
+	qs := jointSweep([]planner.State{planner.Joints{0, 0}, planner.Joints{1, 0}, planner.Joints{1, 3}}, 5)
+	equals(t, 5, len(qs))
+	equals(t, planner.Joints{1, 0}, qs[1])
+	equals(t, planner.Joints{1, 3}, qs[4])
+
+	buf.Reset()
+	ok(t, ArmGIF(&buf, scene, AnimationOptions{Options: Options{Width: 420}, Frames: 6, Delay: 50 * time.Millisecond}))
+	anim, err := gif.DecodeAll(&buf)
+	ok(t, err)
+	equals(t, 6, len(anim.Image))
+	assert(t, ArmGIF(&buf, ArmScene{Space: scene.Space}, AnimationOptions{Frames: 6}) != nil, "expected an error without a solution")
+}
+
+func TestView3D(t *testing.T) {
+	a, b, c := planner.Point3D{X: 5, Y: 5, Z: 5}, planner.Point3D{X: 20, Y: 5, Z: 10}, planner.Point3D{X: 35, Y: 15, Z: 15}
+	first, second := &planner.Motion{From: a, To: b}, &planner.Motion{From: b, To: c}
+	scene := Scene3D{
+		Space:    planner.ConfigSpace3D{XMin: 0, XMax: 40, YMin: 0, YMax: 20, ZMin: 0, ZMax: 20},
+		Spheres:  []planner.Sphere{{X: 20, Y: 15, Z: 5, R: 3}},
+		Boxes:    []planner.Box{{Center: planner.Point3D{X: 10, Y: 15, Z: 15}, Size: planner.Point3D{X: 4, Y: 4, Z: 4}, Yaw: 0.5}},
+		Problem:  planner.Problem3D{Start: a, Goal: planner.Sphere{X: 35, Y: 15, Z: 15, R: 2}},
+		Robot:    planner.Robot3D{{}, {Z: 1}},
+		Solution: &planner.Solution{Path: []*planner.Motion{first, second}, Tree: []*planner.Motion{first, second}},
+	}
+
+	for _, view := range []View{ViewTop, ViewFront, ViewSide, ViewIso} {
+		t.Run(string(view), func(t *testing.T) {
+			var buf bytes.Buffer
+			ok(t, SVG3D(&buf, scene, view, Options{Width: 400}))
+			// The edges of the box and the bounds, two tree edges and the
+			// path.
+			equals(t, 12+12+2+1, strings.Count(buf.String(), "<polyline"))
+			equals(t, 6, strings.Count(buf.String(), "<polygon"))
+			// The sphere, the goal, two footprint points at each of three
+			// states and the start.
+			equals(t, 1+1+2*3+1, strings.Count(buf.String(), "<circle"))
+		})
+	}
+
+	// Seen from the front, the top of the space is at the top of the image.
+	p, tr, err := newTransform3D(scene.Space, ViewFront, 400)
+	ok(t, err)
+	low, high := tr.pt(p.to2D(planner.Point3D{Z: 0})), tr.pt(p.to2D(planner.Point3D{Z: 20}))
+	assert(t, high.Y < low.Y, "z is not up in the front view")
+
+	var buf bytes.Buffer
+	ok(t, PNG3D(&buf, scene, ViewIso, Options{Width: 400}))
+	_, err = png.Decode(&buf)
+	ok(t, err)
+	assert(t, SVG3D(&buf, scene, View("below"), Options{Width: 400}) != nil, "expected an error for an unknown view")
+}
+
+type nopCloser struct{ io.Writer }
+
+func (nopCloser) Close() error { return nil }
@@ -0,0 +1,70 @@
+package render
+
+import (
+	"bufio"
+	"fmt"
+	"image/color"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// svgCanvas collects SVG elements.
+type svgCanvas struct {
+	width, height int
+	elements      []string
+}
+
+func newSVGCanvas(width, height int) *svgCanvas {
+	return &svgCanvas{width: width, height: height}
+}
+
+func (c *svgCanvas) polyline(pts []pt, col color.NRGBA, width float64) {
+	if len(pts) < 2 {
+		return
+	}
+	c.elements = append(c.elements, fmt.Sprintf(`<polyline points="%s" fill="none" stroke="%s" stroke-opacity="%.2f" stroke-width="%.2f" stroke-linejoin="round"/>`,
+		svgPoints(pts), rgb(col), opacity(col), width))
+}
+
+func (c *svgCanvas) circle(center pt, r float64, fill color.NRGBA) {
+	c.elements = append(c.elements, fmt.Sprintf(`<circle cx="%.2f" cy="%.2f" r="%.2f" fill="%s" fill-opacity="%.2f"/>`,
+		center.X, center.Y, r, rgb(fill), opacity(fill)))
+}
+
+func (c *svgCanvas) polygon(pts []pt, fill color.NRGBA) {
+	c.elements = append(c.elements, fmt.Sprintf(`<polygon points="%s" fill="%s" fill-opacity="%.2f"/>`,
+		svgPoints(pts), rgb(fill), opacity(fill)))
+}
+
+func (c *svgCanvas) writeTo(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n", c.width, c.height, c.width, c.height)
+	fmt.Fprintf(bw, `<rect width="100%%" height="100%%" fill="%s"/>`+"\n", rgb(backgroundColor))
+	for _, e := range c.elements {
+		fmt.Fprintln(bw, e)
+	}
+	fmt.Fprintln(bw, "</svg>")
+	return errors.Wrap(bw.Flush(), "could not write svg")
+}
+
+// svgPoints formats the points of a polyline or polygon.
+func svgPoints(pts []pt) string {
+	var points strings.Builder
+	for i, p := range pts {
+		if i > 0 {
+			points.WriteByte(' ')
+		}
+		fmt.Fprintf(&points, "%.2f,%.2f", p.X, p.Y)
+	}
+	return points.String()
+}
+
+func rgb(c color.NRGBA) string {
+	return fmt.Sprintf("rgb(%d,%d,%d)", c.R, c.G, c.B)
+}
+
+func opacity(c color.NRGBA) float64 {
+	return float64(c.A) / 255
+}
@@ -0,0 +1,208 @@
+package render
+
+import (
+	"image/color"
+	"io"
+	"math"
+	"sort"
+
+	"github.com/pkg/errors"
+
+	"github.com/hdhauk/enae788v/planner"
+)
+
+// Scene3D is everything drawn in a figure of a problem in 3D space. Robot is
+// nil for a point robot, and Solution is nil to only draw the problem.
+type Scene3D struct {
+	Space    planner.ConfigSpace3D
+	Spheres  []planner.Sphere
+	Boxes    []planner.Box
+	Problem  planner.Problem3D
+	Robot    planner.Robot3D
+	Solution *planner.Solution
+}
+
+// View is the direction a 3D scene is seen from. Scenes are drawn with an
+// orthographic projection, so sizes do not shrink with depth.
+type View string
+
+// Views of a 3D scene.
+const (
+	ViewTop   View = "top"   // looking down, with x to the right and y up
+	ViewFront View = "front" // looking along y, with x to the right and z up
+	ViewSide  View = "side"  // looking against x, with y to the right and z up
+	ViewIso   View = "iso"   // isometric, from above the corner of the largest x and smallest y
+)
+
+// boxEdgeColor outlines the faces of boxes, which have the color of the
+// other obstacles.
+var boxEdgeColor = color.NRGBA{35, 60, 110, 255}
+
+// projection maps 3D points to the plane of a view: u to the right, v up and
+// depth toward the viewer.
+type projection struct {
+	u, v, depth planner.Point3D
+}
+
+func newProjection(view View) (projection, error) {
+	switch view {
+	case ViewTop:
+		return projection{u: planner.Point3D{X: 1}, v: planner.Point3D{Y: 1}, depth: planner.Point3D{Z: 1}}, nil
+	case ViewFront:
+		return projection{u: planner.Point3D{X: 1}, v: planner.Point3D{Z: 1}, depth: planner.Point3D{Y: -1}}, nil
+	case ViewSide:
+		return projection{u: planner.Point3D{Y: 1}, v: planner.Point3D{Z: 1}, depth: planner.Point3D{X: 1}}, nil
+	case ViewIso:
+		s2, s3, s6 := math.Sqrt(2), math.Sqrt(3), math.Sqrt(6)
+		return projection{
+			u:     planner.Point3D{X: 1 / s2, Y: 1 / s2},
+			v:     planner.Point3D{X: -1 / s6, Y: 1 / s6, Z: 2 / s6},
+			depth: planner.Point3D{X: 1 / s3, Y: -1 / s3, Z: 1 / s3},
+		}, nil
+	}
+	return projection{}, errors.Errorf("unknown view %q", view)
+}
+
+func dot3D(a, b planner.Point3D) float64 {
+	return a.X*b.X + a.Y*b.Y + a.Z*b.Z
+}
+
+// to2D returns the position of p in the plane of the view.
+func (p projection) to2D(q planner.Point3D) (float64, float64) {
+	return dot3D(q, p.u), dot3D(q, p.v)
+}
+
+// spaceCorners returns the corners of a 3D config space as a box.
+func spaceCorners(cs planner.ConfigSpace3D) [8]planner.Point3D {
+	return planner.Box{
+		Center: planner.Point3D{X: (cs.XMin + cs.XMax) / 2, Y: (cs.YMin + cs.YMax) / 2, Z: (cs.ZMin + cs.ZMax) / 2},
+		Size:   planner.Point3D{X: cs.XMax - cs.XMin, Y: cs.YMax - cs.YMin, Z: cs.ZMax - cs.ZMin},
+	}.Corners()
+}
+
+// boxEdges are the corners of planner.Box.Corners joined by the edges of a
+// box, and boxFaces those around its faces.
+var (
+	boxEdges = [12][2]int{{0, 1}, {1, 2}, {2, 3}, {3, 0}, {4, 5}, {5, 6}, {6, 7}, {7, 4}, {0, 4}, {1, 5}, {2, 6}, {3, 7}}
+	boxFaces = [6][4]int{{0, 1, 2, 3}, {4, 5, 6, 7}, {0, 1, 5, 4}, {1, 2, 6, 5}, {2, 3, 7, 6}, {3, 0, 4, 7}}
+)
+
+// newTransform3D returns the projection of a view, and the transform of the
+// plane of the view to pixels that fits the projected config space.
+func newTransform3D(cs planner.ConfigSpace3D, view View, width int) (projection, transform, error) {
+	p, err := newProjection(view)
+	if err != nil {
+		return p, transform{}, err
+	}
+	plane := planner.ConfigSpace{XMin: math.Inf(1), XMax: math.Inf(-1), YMin: math.Inf(1), YMax: math.Inf(-1)}
+	for _, c := range spaceCorners(cs) {
+		u, v := p.to2D(c)
+		plane.XMin, plane.XMax = math.Min(plane.XMin, u), math.Max(plane.XMax, u)
+		plane.YMin, plane.YMax = math.Min(plane.YMin, v), math.Max(plane.YMax, v)
+	}
+	return p, newTransform(plane, width), nil
+}
+
+// draw3D draws a 3D scene on c. Obstacles are drawn from the furthest to the
+// nearest, so nearer obstacles cover those behind them, while the bounds, the
+// tree and the path are drawn over every obstacle.
+func draw3D(c canvas, p projection, t transform, s Scene3D, opts Options) {
+	pt3 := func(q planner.Point3D) pt {
+		return t.pt(p.to2D(q))
+	}
+
+	type obstacle struct {
+		depth float64
+		draw  func()
+	}
+	var obstacles []obstacle
+	for _, o := range s.Spheres {
+		o := o
+		obstacles = append(obstacles, obstacle{dot3D(o.Center(), p.depth), func() {
+			c.circle(pt3(o.Center()), o.R*t.scale, obstacleColor)
+		}})
+	}
+	for _, o := range s.Boxes {
+		corners := o.Corners()
+		obstacles = append(obstacles, obstacle{dot3D(o.Center, p.depth), func() {
+			for _, f := range boxFaces {
+				c.polygon([]pt{pt3(corners[f[0]]), pt3(corners[f[1]]), pt3(corners[f[2]]), pt3(corners[f[3]])}, obstacleColor)
+			}
+			for _, e := range boxEdges {
+				c.polyline([]pt{pt3(corners[e[0]]), pt3(corners[e[1]])}, boxEdgeColor, 1)
+			}
+		}})
+	}
+	sort.SliceStable(obstacles, func(i, j int) bool { return obstacles[i].depth < obstacles[j].depth })
+	for _, o := range obstacles {
+		o.draw()
+	}
+
+	corners := spaceCorners(s.Space)
+	for _, e := range boxEdges {
+		c.polyline([]pt{pt3(corners[e[0]]), pt3(corners[e[1]])}, boundsColor, 1.5)
+	}
+	g := s.Problem.Goal
+	c.circle(pt3(g.Center()), g.R*t.scale, goalColor)
+
+	if sol := s.Solution; sol != nil {
+		if !opts.HideTree {
+			for _, m := range sol.Tree {
+				var pts []pt
+				for _, st := range append([]planner.State{m.From}, motionStates(m)...) {
+					pts = append(pts, pt3(planner.Pose3D(st)))
+				}
+				c.polyline(pts, treeColor, 0.8)
+			}
+		}
+		var path []pt
+		states := planner.PathStates(sol.Path)
+		for _, st := range states {
+			path = append(path, pt3(planner.Pose3D(st)))
+		}
+		c.polyline(path, pathColor, 2.5)
+		if s.Robot != nil {
+			for i, st := range states {
+				if opts.FootprintStep > 0 && i%opts.FootprintStep != 0 {
+					continue
+				}
+				q := planner.Pose3D(st)
+				for _, offset := range s.Robot {
+					c.circle(pt3(planner.Point3D{X: q.X + offset.X, Y: q.Y + offset.Y, Z: q.Z + offset.Z}), 1.2, footprintColor)
+				}
+			}
+		}
+	}
+
+	c.circle(pt3(s.Problem.Start), 4, startColor)
+}
+
+// motionStates returns the states along a motion after its start.
+func motionStates(m *planner.Motion) []planner.State {
+	if len(m.Path) == 0 {
+		return []planner.State{m.To}
+	}
+	return m.Path
+}
+
+// SVG3D draws a 3D scene seen from view as an SVG image.
+func SVG3D(w io.Writer, s Scene3D, view View, opts Options) error {
+	p, t, err := newTransform3D(s.Space, view, opts.Width)
+	if err != nil {
+		return err
+	}
+	c := newSVGCanvas(t.size())
+	draw3D(c, p, t, s, opts)
+	return c.writeTo(w)
+}
+
+// PNG3D draws a 3D scene seen from view as a PNG image.
+func PNG3D(w io.Writer, s Scene3D, view View, opts Options) error {
+	p, t, err := newTransform3D(s.Space, view, opts.Width)
+	if err != nil {
+		return err
+	}
+	c := newRasterCanvas(t.size())
+	draw3D(c, p, t, s, opts)
+	return c.writeTo(w)
+}